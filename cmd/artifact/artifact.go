@@ -16,7 +16,14 @@ func NewCmd() *cobra.Command {
 		newGetCmd(),
 		newDownloadCmd(),
 		newTagCmd(),
+		newTagsCmd(),
 		newDeleteCmd(),
+		newVerifyCmd(),
+		newChecksumCmd(),
+		newImportCmd(),
+		newDepsCmd(),
+		newFavoriteCmd(),
+		newRecentCmd(),
 	)
 
 	return cmd
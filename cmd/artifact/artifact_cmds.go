@@ -2,13 +2,24 @@ package artifact
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/parallel"
+	"cli/internal/progress"
+	"cli/internal/watch"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -22,6 +33,7 @@ func newNamespaceCmd() *cobra.Command {
 		Short: "List all artifact namespaces",
 		RunE:  runNamespaceList,
 	}
+	watch.AddFlag(listCmd)
 	cmd.AddCommand(listCmd)
 
 	return cmd
@@ -39,92 +51,176 @@ func runNamespaceList(cmd *cobra.Command, _ []string) error {
 			return a.Namespace
 		}},
 	}
-	printer := output.New(output.ParseFormat(cfg.Output), nsCol, os.Stdout)
 
-	namespaces, err := enclave.Collect(c.ListArtifactNamespaces(cmd.Context()))
-	if err != nil {
-		return fmt.Errorf("list artifact namespaces: %w", err)
-	}
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, nsCol, w)
 
-	// Deduplicate namespace names.
-	seen := map[string]bool{}
-	unique := make([]enclave.Artifact, 0, len(namespaces))
-	for _, a := range namespaces {
-		key := a.Namespace
-		if !seen[key] {
-			seen[key] = true
-			unique = append(unique, a)
+		namespaces, err := progress.Spin(
+			i18n.T("Listing artifact namespaces"),
+			func() ([]enclave.Artifact, error) {
+				return enclave.Collect(c.ListArtifactNamespaces(ctx))
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list artifact namespaces: %w", err)
+		}
+
+		// Deduplicate namespace names.
+		seen := map[string]bool{}
+		unique := make([]enclave.Artifact, 0, len(namespaces))
+		for _, a := range namespaces {
+			key := a.Namespace
+			if !seen[key] {
+				seen[key] = true
+				unique = append(unique, a)
+			}
 		}
-	}
 
-	return printer.Print(unique)
+		return printer.Print(unique)
+	})
 }
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "list <namespace>",
+	cmd := &cobra.Command{
+		Use:   "list [namespace]",
 		Short: "List artifacts in a namespace",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runList,
+		Long: "List artifacts in a namespace. With --favorites, only " +
+			"favorite artifacts are listed, and the namespace may be " +
+			"omitted to list all of them.",
+		Args:              cobra.RangeArgs(0, 1),
+		RunE:              runList,
+		ValidArgsFunction: completeArtifact(1),
 	}
+	cmd.Flags().Bool(
+		"favorites",
+		false,
+		"Only list artifacts pinned with encl artifact favorite",
+	)
+	watch.AddFlag(cmd)
+
+	return cmd
 }
 
 func runList(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ArtifactColumns,
-		os.Stdout,
-	)
 
-	artifacts, err := enclave.Collect(c.ListArtifacts(cmd.Context(), args[0]))
-	if err != nil {
-		return fmt.Errorf("list artifacts: %w", err)
+	favorites, _ := cmd.Flags().GetBool("favorites")
+	var namespaces []string
+	switch {
+	case len(args) == 1:
+		namespaces = args
+	case favorites:
+		for _, f := range cfg.Favorites {
+			ns, _, _ := strings.Cut(f, "/")
+			if !slices.Contains(namespaces, ns) {
+				namespaces = append(namespaces, ns)
+			}
+		}
+	default:
+		return errors.New("requires a namespace, or --favorites")
 	}
 
-	return printer.Print(artifacts)
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, output.ArtifactColumns, w)
+
+		artifacts, err := progress.Spin(
+			i18n.T("Listing artifacts"),
+			func() ([]enclave.Artifact, error) {
+				var all []enclave.Artifact
+				for _, ns := range namespaces {
+					list, err := enclave.Collect(c.ListArtifacts(ctx, ns))
+					if err != nil {
+						return nil, err
+					}
+					all = append(all, list...)
+				}
+
+				return all, nil
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list artifacts: %w", err)
+		}
+		if favorites {
+			artifacts = slices.DeleteFunc(artifacts, func(a enclave.Artifact) bool {
+				return !slices.Contains(cfg.Favorites, a.Namespace+"/"+a.Name)
+			})
+		}
+
+		return printer.Print(withSizes(ctx, cfg, artifacts))
+	})
 }
 
 func newVersionsCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "versions <namespace> <name>",
-		Short: "List all versions of an artifact",
-		Args:  cobra.ExactArgs(2),
-		RunE:  runVersions,
+	cmd := &cobra.Command{
+		Use:               "versions <namespace> <name>",
+		Short:             "List all versions of an artifact",
+		Args:              cobra.ExactArgs(2),
+		RunE:              runVersions,
+		ValidArgsFunction: completeArtifact(2),
 	}
+	watch.AddFlag(cmd)
+
+	return cmd
 }
 
 func runVersions(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ArtifactColumns,
-		os.Stdout,
-	)
 
-	versions, err := enclave.Collect(
-		c.ListArtifactVersions(cmd.Context(), args[0], args[1]),
-	)
-	if err != nil {
-		return fmt.Errorf("list artifact versions: %w", err)
-	}
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, output.ArtifactColumns, w)
+
+		versions, err := progress.Spin(
+			i18n.T("Listing artifact versions"),
+			func() ([]enclave.Artifact, error) {
+				return enclave.Collect(c.ListArtifactVersions(ctx, args[0], args[1]))
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list artifact versions: %w", err)
+		}
 
-	return printer.Print(versions)
+		return printer.Print(withSizes(ctx, cfg, versions))
+	})
 }
 
 func newUploadCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "upload <namespace> <name> <file>",
-		Short: "Upload an artifact",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runUpload,
+	cmd := &cobra.Command{
+		Use:               "upload <namespace> <name> <file>",
+		Short:             "Upload an artifact",
+		Args:              cobra.ExactArgs(3),
+		RunE:              runUpload,
+		ValidArgsFunction: completeArtifact(2),
 	}
+	cmd.Flags().StringArray(
+		"depends",
+		nil,
+		"Declare a dependency on <namespace>/<name>:<tag-or-hash> (repeatable)",
+	)
+
+	return cmd
 }
 
 func runUpload(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+	printer := output.FromConfig(cfg, output.ArtifactColumns, os.Stdout)
+
+	// Check declared dependencies before uploading anything.
+	specs, _ := cmd.Flags().GetStringArray("depends")
+	depTags := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		d, err := parseDependency(spec)
+		if err != nil {
+			return err
+		}
+		if _, err := resolveDependency(cmd.Context(), c, d); err != nil {
+			return err
+		}
+		depTags = append(depTags, output.DependsPrefix+d.String())
+	}
 
 	f, err := os.Open(args[2])
 	if err != nil {
@@ -132,36 +228,74 @@ func runUpload(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = f.Close() }()
 
-	result, err := c.UploadArtifact(cmd.Context(), args[0], args[1], f)
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	body := progress.NewReader(
+		f,
+		size,
+		i18n.Sprintf("Uploading %s", filepath.Base(args[2])),
+	)
+	result, err := c.UploadArtifact(cmd.Context(), args[0], args[1], body)
+	body.Done()
 	if err != nil {
 		return fmt.Errorf("upload artifact: %w", err)
 	}
-	_, err = fmt.Fprintf(
-		os.Stdout,
-		"Uploaded. Version hash: %s\n",
+	log.Debug().
+		Str("size", output.FormatSize(size, cfg.Raw)).
+		Msg("uploaded artifact")
+	touchRecent(args[0], args[1], result.VersionHash, "upload")
+	// Echo the stored version so pipelines can chain on its hash. The upload
+	// response only carries the hash; fall back to it if the lookup fails,
+	// unless dependencies must be added to the tags the version already has.
+	a, err := c.GetArtifactByHash(
+		cmd.Context(),
+		args[0],
+		args[1],
 		result.VersionHash,
 	)
+	if err != nil {
+		if len(depTags) > 0 {
+			return fmt.Errorf("record dependencies: %w", err)
+		}
+		log.Debug().Err(err).Msg("fetch uploaded artifact")
+		a = enclave.Artifact{
+			Namespace:   args[0],
+			Name:        args[1],
+			VersionHash: result.VersionHash,
+		}
+	}
+	if len(depTags) > 0 {
+		a, err = c.UpdateArtifactTagsByHash(
+			cmd.Context(),
+			args[0],
+			args[1],
+			result.VersionHash,
+			append(a.Tags, depTags...),
+		)
+		if err != nil {
+			return fmt.Errorf("record dependencies: %w", err)
+		}
+	}
 
-	return err
+	return printer.Print([]any{a})
 }
 
 func newGetCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "get <namespace> <name> <tag-or-hash>",
-		Short: "Get artifact metadata by tag or hash",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runGet,
+		Use:               "get <namespace> <name> <tag-or-hash>",
+		Short:             "Get artifact metadata by tag or hash",
+		Args:              cobra.ExactArgs(3),
+		RunE:              runGet,
+		ValidArgsFunction: completeArtifact(3),
 	}
 }
 
 func runGet(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ArtifactColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.ArtifactColumns, os.Stdout)
 
 	namespace, name, ref := args[0], args[1], args[2]
 	var a enclave.Artifact
@@ -172,26 +306,39 @@ func runGet(cmd *cobra.Command, args []string) error {
 		a, err = c.GetArtifactByTag(cmd.Context(), namespace, name, ref)
 	}
 	if err != nil {
-		return fmt.Errorf("get artifact: %w", err)
+		return fmt.Errorf(
+			"get artifact: %w",
+			suggestArtifact(cmd.Context(), c, namespace, name, ref, err),
+		)
 	}
+	touchRecent(namespace, name, ref, "get")
 
-	return printer.Print([]any{a})
+	return printer.Print(withSizes(cmd.Context(), cfg, []enclave.Artifact{a}))
 }
 
 func newDownloadCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "download <namespace> <name> <tag-or-hash>",
-		Short: "Download an artifact",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runDownload,
+		Use:               "download <namespace> <name> <tag-or-hash>",
+		Short:             "Download an artifact",
+		Args:              cobra.ExactArgs(3),
+		RunE:              runDownload,
+		ValidArgsFunction: completeArtifact(3),
 	}
 	cmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+	cmd.Flags().String(
+		"write-checksums",
+		"",
+		"Record the SHA-256 digest in a SHA256SUMS-style file "+
+			"(default: SHA256SUMS next to the output)",
+	)
+	cmd.Flags().Lookup("write-checksums").NoOptDefVal = checksumsFile
 
 	return cmd
 }
 
 func runDownload(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
 
 	namespace, name, ref := args[0], args[1], args[2]
 	var reader interface {
@@ -205,7 +352,10 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		reader, err = c.DownloadArtifactByTag(cmd.Context(), namespace, name, ref)
 	}
 	if err != nil {
-		return fmt.Errorf("download artifact: %w", err)
+		return fmt.Errorf(
+			"download artifact: %w",
+			suggestArtifact(cmd.Context(), c, namespace, name, ref, err),
+		)
 	}
 	defer func() { _ = reader.Close() }()
 
@@ -223,30 +373,62 @@ func runDownload(cmd *cobra.Command, args []string) error {
 		defer func() { _ = w.Close() }()
 	}
 
+	body := progress.NewReader(reader, 0, i18n.Sprintf("Downloading %s", name))
+	defer body.Done()
+
+	sums, _ := cmd.Flags().GetString("write-checksums")
+	var dst io.Writer = w
+	digest := sha256.New()
+	if sums != "" {
+		dst = io.MultiWriter(w, digest)
+	}
+
 	buf := make([]byte, 32*1024)
+	var total int64
 	for {
-		n, readErr := reader.Read(buf)
+		n, readErr := body.Read(buf)
 		if n > 0 {
-			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+			if _, writeErr := dst.Write(buf[:n]); writeErr != nil {
 				return fmt.Errorf("write output: %w", writeErr)
 			}
+			total += int64(n)
 		}
-		if readErr != nil {
+		if errors.Is(readErr, io.EOF) {
 			break
 		}
+		if readErr != nil {
+			return fmt.Errorf("download artifact: %w", readErr)
+		}
 	}
+	log.Debug().
+		Str("size", output.FormatSize(total, cfg.Raw)).
+		Msg("downloaded artifact")
+	touchRecent(namespace, name, ref, "download")
 
-	return nil
+	if sums == "" {
+		return nil
+	}
+	entry := name
+	if out != "" {
+		entry = filepath.Base(out)
+		if sums == checksumsFile {
+			sums = filepath.Join(filepath.Dir(out), checksumsFile)
+		}
+	}
+
+	return writeChecksum(sums, hex.EncodeToString(digest.Sum(nil)), entry)
 }
 
 func newTagCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "tag <namespace> <name> <tag-or-hash>",
-		Short: "Update tags on an artifact version",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runTag,
+		Use:               "tag <namespace> <name> <tag-or-hash>",
+		Short:             "Update tags on an artifact version",
+		Args:              cobra.ExactArgs(3),
+		RunE:              runTag,
+		ValidArgsFunction: completeArtifact(3),
 	}
-	cmd.Flags().StringSlice("tags", nil, "New tag list (replaces existing tags)")
+	cmd.Flags().
+		StringSlice("tags", nil, "New tag list (replaces existing tags, except dependencies)")
 	_ = cmd.MarkFlagRequired("tags")
 
 	return cmd
@@ -255,34 +437,39 @@ func newTagCmd() *cobra.Command {
 func runTag(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ArtifactColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.ArtifactColumns, os.Stdout)
 
 	namespace, name, ref := args[0], args[1], args[2]
 	tags, _ := cmd.Flags().GetStringSlice("tags")
+	for _, t := range tags {
+		if strings.HasPrefix(t, output.DependsPrefix) {
+			return fmt.Errorf(
+				"tag %q is reserved for dependencies; declare them with "+
+					"upload --depends",
+				t,
+			)
+		}
+	}
 
+	// The tag list replaces the existing one, except for the tags declaring
+	// dependencies, which are kept.
 	var a enclave.Artifact
 	var err error
 	if isHash(ref) {
-		a, err = c.UpdateArtifactTagsByHash(
-			cmd.Context(),
-			namespace,
-			name,
-			ref,
-			tags,
-		)
+		a, err = c.GetArtifactByHash(cmd.Context(), namespace, name, ref)
 	} else {
-		a, err = c.UpdateArtifactTagsByTag(
-			cmd.Context(),
-			namespace,
-			name,
-			ref,
-			tags,
-		)
+		a, err = c.GetArtifactByTag(cmd.Context(), namespace, name, ref)
+	}
+	if err != nil {
+		return fmt.Errorf("get artifact: %w", err)
 	}
+	a, err = c.UpdateArtifactTagsByHash(
+		cmd.Context(),
+		namespace,
+		name,
+		a.VersionHash,
+		append(tags, output.DependsTags(a.Tags)...),
+	)
 	if err != nil {
 		return fmt.Errorf("update artifact tags: %w", err)
 	}
@@ -291,36 +478,57 @@ func runTag(cmd *cobra.Command, args []string) error {
 }
 
 func newDeleteCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "delete <namespace> <name> <tag-or-hash>",
-		Short: "Delete an artifact version by tag or hash",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runDelete,
+	cmd := &cobra.Command{
+		Use:               "delete <namespace> <name> <tag-or-hash>...",
+		Short:             "Delete artifact versions by tag or hash",
+		Args:              cobra.MinimumNArgs(3),
+		RunE:              runDelete,
+		ValidArgsFunction: completeArtifact(3),
 	}
+	parallel.AddFlag(cmd)
+	cmd.Flags().
+		Bool(
+			"force",
+			false,
+			"Delete without checking whether other versions depend on it",
+		)
+
+	return cmd
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ArtifactColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.ArtifactColumns, os.Stdout)
 
-	namespace, name, ref := args[0], args[1], args[2]
-	var a enclave.Artifact
-	var err error
-	if isHash(ref) {
-		a, err = c.DeleteArtifactByHash(cmd.Context(), namespace, name, ref)
-	} else {
-		a, err = c.DeleteArtifactByTag(cmd.Context(), namespace, name, ref)
+	namespace, name := args[0], args[1]
+	if err := checkDependents(cmd, c, namespace, name, args[2:]); err != nil {
+		return err
 	}
-	if err != nil {
-		return fmt.Errorf("delete artifact: %w", err)
+	deleted, err := parallel.Map(
+		cmd,
+		i18n.T("Deleting artifact versions"),
+		args[2:],
+		func(ctx context.Context, ref string) (enclave.Artifact, error) {
+			var a enclave.Artifact
+			var err error
+			if isHash(ref) {
+				a, err = c.DeleteArtifactByHash(ctx, namespace, name, ref)
+			} else {
+				a, err = c.DeleteArtifactByTag(ctx, namespace, name, ref)
+			}
+			if err != nil {
+				return a, fmt.Errorf("delete artifact %s: %w", ref, err)
+			}
+
+			return a, nil
+		},
+	)
+	if len(deleted) > 0 {
+		err = errors.Join(printer.Print(deleted), err)
 	}
 
-	return printer.Print([]any{a})
+	return err
 }
 
 // isHash returns true if s looks like a SHA-256 hex digest (64 hex chars).
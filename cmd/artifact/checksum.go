@@ -0,0 +1,80 @@
+package artifact
+
+import (
+	"bufio"
+	"bytes"
+	"cli/internal/client"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+// checksumsFile is the default name of the file --write-checksums writes.
+const checksumsFile = "SHA256SUMS"
+
+func newChecksumCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "checksum <namespace> <name> <tag-or-hash>",
+		Short: "Print the SHA-256 digest of an artifact version",
+		Long: "Print the SHA-256 digest the registry stores for an artifact " +
+			"version, in the format of sha256sum, without downloading it.",
+		Args:              cobra.ExactArgs(3),
+		RunE:              runChecksum,
+		ValidArgsFunction: completeArtifact(3),
+	}
+}
+
+func runChecksum(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+
+	namespace, name, ref := args[0], args[1], args[2]
+	var a enclave.Artifact
+	var err error
+	if isHash(ref) {
+		a, err = c.GetArtifactByHash(cmd.Context(), namespace, name, ref)
+	} else {
+		a, err = c.GetArtifactByTag(cmd.Context(), namespace, name, ref)
+	}
+	if err != nil {
+		return fmt.Errorf("get artifact: %w", err)
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s  %s\n", a.VersionHash, name)
+
+	return err
+}
+
+// writeChecksum records digest for file in the SHA256SUMS-style file at
+// path. An existing line for the same file is replaced; other lines are
+// kept, so several downloads can share one checksums file.
+func writeChecksum(path, digest, file string) error {
+	path = filepath.Clean(path)
+	var buf bytes.Buffer
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read checksums: %w", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if _, name, ok := strings.Cut(line, "  "); ok && name == file {
+			continue
+		}
+		buf.WriteString(line + "\n")
+	}
+	fmt.Fprintf(&buf, "%s  %s\n", digest, file)
+
+	if err := os.WriteFile(
+		path,
+		buf.Bytes(),
+		0o644,
+	); err != nil { // #nosec G306 -- checksums are public
+		return fmt.Errorf("write checksums: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package artifact
+
+import (
+	"cli/internal/client"
+	"cli/internal/config"
+	"cli/internal/recent"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds how long completion waits for the server before
+// settling for local suggestions.
+const completionTimeout = 2 * time.Second
+
+// completeArtifact returns a completion function for commands whose first
+// n arguments are <namespace> <name> <tag-or-hash>. It suggests recently
+// used artifacts, most recent first, followed by those on the server, and
+// falls back to file completion after them.
+//
+// When the server is unreachable, the suggestions come from recent use and,
+// with the response cache enabled, from cached listings of any age, so
+// completion keeps working offline.
+func completeArtifact(n int) cobra.CompletionFunc {
+	return func(
+		cmd *cobra.Command,
+		args []string,
+		_ string,
+	) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= n {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		var suggestions []string
+		add := func(s string) {
+			if s != "" && !slices.Contains(suggestions, s) {
+				suggestions = append(suggestions, s)
+			}
+		}
+		entries, _ := recent.Read()
+		for i := range entries {
+			e := &entries[i]
+			switch len(args) {
+			case 0:
+				add(e.Namespace)
+			case 1:
+				if e.Namespace == args[0] {
+					add(e.Name)
+				}
+			default:
+				if e.Namespace == args[0] && e.Name == args[1] {
+					add(e.Ref)
+				}
+			}
+		}
+		for _, s := range serverCandidates(cmd, args) {
+			add(s)
+		}
+
+		return suggestions, cobra.ShellCompDirectiveNoFileComp |
+			cobra.ShellCompDirectiveKeepOrder
+	}
+}
+
+// serverCandidates lists the namespaces, names, or tags and hashes for the
+// next argument from the server. Completion runs without the usual command
+// setup, so it builds its own client. Any failure yields no candidates.
+func serverCandidates(cmd *cobra.Command, args []string) []string {
+	cfg, err := config.Load(cmd.Root().PersistentFlags())
+	if err != nil {
+		return nil
+	}
+	c, err := client.New(cfg)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(
+		client.WithStaleFallback(context.Background()),
+		completionTimeout,
+	)
+	defer cancel()
+
+	var artifacts []enclave.Artifact
+	switch len(args) {
+	case 0:
+		artifacts, err = enclave.Collect(c.ListArtifactNamespaces(ctx))
+	case 1:
+		artifacts, err = enclave.Collect(c.ListArtifacts(ctx, args[0]))
+	default:
+		artifacts, err = enclave.Collect(
+			c.ListArtifactVersions(ctx, args[0], args[1]),
+		)
+	}
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	for _, a := range artifacts {
+		switch len(args) {
+		case 0:
+			out = append(out, a.Namespace)
+		case 1:
+			out = append(out, a.Name)
+		default:
+			out = append(out, a.Tags...)
+			out = append(out, a.VersionHash)
+		}
+	}
+
+	return out
+}
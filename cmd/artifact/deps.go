@@ -0,0 +1,350 @@
+package artifact
+
+import (
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/output"
+	"cli/internal/progress"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+// dependency is a declared dependency on another artifact version.
+type dependency struct {
+	Namespace string
+	Name      string
+	// Ref is a tag or version hash.
+	Ref string
+}
+
+// parseDependency parses <namespace>/<name>:<tag-or-hash>.
+func parseDependency(s string) (dependency, error) {
+	path, ref, ok := strings.Cut(s, ":")
+	namespace, name, ok2 := strings.Cut(path, "/")
+	if !ok || !ok2 || namespace == "" || name == "" || ref == "" {
+		return dependency{}, fmt.Errorf(
+			"invalid dependency %q, want <namespace>/<name>:<tag-or-hash>",
+			s,
+		)
+	}
+
+	return dependency{Namespace: namespace, Name: name, Ref: ref}, nil
+}
+
+// String formats d as <namespace>/<name>:<ref>.
+func (d dependency) String() string {
+	return d.Namespace + "/" + d.Name + ":" + d.Ref
+}
+
+// dependencies returns the dependencies declared in a's tags.
+func dependencies(a *enclave.Artifact) []dependency {
+	var deps []dependency
+	for _, t := range output.DependsTags(a.Tags) {
+		s := strings.TrimPrefix(t, output.DependsPrefix)
+		if d, err := parseDependency(s); err == nil {
+			deps = append(deps, d)
+		}
+	}
+
+	return deps
+}
+
+// matches reports whether d refers to the version a.
+func (d dependency) matches(a *enclave.Artifact) bool {
+	return d.Namespace == a.Namespace && d.Name == a.Name &&
+		(d.Ref == a.VersionHash || slices.Contains(a.Tags, d.Ref))
+}
+
+func newDepsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deps <namespace> <name> <tag-or-hash>",
+		Short: "Show the dependencies of an artifact version",
+		Long: "Show the artifact versions a version depends on, as declared " +
+			"with upload --depends. --tree follows dependencies transitively; " +
+			"--reverse lists the versions that depend on this one instead, " +
+			"which is what breaks when it is deleted.",
+		Args:              cobra.ExactArgs(3),
+		RunE:              runDeps,
+		ValidArgsFunction: completeArtifact(3),
+	}
+	cmd.Flags().Bool("tree", false, "Print transitive dependencies as a tree")
+	cmd.Flags().
+		Bool("reverse", false, "List the versions that depend on this one")
+
+	return cmd
+}
+
+func runDeps(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+	printer := output.FromConfig(cfg, output.ArtifactColumns, os.Stdout)
+
+	root := dependency{Namespace: args[0], Name: args[1], Ref: args[2]}
+	a, err := resolveDependency(cmd.Context(), c, root)
+	if err != nil {
+		return err
+	}
+
+	if reverse, _ := cmd.Flags().GetBool("reverse"); reverse {
+		dependents, err := progress.Spin(
+			i18n.T("Searching dependents"),
+			func() ([]enclave.Artifact, error) {
+				return findDependents(cmd.Context(), c, &a)
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		return printer.Print(dependents)
+	}
+
+	if tree, _ := cmd.Flags().GetBool("tree"); tree {
+		return printTree(cmd.Context(), c, cmd.OutOrStdout(), root, &a)
+	}
+
+	deps := dependencies(&a)
+	resolved := make([]enclave.Artifact, 0, len(deps))
+	for _, d := range deps {
+		dep, err := resolveDependency(cmd.Context(), c, d)
+		if err != nil {
+			return err
+		}
+		resolved = append(resolved, dep)
+	}
+
+	return printer.Print(resolved)
+}
+
+// resolveDependency fetches the version d refers to.
+func resolveDependency(
+	ctx context.Context,
+	c *enclave.Client,
+	d dependency,
+) (enclave.Artifact, error) {
+	var a enclave.Artifact
+	var err error
+	if isHash(d.Ref) {
+		a, err = c.GetArtifactByHash(ctx, d.Namespace, d.Name, d.Ref)
+	} else {
+		a, err = c.GetArtifactByTag(ctx, d.Namespace, d.Name, d.Ref)
+	}
+	if err != nil {
+		return a, fmt.Errorf("get artifact %s: %w", d, err)
+	}
+
+	return a, nil
+}
+
+// printTree writes the transitive dependencies of a, indented by depth.
+// Dependencies already printed higher up the current branch are marked as
+// cycles instead of being followed again.
+func printTree(
+	ctx context.Context,
+	c *enclave.Client,
+	w io.Writer,
+	d dependency,
+	a *enclave.Artifact,
+) error {
+	var walk func(d dependency, a *enclave.Artifact, depth int, path []string) error
+	walk = func(d dependency, a *enclave.Artifact, depth int, path []string) error {
+		indent := strings.Repeat("  ", depth)
+		label := d.Namespace + "/" + d.Name + ":" + d.Ref
+		if isHash(d.Ref) {
+			label = d.Namespace + "/" + d.Name + ":" + shortHash(d.Ref)
+		} else {
+			label += "  " + shortHash(a.VersionHash)
+		}
+		if slices.Contains(path, a.VersionHash) {
+			_, err := fmt.Fprintf(w, "%s%s (cycle)\n", indent, label)
+
+			return err
+		}
+		if _, err := fmt.Fprintln(w, indent+label); err != nil {
+			return err
+		}
+		path = append(path, a.VersionHash)
+		for _, dep := range dependencies(a) {
+			child, err := resolveDependency(ctx, c, dep)
+			if err != nil {
+				if _, err := fmt.Fprintf(
+					w,
+					"%s  %s (%v)\n",
+					indent,
+					dep,
+					err,
+				); err != nil {
+					return err
+				}
+
+				continue
+			}
+			if err := walk(dep, &child, depth+1, path); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return walk(d, a, 0, nil)
+}
+
+// findDependents scans every artifact version for dependencies on a.
+func findDependents(
+	ctx context.Context,
+	c *enclave.Client,
+	a *enclave.Artifact,
+) ([]enclave.Artifact, error) {
+	versions, err := allVersions(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return dependentsOf(versions, a), nil
+}
+
+// dependentsOf returns the versions that declare a dependency on a.
+func dependentsOf(
+	versions []enclave.Artifact,
+	a *enclave.Artifact,
+) []enclave.Artifact {
+	var dependents []enclave.Artifact
+	for i := range versions {
+		if slices.ContainsFunc(
+			dependencies(&versions[i]),
+			func(d dependency) bool { return d.matches(a) },
+		) {
+			dependents = append(dependents, versions[i])
+		}
+	}
+
+	return dependents
+}
+
+// allVersions lists every version of every artifact.
+func allVersions(
+	ctx context.Context,
+	c *enclave.Client,
+) ([]enclave.Artifact, error) {
+	namespaces, err := enclave.Collect(c.ListArtifactNamespaces(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list namespaces: %w", err)
+	}
+
+	return allVersionsOf(ctx, c, namespaces)
+}
+
+// allVersionsOf lists every version of the artifacts in namespaces, given
+// as the namespace entries returned by ListArtifactNamespaces.
+func allVersionsOf(
+	ctx context.Context,
+	c *enclave.Client,
+	namespaces []enclave.Artifact,
+) ([]enclave.Artifact, error) {
+	var all []enclave.Artifact
+	for _, ns := range namespaces {
+		artifacts, err := enclave.Collect(c.ListArtifacts(ctx, ns.Namespace))
+		if err != nil {
+			return nil, fmt.Errorf("list artifacts: %w", err)
+		}
+		for _, art := range artifacts {
+			versions, err := enclave.Collect(
+				c.ListArtifactVersions(ctx, art.Namespace, art.Name),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("list artifact versions: %w", err)
+			}
+			all = append(all, versions...)
+		}
+	}
+
+	return all, nil
+}
+
+// checkDependents lists the versions that depend on the versions of
+// namespace/name about to be deleted on the command's error output, and
+// fails when there are any. The API cannot search tags, so this lists every
+// version in the registry; --force skips the check. Refs that do not
+// resolve are left for the delete to report.
+func checkDependents(
+	cmd *cobra.Command,
+	c *enclave.Client,
+	namespace, name string,
+	refs []string,
+) error {
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		return nil
+	}
+	ctx := cmd.Context()
+	targets := make([]enclave.Artifact, 0, len(refs))
+	labels := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		d := dependency{Namespace: namespace, Name: name, Ref: ref}
+		if a, err := resolveDependency(ctx, c, d); err == nil {
+			targets = append(targets, a)
+			labels = append(labels, d.String())
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	versions, err := progress.Spin(
+		i18n.T("Searching dependents"),
+		func() ([]enclave.Artifact, error) { return allVersions(ctx, c) },
+	)
+	if err != nil {
+		return err
+	}
+	// Versions deleted together do not hold each other back.
+	versions = slices.DeleteFunc(versions, func(v enclave.Artifact) bool {
+		return slices.ContainsFunc(targets, func(t enclave.Artifact) bool {
+			return t.Namespace == v.Namespace && t.Name == v.Name &&
+				t.VersionHash == v.VersionHash
+		})
+	})
+
+	w := cmd.ErrOrStderr()
+	referenced := 0
+	for i := range targets {
+		dependents := dependentsOf(versions, &targets[i])
+		if len(dependents) == 0 {
+			continue
+		}
+		referenced++
+		_, _ = fmt.Fprintf(w, i18n.T("%s is still referenced by:\n"), labels[i])
+		for _, v := range dependents {
+			_, _ = fmt.Fprintf(
+				w,
+				"  %s/%s:%s\n",
+				v.Namespace,
+				v.Name,
+				shortHash(v.VersionHash),
+			)
+		}
+	}
+	if referenced == 0 {
+		return nil
+	}
+
+	return errors.New(i18n.Sprintf(
+		"%d of the given objects are still in use (use --force to delete anyway)",
+		referenced,
+	))
+}
+
+func shortHash(h string) string {
+	if len(h) > 16 {
+		return h[:16]
+	}
+
+	return h
+}
@@ -0,0 +1,131 @@
+package artifact
+
+import (
+	ic "cli/internal/config"
+	"cli/internal/i18n"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// favoritesKey is the config key holding favorite artifacts.
+const favoritesKey = "favorites"
+
+func newFavoriteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "favorite",
+		Short: "Pin frequently used artifacts",
+		Long: "Pin frequently used artifacts by <namespace>/<name>. " +
+			"Favorites are stored in the config file; list them with " +
+			"encl artifact list --favorites.",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "add <namespace>/<name>...",
+			Short: "Add artifacts to the favorites",
+			Args:  cobra.MinimumNArgs(1),
+			RunE:  runFavoriteAdd,
+		},
+		&cobra.Command{
+			Use:   "remove <namespace>/<name>...",
+			Short: "Remove artifacts from the favorites",
+			Args:  cobra.MinimumNArgs(1),
+			RunE:  runFavoriteRemove,
+		},
+		&cobra.Command{
+			Use:   "list",
+			Short: "List favorite artifacts",
+			Args:  cobra.NoArgs,
+			RunE:  runFavoriteList,
+		},
+	)
+
+	return cmd
+}
+
+func runFavoriteAdd(cmd *cobra.Command, args []string) error {
+	favorites, err := loadFavorites(cmd)
+	if err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if err := checkFavorite(arg); err != nil {
+			return err
+		}
+		if !slices.Contains(favorites, arg) {
+			favorites = append(favorites, arg)
+		}
+	}
+	slices.Sort(favorites)
+
+	return saveFavorites(cmd, favorites)
+}
+
+func runFavoriteRemove(cmd *cobra.Command, args []string) error {
+	favorites, err := loadFavorites(cmd)
+	if err != nil {
+		return err
+	}
+	for _, arg := range args {
+		i := slices.Index(favorites, arg)
+		if i < 0 {
+			return fmt.Errorf("%s is not a favorite", arg)
+		}
+		favorites = slices.Delete(favorites, i, i+1)
+	}
+
+	return saveFavorites(cmd, favorites)
+}
+
+func runFavoriteList(cmd *cobra.Command, _ []string) error {
+	favorites, err := loadFavorites(cmd)
+	if err != nil {
+		return err
+	}
+	for _, f := range favorites {
+		if _, err := fmt.Fprintln(cmd.OutOrStdout(), f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkFavorite validates a <namespace>/<name> favorite.
+func checkFavorite(s string) error {
+	namespace, name, ok := strings.Cut(s, "/")
+	if !ok || namespace == "" || name == "" ||
+		strings.ContainsAny(name, "/@:") {
+		return fmt.Errorf("invalid artifact %q, want <namespace>/<name>", s)
+	}
+
+	return nil
+}
+
+// loadFavorites returns the favorites from the resolved configuration.
+func loadFavorites(cmd *cobra.Command) ([]string, error) {
+	cfg, err := ic.Load(cmd.Root().PersistentFlags())
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	return cfg.Favorites, nil
+}
+
+// saveFavorites stores favorites in the config file.
+func saveFavorites(cmd *cobra.Command, favorites []string) error {
+	path, err := ic.SetList(favoritesKey, favorites)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(
+		cmd.OutOrStdout(),
+		i18n.T("Saved %s to %s\n"),
+		favoritesKey,
+		path,
+	)
+
+	return err
+}
@@ -0,0 +1,191 @@
+package artifact
+
+import (
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/oci"
+	"cli/internal/output"
+	"cli/internal/progress"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <oci://registry/repository[:tag|@digest]> <namespace> <name>",
+		Short: "Import the wasm layer of an OCI artifact",
+		Long: "Pull the wasm layer of an OCI artifact and upload it as a new " +
+			"artifact version. The version is tagged with its origin, e.g. " +
+			"oci:ghcr.io/acme/filter@sha256:…, so it can be traced back to " +
+			"the image it came from. Registry logins are taken from the " +
+			"docker configuration.",
+		Args: cobra.ExactArgs(3),
+		RunE: runImport,
+	}
+	cmd.Flags().
+		StringSlice("tags", nil, "Additional tags for the imported version")
+
+	return cmd
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+	printer := output.FromConfig(cfg, output.ArtifactColumns, os.Stdout)
+
+	ref, err := oci.ParseRef(args[0])
+	if err != nil {
+		return err
+	}
+	namespace, name := args[1], args[2]
+	registry := oci.NewClient(client.ExternalTransport(cfg))
+
+	manifest, err := registry.Manifest(cmd.Context(), ref)
+	if err != nil {
+		return err
+	}
+	layer, err := wasmLayer(manifest)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ref, err)
+	}
+
+	f, err := pullLayer(cmd, registry, ref, layer)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	body := progress.NewReader(
+		f,
+		layer.Size,
+		i18n.Sprintf("Uploading %s", name),
+	)
+	result, err := c.UploadArtifact(cmd.Context(), namespace, name, body)
+	body.Done()
+	// Version hashes are SHA-256 digests of the content, so a layer that was
+	// imported before is found under its own digest.
+	_, hash, _ := strings.Cut(layer.Digest, ":")
+	switch {
+	case err == nil:
+		hash = result.VersionHash
+	case errors.Is(err, enclave.ErrConflict):
+		log.Debug().Str("hash", hash).Msg("layer already uploaded")
+	default:
+		return fmt.Errorf("upload artifact: %w", err)
+	}
+
+	// Tags replace the existing list, so keep those of an identical
+	// version uploaded earlier.
+	a, err := c.GetArtifactByHash(cmd.Context(), namespace, name, hash)
+	if err != nil {
+		return fmt.Errorf("get artifact: %w", err)
+	}
+	if manifest.Digest != "" {
+		ref.Reference = manifest.Digest
+	}
+	extra, _ := cmd.Flags().GetStringSlice("tags")
+	tags := a.Tags
+	for _, t := range append(extra, "oci:"+ref.String()) {
+		if !slices.Contains(tags, t) {
+			tags = append(tags, t)
+		}
+	}
+	a, err = c.UpdateArtifactTagsByHash(
+		cmd.Context(),
+		namespace,
+		name,
+		hash,
+		tags,
+	)
+	if err != nil {
+		return fmt.Errorf("update artifact tags: %w", err)
+	}
+
+	return printer.Print([]any{a})
+}
+
+// wasmLayer returns the single wasm layer of m.
+func wasmLayer(m *oci.Manifest) (oci.Descriptor, error) {
+	var found []oci.Descriptor
+	types := make([]string, 0, len(m.Layers))
+	for _, l := range m.Layers {
+		types = append(types, l.MediaType)
+		if strings.Contains(l.MediaType, "wasm") {
+			found = append(found, l)
+		}
+	}
+	switch len(found) {
+	case 1:
+		return found[0], nil
+	case 0:
+		return oci.Descriptor{}, fmt.Errorf(
+			"no wasm layer (layer media types: %s)",
+			strings.Join(types, ", "),
+		)
+	default:
+		return oci.Descriptor{}, fmt.Errorf(
+			"%d wasm layers, expected one",
+			len(found),
+		)
+	}
+}
+
+// pullLayer downloads layer into a temporary file and checks its digest. The
+// returned file is positioned at the start.
+func pullLayer(
+	cmd *cobra.Command,
+	registry *oci.Client,
+	ref oci.Ref,
+	layer oci.Descriptor,
+) (*os.File, error) {
+	algo, want, _ := strings.Cut(layer.Digest, ":")
+	if algo != "sha256" {
+		return nil, fmt.Errorf("unsupported layer digest %q", layer.Digest)
+	}
+
+	blob, err := registry.Blob(cmd.Context(), ref, layer.Digest)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = blob.Close() }()
+
+	f, err := os.CreateTemp("", "encl-import-*.wasm")
+	if err != nil {
+		return nil, fmt.Errorf("create temp file: %w", err)
+	}
+	h := sha256.New()
+	body := progress.NewReader(
+		blob,
+		layer.Size,
+		i18n.Sprintf("Downloading %s", ref.Repository),
+	)
+	_, err = io.Copy(io.MultiWriter(f, h), body)
+	body.Done()
+	if err == nil && hex.EncodeToString(h.Sum(nil)) != want {
+		err = fmt.Errorf("layer digest mismatch, expected %s", layer.Digest)
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+
+		return nil, fmt.Errorf("pull %s: %w", ref, err)
+	}
+
+	return f, nil
+}
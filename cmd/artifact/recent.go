@@ -0,0 +1,55 @@
+package artifact
+
+import (
+	"cli/internal/config"
+	"cli/internal/output"
+	"cli/internal/recent"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func newRecentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recent",
+		Short: "List recently used artifacts",
+		Long: "List the artifacts uploaded, downloaded, or looked up from " +
+			"this machine, most recent first. Shell completion suggests " +
+			"them first, too.",
+		Args: cobra.NoArgs,
+		RunE: runRecent,
+	}
+	cmd.Flags().
+		IntP("limit", "n", 20, "Show at most this many artifacts (0 for all)")
+
+	return cmd
+}
+
+func runRecent(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(cmd.Root().PersistentFlags())
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	printer := output.FromConfig(cfg, output.RecentColumns, os.Stdout)
+
+	entries, err := recent.Read()
+	if err != nil {
+		return err
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return printer.Print(entries)
+}
+
+// touchRecent records the use of an artifact for encl artifact recent.
+// Failing to record it does not fail the command.
+func touchRecent(namespace, name, ref, action string) {
+	if err := recent.Touch(namespace, name, ref, action); err != nil {
+		log.Debug().Err(err).Msg("record recent artifact")
+	}
+}
@@ -0,0 +1,59 @@
+package artifact
+
+import (
+	"cli/internal/client"
+	"cli/internal/config"
+	"cli/internal/output"
+	"context"
+	"sync"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/rs/zerolog/log"
+)
+
+// sizeLookups bounds the concurrent size requests for a listing.
+const sizeLookups = 8
+
+// withSizes looks up the stored size of each artifact version when the
+// output uses the SIZE column, and returns artifacts unchanged otherwise.
+// Sizes that cannot be determined are left unknown rather than failing the
+// listing.
+func withSizes(
+	ctx context.Context,
+	cfg *config.Config,
+	artifacts []enclave.Artifact,
+) any {
+	if !output.Uses(cfg, output.ArtifactColumns, "size") {
+		return artifacts
+	}
+	sized := make([]output.SizedArtifact, len(artifacts))
+	sem := make(chan struct{}, sizeLookups)
+	var wg sync.WaitGroup
+	for i := range artifacts {
+		a := &artifacts[i]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			size, err := client.ArtifactSize(
+				ctx,
+				cfg,
+				a.Namespace,
+				a.Name,
+				a.VersionHash,
+			)
+			if err != nil {
+				log.Debug().
+					Err(err).
+					Str("artifact", a.Namespace+"/"+a.Name+"@"+a.VersionHash).
+					Msg("look up artifact size")
+				size = -1
+			}
+			sized[i] = output.SizedArtifact{Artifact: *a, Size: size}
+		}()
+	}
+	wg.Wait()
+
+	return sized
+}
@@ -0,0 +1,81 @@
+package artifact
+
+import (
+	"cli/internal/picker"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+)
+
+// suggestArtifact adds the closest existing namespace, name, or tag to err
+// when the artifact namespace/name:ref was not found. The first part that
+// does not exist is the one corrected.
+func suggestArtifact(
+	ctx context.Context,
+	c *enclave.Client,
+	namespace, name, ref string,
+	err error,
+) error {
+	if !errors.Is(err, enclave.ErrNotFound) {
+		return err
+	}
+	list := func(
+		seq func(ctx context.Context) ([]enclave.Artifact, error),
+		field func(enclave.Artifact) []string,
+	) picker.Source {
+		return picker.Source{List: func(ctx context.Context) ([]string, error) {
+			artifacts, err := seq(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for _, a := range artifacts {
+				for _, n := range field(a) {
+					if !slices.Contains(names, n) {
+						names = append(names, n)
+					}
+				}
+			}
+
+			return names, nil
+		}}
+	}
+	namespaces := list(
+		func(ctx context.Context) ([]enclave.Artifact, error) {
+			return enclave.Collect(c.ListArtifactNamespaces(ctx))
+		},
+		func(a enclave.Artifact) []string { return []string{a.Namespace} },
+	)
+	names := list(
+		func(ctx context.Context) ([]enclave.Artifact, error) {
+			return enclave.Collect(c.ListArtifacts(ctx, namespace))
+		},
+		func(a enclave.Artifact) []string { return []string{a.Name} },
+	)
+	tags := list(
+		func(ctx context.Context) ([]enclave.Artifact, error) {
+			return enclave.Collect(c.ListArtifactVersions(ctx, namespace, name))
+		},
+		func(a enclave.Artifact) []string { return a.Tags },
+	)
+
+	for _, step := range []struct {
+		src   picker.Source
+		value string
+	}{{namespaces, namespace}, {names, name}} {
+		existing, listErr := step.src.List(ctx)
+		if listErr != nil {
+			return err
+		}
+		if !slices.Contains(existing, step.value) {
+			return picker.Suggest(ctx, step.src, step.value, err)
+		}
+	}
+	if isHash(ref) {
+		return err
+	}
+
+	return picker.Suggest(ctx, tags, ref, err)
+}
@@ -0,0 +1,98 @@
+package artifact
+
+import (
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/output"
+	"cli/internal/progress"
+	"cmp"
+	"context"
+	"os"
+	"slices"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+func newTagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "List the tags in use across the registry",
+		Long: "List every tag used by artifact versions in the registry, " +
+			"with the number of versions and artifacts carrying it, most " +
+			"used first. -o wide lists the artifacts. Dependency tags added " +
+			"with upload --depends are left out.",
+		Example: "  encl artifact tags\n" +
+			"  encl artifact tags --namespace plugins -o wide --filter tag=latest",
+		Args: cobra.NoArgs,
+		RunE: runTags,
+	}
+	cmd.Flags().String("namespace", "", "Only count artifacts in this namespace")
+
+	return cmd
+}
+
+func runTags(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+	printer := output.FromConfig(cfg, output.TagColumns, os.Stdout)
+
+	namespace, _ := cmd.Flags().GetString("namespace")
+	versions, err := progress.Spin(
+		i18n.T("Collecting tags"),
+		func() ([]enclave.Artifact, error) {
+			if namespace != "" {
+				return namespaceVersions(cmd.Context(), c, namespace)
+			}
+
+			return allVersions(cmd.Context(), c)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return printer.Print(tagUsage(versions))
+}
+
+// namespaceVersions lists every version of the artifacts in namespace.
+func namespaceVersions(
+	ctx context.Context,
+	c *enclave.Client,
+	namespace string,
+) ([]enclave.Artifact, error) {
+	return allVersionsOf(ctx, c, []enclave.Artifact{{Namespace: namespace}})
+}
+
+// tagUsage counts the versions and artifacts of every tag, most used first.
+func tagUsage(versions []enclave.Artifact) []output.TagUsage {
+	byTag := map[string]*output.TagUsage{}
+	for _, v := range versions {
+		artifact := v.Namespace + "/" + v.Name
+		for _, t := range output.UserTags(v.Tags) {
+			u, ok := byTag[t]
+			if !ok {
+				u = &output.TagUsage{Tag: t}
+				byTag[t] = u
+			}
+			u.Versions++
+			if !slices.Contains(u.Artifacts, artifact) {
+				u.Artifacts = append(u.Artifacts, artifact)
+			}
+		}
+	}
+
+	usage := make([]output.TagUsage, 0, len(byTag))
+	for _, u := range byTag {
+		slices.Sort(u.Artifacts)
+		usage = append(usage, *u)
+	}
+	slices.SortFunc(usage, func(a, b output.TagUsage) int {
+		return cmp.Or(
+			cmp.Compare(b.Versions, a.Versions),
+			cmp.Compare(a.Tag, b.Tag),
+		)
+	})
+
+	return usage
+}
@@ -0,0 +1,88 @@
+package artifact
+
+import (
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <namespace> <name> <tag-or-hash> <file>",
+		Short: "Check that a local file matches an artifact version",
+		Long: "Compute the SHA-256 digest of a local file and compare it to " +
+			"the version hash the registry stores for the artifact version. " +
+			"Exits non-zero when they differ.",
+		Args:              cobra.ExactArgs(4),
+		RunE:              runVerify,
+		ValidArgsFunction: completeArtifact(3),
+	}
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+
+	namespace, name, ref, file := args[0], args[1], args[2], args[3]
+	local, err := fileDigest(file)
+	if err != nil {
+		return err
+	}
+
+	var a enclave.Artifact
+	if isHash(ref) {
+		a, err = c.GetArtifactByHash(cmd.Context(), namespace, name, ref)
+	} else {
+		a, err = c.GetArtifactByTag(cmd.Context(), namespace, name, ref)
+	}
+	if err != nil {
+		return fmt.Errorf("get artifact: %w", err)
+	}
+
+	if local != a.VersionHash {
+		return fmt.Errorf(
+			"%s does not match %s/%s@%s: local sha256 %s, registry %s",
+			file,
+			namespace,
+			name,
+			ref,
+			local,
+			a.VersionHash,
+		)
+	}
+	_, err = fmt.Fprintf(
+		cmd.OutOrStdout(),
+		i18n.T("%s matches %s/%s@%s (sha256 %s)\n"),
+		file,
+		namespace,
+		name,
+		ref,
+		local,
+	)
+
+	return err
+}
+
+// fileDigest returns the hex SHA-256 digest of the file at path, the same
+// digest the registry uses as version hash.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
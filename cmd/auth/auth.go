@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the "auth" command group.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the credentials the CLI signs in with",
+	}
+	cmd.AddCommand(
+		newRotateCmd(),
+	)
+
+	return cmd
+}
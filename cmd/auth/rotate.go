@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"cli/internal/ci"
+	"cli/internal/client"
+	"cli/internal/config"
+	"cli/internal/i18n"
+	"cli/internal/password"
+	"errors"
+	"fmt"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+func newRotateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the current user's password with a generated one",
+		Long: "Generate a random password that follows password_policy, set " +
+			"it for the current user, and sign in with it to verify it " +
+			"works. If the old password is stored in the config file, the " +
+			"new one replaces it there; otherwise it is printed so it can " +
+			"be stored where the old one is kept.\n\n" +
+			"If the new password cannot be verified, the old one is " +
+			"restored.",
+		Args: cobra.NoArgs,
+		RunE: runRotate,
+	}
+	cmd.Flags().Int("length", 24, "Length of the generated password")
+
+	return cmd
+}
+
+func runRotate(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+	if cfg.Replay != "" {
+		return errors.New("cannot rotate the password while replaying")
+	}
+	key, err := passwordKey(cfg)
+	if err != nil {
+		return err
+	}
+
+	length, _ := cmd.Flags().GetInt("length")
+	pw, err := password.Generate(&cfg.PasswordPolicy, length)
+	if err != nil {
+		return err
+	}
+	ci.Mask(pw)
+	if _, err := c.UpdateMe(
+		cmd.Context(),
+		enclave.WithPassword(pw),
+	); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+
+	// The new password is verified before the old one is replaced in the
+	// config file, so a failed rotation never leaves the config without a
+	// working password.
+	if err := verify(cmd, cfg, pw); err != nil {
+		return restore(cmd, cfg, pw, err)
+	}
+
+	if key == "" {
+		_, err := fmt.Fprintf(
+			cmd.OutOrStdout(),
+			i18n.T("Password rotated. It is not stored in the config "+
+				"file; update it where the old one is kept:\n%s\n"),
+			pw,
+		)
+
+		return err
+	}
+	path, err := config.Set(key, pw)
+	if err != nil {
+		// The password is printed rather than put into the error, which
+		// ends up in history, summaries and hooks.
+		_, _ = fmt.Fprintf(
+			cmd.ErrOrStderr(),
+			i18n.T("The new password could not be saved; it is:\n%s\n"),
+			pw,
+		)
+
+		return fmt.Errorf("save new password: %w", err)
+	}
+	_, err = fmt.Fprintf(
+		cmd.OutOrStdout(),
+		i18n.T("Password rotated and saved to %s\n"),
+		path,
+	)
+
+	return err
+}
+
+// passwordKey returns the config file key holding the password in use, or
+// "" if it comes from somewhere else, such as a flag, the environment, or a
+// credential helper.
+func passwordKey(cfg *config.Config) (string, error) {
+	values, err := config.FileValues()
+	if err != nil {
+		return "", err
+	}
+	keys := []string{"password"}
+	if cfg.Profile != "" {
+		keys = []string{"profiles." + cfg.Profile + ".password", "password"}
+	}
+	for _, key := range keys {
+		if v, ok := values[key]; ok {
+			if v == cfg.Password {
+				return key, nil
+			}
+
+			// A value overridden by a later source is not the one in use.
+			return "", nil
+		}
+	}
+
+	return "", nil
+}
+
+// restore sets the old password again after the new one pw failed to
+// verify with verifyErr. The server already expects pw at this point, so
+// the change is made signed in with it.
+func restore(
+	cmd *cobra.Command,
+	cfg *config.Config,
+	pw string,
+	verifyErr error,
+) error {
+	c, err := enclave.New(cfg.APIURL, cfg.Username, pw)
+	if err == nil {
+		_, err = c.UpdateMe(cmd.Context(), enclave.WithPassword(cfg.Password))
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(
+			cmd.ErrOrStderr(),
+			i18n.T("The old password could not be restored; the "+
+				"password may now be:\n%s\n"),
+			pw,
+		)
+
+		return fmt.Errorf(
+			"verify new password: %w; restoring the old password "+
+				"failed too: %w",
+			verifyErr,
+			err,
+		)
+	}
+
+	return fmt.Errorf(
+		"verify new password: %w; the old password was restored",
+		verifyErr,
+	)
+}
+
+// verify signs in with the new password and fetches the current user.
+func verify(cmd *cobra.Command, cfg *config.Config, pw string) error {
+	c, err := enclave.New(cfg.APIURL, cfg.Username, pw)
+	if err != nil {
+		return err
+	}
+	_, err = c.GetMe(cmd.Context())
+
+	return err
+}
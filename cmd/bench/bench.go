@@ -0,0 +1,262 @@
+// Package bench implements encl bench, which measures API latency and
+// artifact transfer throughput against the configured server.
+package bench
+
+import (
+	"bytes"
+	"cli/internal/bench"
+	"cli/internal/client"
+	"cli/internal/output"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the "bench" command group.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure API latency and transfer throughput",
+		Long: "Measure request latency and artifact transfer throughput " +
+			"against the configured server. Comparing api with upload and " +
+			"download helps tell network, server, and CLI slowness apart. " +
+			"Responses are never served from the local cache.",
+	}
+	cmd.PersistentFlags().IntP("requests", "n", 50, "Number of requests")
+	cmd.PersistentFlags().IntP("concurrency", "c", 4, "Concurrent requests")
+	cmd.AddCommand(
+		newAPICmd(),
+		newUploadCmd(),
+		newDownloadCmd(),
+	)
+
+	return cmd
+}
+
+func newAPICmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "api",
+		Short: "Measure the latency of a lightweight API call",
+		Long: "Measure the latency of fetching the current user, the " +
+			"cheapest authenticated call.",
+		Args: cobra.NoArgs,
+		RunE: runAPI,
+	}
+}
+
+func newUploadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upload",
+		Short: "Measure artifact upload throughput",
+		Long: "Upload random payloads as versions of a scratch artifact and " +
+			"delete them again afterwards.",
+		Args: cobra.NoArgs,
+		RunE: runUpload,
+	}
+	addTransferFlags(cmd)
+
+	return cmd
+}
+
+func newDownloadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Measure artifact download throughput",
+		Long: "Upload one random payload as a scratch artifact, download it " +
+			"repeatedly, and delete it again afterwards.",
+		Args: cobra.NoArgs,
+		RunE: runDownload,
+	}
+	addTransferFlags(cmd)
+
+	return cmd
+}
+
+func addTransferFlags(cmd *cobra.Command) {
+	cmd.Flags().String("size", "1MiB", "Payload size, e.g. 512KiB or 10MiB")
+	cmd.Flags().String("namespace", "bench", "Namespace for scratch artifacts")
+}
+
+func runAPI(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	n, concurrency := runFlags(cmd)
+
+	report := bench.Run(
+		client.WithoutCache(cmd.Context()),
+		"api",
+		n,
+		concurrency,
+		func(ctx context.Context, _ int) (int64, error) {
+			_, err := c.GetMe(ctx)
+
+			return 0, err
+		},
+	)
+
+	return printReport(cmd, &report)
+}
+
+func runUpload(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	n, concurrency := runFlags(cmd)
+	namespace, name, size, err := transferFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	payloads := make([][]byte, n)
+	for i := range payloads {
+		payloads[i] = randomPayload(size)
+	}
+	hashes := make([]string, n)
+	report := bench.Run(
+		cmd.Context(),
+		"upload",
+		n,
+		concurrency,
+		func(ctx context.Context, i int) (int64, error) {
+			res, err := c.UploadArtifact(
+				ctx,
+				namespace,
+				name,
+				bytes.NewReader(payloads[i]),
+			)
+			if err != nil {
+				return 0, err
+			}
+			hashes[i] = res.VersionHash
+
+			return size, nil
+		},
+	)
+	for _, h := range hashes {
+		if h != "" {
+			cleanup(cmd.Context(), c, namespace, name, h)
+		}
+	}
+
+	return printReport(cmd, &report)
+}
+
+func runDownload(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	n, concurrency := runFlags(cmd)
+	namespace, name, size, err := transferFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.UploadArtifact(
+		cmd.Context(),
+		namespace,
+		name,
+		bytes.NewReader(randomPayload(size)),
+	)
+	if err != nil {
+		return fmt.Errorf("upload scratch artifact: %w", err)
+	}
+	defer cleanup(cmd.Context(), c, namespace, name, res.VersionHash)
+
+	report := bench.Run(
+		client.WithoutCache(cmd.Context()),
+		"download",
+		n,
+		concurrency,
+		func(ctx context.Context, _ int) (int64, error) {
+			body, err := c.DownloadArtifactByHash(
+				ctx,
+				namespace,
+				name,
+				res.VersionHash,
+			)
+			if err != nil {
+				return 0, err
+			}
+			defer func() { _ = body.Close() }()
+
+			return io.Copy(io.Discard, body)
+		},
+	)
+
+	return printReport(cmd, &report)
+}
+
+func runFlags(cmd *cobra.Command) (n, concurrency int) {
+	n, _ = cmd.Flags().GetInt("requests")
+	concurrency, _ = cmd.Flags().GetInt("concurrency")
+
+	return max(n, 1), max(concurrency, 1)
+}
+
+// transferFlags returns the scratch artifact location and payload size.
+func transferFlags(
+	cmd *cobra.Command,
+) (namespace, name string, size int64, err error) {
+	namespace, _ = cmd.Flags().GetString("namespace")
+	raw, _ := cmd.Flags().GetString("size")
+	size, err = parseSize(raw)
+	if err != nil {
+		return "", "", 0, err
+	}
+	name = "encl-bench-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+
+	return namespace, name, size, nil
+}
+
+// parseSize parses a byte count with an optional B, KiB, MiB, or GiB
+// suffix.
+func parseSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		factor int64
+	}{{"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}, {"B", 1}}
+	factor := int64(1)
+	num := strings.TrimSpace(s)
+	for _, u := range units {
+		if v, ok := strings.CutSuffix(num, u.suffix); ok {
+			num, factor = strings.TrimSpace(v), u.factor
+
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q, e.g. 512KiB or 10MiB", s)
+	}
+
+	return n * factor, nil
+}
+
+func randomPayload(size int64) []byte {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+
+	return b
+}
+
+// cleanup deletes a scratch artifact version; failures are only logged.
+func cleanup(
+	ctx context.Context,
+	c *enclave.Client,
+	namespace, name, hash string,
+) {
+	if _, err := c.DeleteArtifactByHash(ctx, namespace, name, hash); err != nil {
+		log.Warn().Err(err).Str("hash", hash).Msg("delete scratch artifact")
+	}
+}
+
+func printReport(cmd *cobra.Command, r *bench.Report) error {
+	cfg := client.ConfigFromContext(cmd.Context())
+	printer := output.FromConfig(cfg, output.BenchColumns, os.Stdout)
+
+	return printer.Print([]any{*r})
+}
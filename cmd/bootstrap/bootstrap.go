@@ -0,0 +1,231 @@
+// Package bootstrap implements encl bootstrap, which sets up a fresh server
+// from a single file.
+package bootstrap
+
+import (
+	"bytes"
+	"cli/internal/client"
+	"cli/internal/config"
+	"cli/internal/i18n"
+	"cli/internal/password"
+	"cli/internal/rbac"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// spec is the bootstrap file.
+type spec struct {
+	rbac.Model `yaml:",inline"`
+
+	// Presets names built-in RBAC presets to apply, see encl rbac preset.
+	Presets []string `yaml:"presets"`
+	Users   []user   `yaml:"users"`
+}
+
+// user is a user to create. Password is only needed when the user does not
+// exist yet.
+type user struct {
+	Name        string   `yaml:"name"`
+	DisplayName string   `yaml:"display_name"`
+	Password    string   `yaml:"password"`
+	PasswordEnv string   `yaml:"password_env"`
+	Roles       []string `yaml:"roles"`
+}
+
+// NewCmd returns the "bootstrap" command.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Set up users, roles, resource groups, and policies from a file",
+		Long: "Create the users, roles, resource groups, and policies " +
+			"declared in a bootstrap file. Objects that already exist are " +
+			"kept and only extended, so running bootstrap again is safe.\n\n" +
+			"Example bootstrap.yaml:\n\n" +
+			"  presets: [admin, read-only]\n" +
+			"  resource_groups:\n" +
+			"    - name: tasks\n" +
+			"      endpoints: [/v1/task, /v1/task/*]\n" +
+			"  roles:\n" +
+			"    - name: operator\n" +
+			"  policies:\n" +
+			"    - {role: operator, resource_group: tasks, method: \"*\"}\n" +
+			"  users:\n" +
+			"    - name: alice\n" +
+			"      display_name: Alice\n" +
+			"      password_env: ALICE_PASSWORD\n" +
+			"      roles: [admin]",
+		Args: cobra.NoArgs,
+		RunE: runBootstrap,
+	}
+	cmd.Flags().StringP("file", "f", "", "Bootstrap file (YAML)")
+	_ = cmd.MarkFlagRequired("file")
+	rbac.AddYesFlag(cmd)
+	rbac.AddAtomicFlag(cmd)
+
+	return cmd
+}
+
+func runBootstrap(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+
+	path, _ := cmd.Flags().GetString("file")
+	s, err := readSpec(path)
+	if err != nil {
+		return err
+	}
+
+	m := s.Model
+	for _, name := range s.Presets {
+		p, ok := rbac.LookupPreset(name)
+		if !ok {
+			return fmt.Errorf(
+				"unknown preset %q (available: %s)",
+				name,
+				strings.Join(rbac.PresetNames(), ", "),
+			)
+		}
+		pm := p.Model("")
+		m.Roles = append(pm.Roles, m.Roles...)
+		m.ResourceGroups = append(pm.ResourceGroups, m.ResourceGroups...)
+		m.Policies = append(pm.Policies, m.Policies...)
+	}
+
+	steps, err := rbac.Plan(cmd.Context(), c, &m)
+	if err != nil {
+		return err
+	}
+	// Users come last so the roles they are given exist.
+	for i := range s.Users {
+		userSteps, err := planUser(cmd.Context(), c, &s.Users[i])
+		if err != nil {
+			return err
+		}
+		steps = append(steps, userSteps...)
+	}
+
+	return rbac.Apply(cmd, steps)
+}
+
+// readSpec parses the bootstrap file at path, rejecting unknown keys.
+func readSpec(path string) (*spec, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read bootstrap file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	var s spec
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return &s, nil
+}
+
+// planUser returns the step that creates u or adds its missing roles, if
+// any.
+func planUser(
+	ctx context.Context,
+	c *enclave.Client,
+	u *user,
+) ([]rbac.Step, error) {
+	existing, err := c.GetUser(ctx, u.Name)
+	switch {
+	case errors.Is(err, enclave.ErrNotFound):
+		cfg := client.ConfigFromContext(ctx)
+
+		return createUserStep(u, &cfg.PasswordPolicy)
+	case err != nil:
+		return nil, fmt.Errorf("get user %s: %w", u.Name, err)
+	}
+
+	roles := slices.Clone(existing.Roles)
+	for _, r := range u.Roles {
+		if !slices.Contains(roles, r) {
+			roles = append(roles, r)
+		}
+	}
+	if len(roles) == len(existing.Roles) {
+		return nil, nil
+	}
+
+	return []rbac.Step{{
+		Desc: i18n.Sprintf(
+			"add roles %s to user %s",
+			strings.Join(roles[len(existing.Roles):], ", "),
+			u.Name,
+		),
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			_, err := c.UpdateUser(ctx, u.Name, enclave.WithUserRoles(roles...))
+
+			return err
+		},
+		Revert: func(ctx context.Context, c *enclave.Client) error {
+			_, err := c.UpdateUser(
+				ctx,
+				u.Name,
+				enclave.WithUserRoles(existing.Roles...),
+			)
+
+			return err
+		},
+	}}, nil
+}
+
+func createUserStep(
+	u *user,
+	policy *config.PasswordPolicy,
+) ([]rbac.Step, error) {
+	pw := u.Password
+	if u.PasswordEnv != "" {
+		pw = os.Getenv(u.PasswordEnv)
+	}
+	if pw == "" {
+		return nil, fmt.Errorf(
+			"user %s does not exist and has no password "+
+				"(set password or password_env)",
+			u.Name,
+		)
+	}
+	if err := password.Check(policy, pw); err != nil {
+		return nil, fmt.Errorf("user %s: %w", u.Name, err)
+	}
+	displayName := u.DisplayName
+	if displayName == "" {
+		displayName = u.Name
+	}
+
+	desc := i18n.Sprintf("create user %s", u.Name)
+	if len(u.Roles) > 0 {
+		desc += i18n.Sprintf(" with %s", strings.Join(u.Roles, ", "))
+	}
+
+	return []rbac.Step{{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			_, err := c.CreateUser(
+				ctx,
+				u.Name,
+				pw,
+				displayName,
+				enclave.WithRoles(u.Roles...),
+			)
+
+			return err
+		},
+		Revert: func(ctx context.Context, c *enclave.Client) error {
+			_, err := c.DeleteUser(ctx, u.Name)
+
+			return err
+		},
+	}}, nil
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the "config" command group.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and change CLI settings",
+	}
+	cmd.AddCommand(
+		newViewCmd(),
+		newGetCmd(),
+		newSetCmd(),
+		newEditCmd(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	ic "cli/internal/config"
+	"cli/internal/i18n"
+	"cli/internal/output"
+	"cli/internal/prompt"
+	"cli/internal/styles"
+	"cli/internal/tui/views"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// setting is an editable config key.
+type setting struct {
+	key      string
+	field    views.FormField
+	validate func(value string) error
+}
+
+func newEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edit common settings in a form",
+		Long: "Edit common settings, including the active profile, in an " +
+			"interactive form. Changed values are validated and written to " +
+			"the config file; clearing a field restores its default.",
+		Args: cobra.NoArgs,
+		RunE: runEdit,
+	}
+}
+
+func runEdit(cmd *cobra.Command, _ []string) error {
+	stdin := int(os.Stdin.Fd())   // #nosec G115 -- file descriptors fit in an int
+	stdout := int(os.Stdout.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) ||
+		!prompt.Interactive() {
+		return errors.New(
+			"config edit needs an interactive terminal; use \"encl config set\" instead",
+		)
+	}
+
+	values, err := ic.FileValues()
+	if err != nil {
+		return err
+	}
+	profiles, err := ic.ProfileNames()
+	if err != nil {
+		return err
+	}
+	settings := editableSettings(profiles)
+
+	fields := make([]views.FormField, len(settings))
+	for i, s := range settings {
+		fields[i] = s.field
+		fields[i].Value = values[s.key]
+	}
+	path, err := ic.FilePath()
+	if err != nil {
+		return err
+	}
+	m := &editModel{
+		form:     views.NewForm(i18n.Sprintf("Settings in %s", path), fields),
+		settings: settings,
+		initial:  values,
+	}
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if styles.Plain {
+		opts = append(opts, tea.WithOutput(&styles.PlainFile{File: os.Stdout}))
+	}
+	if _, err := tea.NewProgram(m, opts...).Run(); err != nil {
+		return fmt.Errorf("config edit: %w", err)
+	}
+	if m.err != nil {
+		return m.err
+	}
+	if len(m.changed) == 0 {
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), i18n.T("No changes."))
+
+		return err
+	}
+	_, err = fmt.Fprintf(
+		cmd.OutOrStdout(),
+		i18n.T("Saved %s to %s\n"),
+		strings.Join(m.changed, ", "),
+		path,
+	)
+
+	return err
+}
+
+// editableSettings returns the settings shown by config edit, in order.
+func editableSettings(profiles []string) []setting {
+	profileHint := i18n.T("none")
+	if len(profiles) > 0 {
+		profileHint += " | " + strings.Join(profiles, " | ")
+	}
+
+	return []setting{
+		{
+			key:   "profile",
+			field: views.FormField{Label: "Profile", Placeholder: profileHint},
+			validate: func(v string) error {
+				return oneOf(v, profiles)
+			},
+		},
+		{
+			key: "api_url",
+			field: views.FormField{
+				Label:       "API URL",
+				Placeholder: "https://enclave.example.com",
+			},
+			validate: func(v string) error {
+				u, err := url.Parse(v)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+					u.Host == "" {
+					return fmt.Errorf("%q is not an http(s) URL", v)
+				}
+
+				return nil
+			},
+		},
+		{key: "username", field: views.FormField{Label: "Username"}},
+		{
+			key:   "password",
+			field: views.FormField{Label: "Password", Secret: true},
+		},
+		{
+			key: "output",
+			field: views.FormField{
+				Label:       "Output",
+				Placeholder: strings.Join(output.FormatNames, " | "),
+			},
+			validate: func(v string) error {
+				return oneOf(v, output.FormatNames)
+			},
+		},
+		{
+			key: "log_level",
+			field: views.FormField{
+				Label:       "Log level",
+				Placeholder: "trace | debug | info | warn | error",
+			},
+			validate: func(v string) error {
+				if _, err := zerolog.ParseLevel(v); err != nil {
+					return fmt.Errorf("%q is not a log level", v)
+				}
+
+				return nil
+			},
+		},
+		{
+			key: "theme.name",
+			field: views.FormField{
+				Label: "Theme",
+				Placeholder: "auto | " +
+					strings.Join(styles.ThemeNames, " | "),
+			},
+			validate: func(v string) error {
+				return oneOf(v, append([]string{"auto"}, styles.ThemeNames...))
+			},
+		},
+		{
+			key: "language",
+			field: views.FormField{
+				Label:       "Language",
+				Placeholder: "auto | " + strings.Join(i18n.Languages, " | "),
+			},
+			validate: func(v string) error {
+				return oneOf(v, append([]string{"auto"}, i18n.Languages...))
+			},
+		},
+	}
+}
+
+// oneOf returns an error unless v is one of allowed.
+func oneOf(v string, allowed []string) error {
+	if slices.Contains(allowed, v) {
+		return nil
+	}
+
+	return fmt.Errorf("%q must be one of: %s", v, strings.Join(allowed, ", "))
+}
+
+// editModel hosts the settings form and saves it on submit.
+type editModel struct {
+	form     views.FormModel
+	settings []setting
+	initial  map[string]string
+	changed  []string
+	err      error
+}
+
+func (m *editModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *editModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.form.SetSize(msg.Width, msg.Height)
+
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+	case views.FormCancelledMsg:
+		return m, tea.Quit
+	case views.FormSubmittedMsg:
+		if err := m.validate(msg.Values); err != nil {
+			m.form.SetError(err.Error())
+
+			return m, nil
+		}
+		m.err = m.save(msg.Values)
+
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.form, cmd = m.form.Update(msg)
+
+	return m, cmd
+}
+
+func (m *editModel) View() string {
+	return m.form.View()
+}
+
+// validate checks every non-empty value that changed.
+func (m *editModel) validate(values []string) error {
+	for i, s := range m.settings {
+		v := strings.TrimSpace(values[i])
+		if v == "" || v == m.initial[s.key] || s.validate == nil {
+			continue
+		}
+		if err := s.validate(v); err != nil {
+			return fmt.Errorf("%s: %w", s.field.Label, err)
+		}
+	}
+
+	return nil
+}
+
+// save writes the changed values; cleared values are removed from the file.
+func (m *editModel) save(values []string) error {
+	for i, s := range m.settings {
+		v := strings.TrimSpace(values[i])
+		if v == m.initial[s.key] {
+			continue
+		}
+		var err error
+		if v == "" {
+			_, err = ic.Unset(s.key)
+		} else {
+			_, err = ic.Set(s.key, v)
+		}
+		if err != nil {
+			return err
+		}
+		m.changed = append(m.changed, s.key)
+	}
+
+	return nil
+}
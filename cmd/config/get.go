@@ -0,0 +1,44 @@
+package config
+
+import (
+	ic "cli/internal/config"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newGetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the resolved value of a setting",
+		Long: "Print the resolved value of a setting. Nested keys are " +
+			"separated by dots, e.g. cache.ttl.",
+		Args: cobra.ExactArgs(1),
+		RunE: runGet,
+	}
+}
+
+func runGet(cmd *cobra.Command, args []string) error {
+	value, ok, err := ic.Get(cmd.Root().PersistentFlags(), args[0])
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%s is not set", args[0])
+	}
+
+	// Sections print as YAML; plain values print as-is.
+	if _, isMap := value.(map[string]any); isMap {
+		enc := yaml.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent(2)
+		if err := enc.Encode(value); err != nil {
+			return fmt.Errorf("encode yaml: %w", err)
+		}
+
+		return nil
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), value)
+
+	return err
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	ic "cli/internal/config"
+	"cli/internal/i18n"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Store a setting in the config file",
+		Long: "Store a setting in the config file, creating " +
+			"~/.enclave/config.yaml if none exists. Nested keys are " +
+			"separated by dots, e.g. cache.ttl.",
+		Example: "  encl config set telemetry on\n" +
+			"  encl config set cache.ttl 10m",
+		Args: cobra.ExactArgs(2),
+		RunE: runSet,
+	}
+}
+
+func runSet(cmd *cobra.Command, args []string) error {
+	path, err := ic.Set(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(
+		cmd.OutOrStdout(),
+		i18n.T("Set %s = %s in %s\n"),
+		args[0],
+		args[1],
+		path,
+	)
+
+	return err
+}
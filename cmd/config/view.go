@@ -0,0 +1,41 @@
+package config
+
+import (
+	ic "cli/internal/config"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secrets in printed settings.
+const redacted = "********"
+
+func newViewCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Show all resolved settings",
+		Long: "Show all settings after applying defaults, the config file, " +
+			"environment variables, and flags. The password is redacted.",
+		Args: cobra.NoArgs,
+		RunE: runView,
+	}
+}
+
+func runView(cmd *cobra.Command, _ []string) error {
+	settings, err := ic.Settings(cmd.Root().PersistentFlags())
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if pw, _ := settings["password"].(string); pw != "" {
+		settings["password"] = redacted
+	}
+
+	enc := yaml.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent(2)
+	if err := enc.Encode(settings); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+
+	return nil
+}
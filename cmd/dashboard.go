@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"cli/internal/client"
+	"cli/internal/prompt"
+	"cli/internal/tui"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newDashboardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Show a live overview of the platform",
+		Long: "Show a live, one-screen overview of the server: reachability " +
+			"and latency, user, role, resource group, and policy counts, " +
+			"artifact counts with the most recent uploads, and tasks by " +
+			"state with the latest failures.",
+		Args: cobra.NoArgs,
+		RunE: runDashboard,
+	}
+	cmd.Flags().Duration("interval", 10*time.Second, "Refresh interval")
+
+	return cmd
+}
+
+func runDashboard(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+
+	stdout := int(os.Stdout.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if !term.IsTerminal(stdout) || !prompt.Interactive() {
+		return errors.New("dashboard needs an interactive terminal")
+	}
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+
+	// Every refresh must reach the server.
+	ctx := client.WithoutCache(cmd.Context())
+
+	return tui.RunDashboard(ctx, c, cfg.APIURL, interval)
+}
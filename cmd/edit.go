@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/output"
+	"cli/internal/picker"
+	"cli/internal/prompt"
+	"cli/internal/resource"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// editHeader starts every document opened by encl edit.
+const editHeader = "# Edit the %s below and save to apply the changes. Lines\n" +
+	"# starting with '#' are ignored, and an empty or unchanged file\n" +
+	"# cancels the edit.\n"
+
+func newEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit <resource> <name>",
+		Short: "Edit a user, role, or resource group in your editor",
+		Long: "Fetch an object as YAML, open it in $VISUAL or $EDITOR " +
+			"(default vi), and apply the changes once the editor exits.\n\n" +
+			"Resources are user (display name and roles), role (the users " +
+			"bound to it), and resource-group (its endpoints). Names cannot " +
+			"be changed. When the edited document is invalid or the server " +
+			"rejects it, the editor opens again with the error at the top; " +
+			"saving it unchanged gives up.\n\n" +
+			"Changes to roles and resource groups are recorded for encl " +
+			"rbac undo, which puts the previous users or endpoints back.",
+		Example: "  encl edit user alice\n" +
+			"  encl edit role developers\n" +
+			"  EDITOR=nano encl edit rg artifacts",
+		Args: cobra.ExactArgs(2),
+		RunE: runEdit,
+	}
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+
+	kind, err := resource.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	stdin := int(os.Stdin.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if !term.IsTerminal(stdin) || !prompt.Interactive() {
+		return errors.New(
+			"edit needs an interactive terminal; use \"encl patch\" instead",
+		)
+	}
+	old, err := kind.Get(cmd.Context(), c, args[1])
+	if err != nil {
+		return picker.Suggest(cmd.Context(), kind.Source(c), args[1], err)
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(old); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+	body := buf.String()
+
+	text := fmt.Sprintf(editHeader, kind.Name) + body
+	var lastErr error
+	for {
+		edited, err := editText(cmd.Context(), text)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(stripComments(edited)) == "" ||
+			stripComments(edited) == body {
+			_, err = fmt.Fprintln(
+				cmd.OutOrStdout(),
+				i18n.T("Edit canceled, no changes made."),
+			)
+
+			return err
+		}
+		if lastErr != nil && edited == text {
+			return lastErr
+		}
+
+		obj, err := applyEdit(cmd.Context(), c, kind, old, edited)
+		if err == nil {
+			printer := output.FromConfig(cfg, kind.Columns, os.Stdout)
+
+			return printer.Print([]any{obj})
+		}
+		lastErr = err
+		text = "# " + strings.ReplaceAll(err.Error(), "\n", "\n# ") + "\n#\n" +
+			stripErrors(edited)
+	}
+}
+
+// applyEdit decodes the edited document and applies it.
+func applyEdit(
+	ctx context.Context,
+	c *enclave.Client,
+	kind *resource.Kind,
+	old any,
+	edited string,
+) (any, error) {
+	doc, err := kind.Decode([]byte(edited))
+	if err != nil {
+		return nil, err
+	}
+
+	return kind.Update(ctx, c, old, doc)
+}
+
+// editText opens text in the user's editor and returns the saved result.
+func editText(ctx context.Context, text string) (string, error) {
+	f, err := os.CreateTemp("", "encl-edit-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()
+
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+
+	editor := cmp.Or(os.Getenv("VISUAL"), os.Getenv("EDITOR"), "vi")
+	// Run the editor through the shell, as git does, so it may carry
+	// arguments such as "code --wait".
+	// #nosec G204 G702 -- the editor is chosen by the user running the CLI
+	c := exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, "sh", f.Name())
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("run editor %s: %w", editor, err)
+	}
+
+	// #nosec G304 -- the file was created above
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("read edited file: %w", err)
+	}
+
+	return string(edited), nil
+}
+
+// stripComments removes the lines starting with '#' from text.
+func stripComments(text string) string {
+	var b bytes.Buffer
+	for line := range strings.Lines(text) {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			b.WriteString(line)
+		}
+	}
+
+	return b.String()
+}
+
+// stripErrors removes the error lines a failed attempt put above the
+// header, so they do not pile up.
+func stripErrors(text string) string {
+	if i := strings.Index(text, "# Edit the "); i > 0 {
+		return text[i:]
+	}
+
+	return text
+}
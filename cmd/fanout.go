@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"cli/internal/config"
+	"cli/internal/i18n"
+	"cli/internal/output"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// fanOutCommands are the leaf commands --profiles may run: they only read.
+var fanOutCommands = []string{"list", "get", "versions", "logs"}
+
+// fanOutSkipFlags are not passed on to the per-profile processes. The parent
+// selects the profile, filters, sorts, and renders the merged output, and
+// writes the summary.
+var fanOutSkipFlags = []string{
+	"profiles", "all-profiles", "profile", "output", "output-file", "jq",
+	"jsonpath", "no-headers", "filter", "sort", "summary-file",
+}
+
+// fanOutSkipEnv are the environment variables the per-profile processes do
+// not inherit, for the same reason.
+var fanOutSkipEnv = []string{"ENCLAVE_SUMMARY_FILE"}
+
+// fanOutProfiles returns the profiles selected with --profiles or
+// --all-profiles, or nil if neither is given.
+func fanOutProfiles(cmd *cobra.Command) ([]string, error) {
+	pf := cmd.Root().PersistentFlags()
+	profiles, _ := pf.GetStringSlice("profiles")
+	all, _ := pf.GetBool("all-profiles")
+	if len(profiles) == 0 && !all {
+		return nil, nil
+	}
+	switch {
+	case len(profiles) > 0 && all:
+		return nil, errors.New(
+			"--profiles and --all-profiles are mutually exclusive",
+		)
+	case pf.Changed("profile"):
+		return nil, errors.New("--profile cannot be combined with --profiles")
+	case !slices.Contains(fanOutCommands, cmd.Name()):
+		return nil, fmt.Errorf(
+			"--profiles only runs read-only commands (%s)",
+			strings.Join(fanOutCommands, ", "),
+		)
+	}
+
+	defined, err := config.ProfileNames()
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		if len(defined) == 0 {
+			return nil, errors.New("no profiles are defined in the config")
+		}
+
+		return defined, nil
+	}
+	for _, p := range profiles {
+		if !slices.Contains(defined, p) {
+			return nil, fmt.Errorf("unknown profile %q", p)
+		}
+	}
+
+	return profiles, nil
+}
+
+// profileResult is the output of cmd run against one profile.
+type profileResult struct {
+	stdout []byte
+	err    error
+}
+
+// runFanOut runs cmd once per profile in separate processes, concurrently,
+// and prints their results merged with a PROFILE column. Profiles that fail
+// are reported on stderr without hiding the others' results.
+func runFanOut(
+	cmd *cobra.Command,
+	args []string,
+	cfg *config.Config,
+	profiles []string,
+) error {
+	// The processes would each write these files, and the parent sends no
+	// requests of its own to record.
+	if cfg.Record != "" {
+		return errors.New("--record cannot be combined with --profiles")
+	}
+	if f := cmd.Flags().Lookup("export"); f != nil && f.Changed {
+		return errors.New("--export cannot be combined with --profiles")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("find executable: %w", err)
+	}
+	childArgs := fanOutArgs(cmd, args, cfg)
+	env := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		name, _, _ := strings.Cut(kv, "=")
+
+		return slices.Contains(fanOutSkipEnv, name)
+	})
+
+	results := make([]profileResult, len(profiles))
+	var wg sync.WaitGroup
+	for i, profile := range profiles {
+		wg.Go(func() {
+			c := exec.CommandContext(
+				cmd.Context(),
+				exe,
+				childArgs...) // #nosec G204 -- re-runs this binary
+			// The parent records the command in the history once.
+			c.Env = append(
+				slices.Clone(env),
+				"ENCLAVE_PROFILE="+profile,
+				"ENCLAVE_PLAIN=true",
+				"ENCLAVE_HISTORY=false",
+				output.FanOutEnv+"=1",
+			)
+			var stdout, stderr bytes.Buffer
+			c.Stdout = &stdout
+			c.Stderr = &stderr
+			if err := c.Run(); err != nil {
+				// Keep the error only, without warnings logged before it.
+				msg := strings.TrimSpace(stderr.String())
+				prefix := i18n.T("Error:") + " "
+				if i := strings.LastIndex(msg, prefix); i >= 0 {
+					msg = msg[i+len(prefix):]
+				}
+				if msg == "" {
+					msg = err.Error()
+				}
+				results[i].err = errors.New(msg)
+			}
+			results[i].stdout = stdout.Bytes()
+		})
+	}
+	wg.Wait()
+
+	var headers []string
+	var rows []output.ProfileRow
+	var failed int
+	for i, r := range results {
+		if r.err != nil {
+			failed++
+			_, _ = fmt.Fprintf(os.Stderr, "%s: %v\n", profiles[i], r.err)
+
+			continue
+		}
+		var fo output.FanOut
+		if err := json.Unmarshal(r.stdout, &fo); err != nil {
+			// The command prints plain text; prefix it instead.
+			if err := printPrefixed(profiles[i], r.stdout); err != nil {
+				return err
+			}
+
+			continue
+		}
+		if headers == nil {
+			headers = fo.Headers
+		}
+		for j := range fo.Cells {
+			rows = append(rows, output.ProfileRow{
+				Profile: profiles[i],
+				Cells:   fo.Cells[j],
+				Item:    fo.Items[j],
+			})
+		}
+	}
+
+	if headers != nil {
+		// The profiles selected the columns already; only the position
+		// of PROFILE is left to choose.
+		merged := *cfg
+		if !slices.Contains(cfg.Columns, "profile") {
+			merged.Columns = nil
+		}
+		printer := output.FromConfig(
+			&merged,
+			output.ProfileColumns(headers),
+			os.Stdout,
+		)
+		if err := printer.Print(rows); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d profiles failed", failed, len(profiles))
+	}
+
+	return nil
+}
+
+// fanOutArgs rebuilds the command line of cmd for the per-profile processes,
+// without the flags in fanOutSkipFlags.
+func fanOutArgs(
+	cmd *cobra.Command,
+	args []string,
+	cfg *config.Config,
+) []string {
+	path := strings.Fields(cmd.CommandPath())[1:]
+	out := slices.Clone(path)
+	add := func(f *pflag.Flag) {
+		if !f.Changed || slices.Contains(fanOutSkipFlags, f.Name) {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				if f.Name == "columns" && v == "profile" {
+					continue
+				}
+				out = append(out, "--"+f.Name+"="+v)
+			}
+
+			return
+		}
+		out = append(out, "--"+f.Name+"="+f.Value.String())
+	}
+	cmd.Flags().VisitAll(add)
+	// Wide output needs the wide columns from each profile.
+	format := "table"
+	if output.ParseFormat(cfg.Output) == output.FormatWide {
+		format = "wide"
+	}
+	out = append(out, "--output="+format, "--")
+
+	return append(out, args...)
+}
+
+// printPrefixed writes text output of a profile with the profile name in
+// front of every line.
+func printPrefixed(profile string, out []byte) error {
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if _, err := fmt.Fprintf(
+			os.Stdout,
+			"%s: %s\n",
+			profile,
+			sc.Text(),
+		); err != nil {
+			return err
+		}
+	}
+
+	return sc.Err()
+}
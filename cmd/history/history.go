@@ -0,0 +1,64 @@
+package history
+
+import (
+	"cli/internal/config"
+	ih "cli/internal/history"
+	"cli/internal/output"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the "history" command.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show previously executed commands",
+		Long: "Show commands recorded in ~/.enclave/history.jsonl with their " +
+			"target server and outcome, oldest first. Passwords are never " +
+			"recorded. Use --output wide for flags, errors, and request IDs.",
+		Args: cobra.NoArgs,
+		RunE: runHistory,
+	}
+	cmd.Flags().Bool("failed", false, "Only show commands that failed")
+	cmd.Flags().String("profile", "", "Only show commands run with this profile")
+	cmd.Flags().
+		IntP("limit", "n", 50, "Show at most this many recent entries (0 for all)")
+
+	return cmd
+}
+
+func runHistory(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(cmd.Root().PersistentFlags())
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	printer := output.FromConfig(cfg, output.HistoryColumns, os.Stdout)
+
+	entries, err := ih.Read()
+	if err != nil {
+		return err
+	}
+
+	failed, _ := cmd.Flags().GetBool("failed")
+	profile, _ := cmd.Flags().GetString("profile")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	matched := make([]ih.Entry, 0, len(entries))
+	for i := range entries {
+		e := &entries[i]
+		if failed && !e.Failed() {
+			continue
+		}
+		if profile != "" && e.Profile != profile {
+			continue
+		}
+		matched = append(matched, *e)
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+
+	return printer.Print(matched)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"cli/internal/mockserver"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newMockServerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock-server",
+		Short: "Run an in-memory Enclave API for local testing",
+		Long: `Run an in-memory implementation of the Enclave API.
+
+Without --fixture the server starts with a single user "admin" (password
+"admin") that has access to everything. A fixture is a YAML file with
+users, roles, resourceGroups, policies, artifacts, and tasks to seed.
+All state is lost when the server stops.
+
+Tasks are not executed; they are reported as completed immediately.`,
+		Example: `  encl mock-server --listen :8080 --fixture fixture.yaml
+  ENCLAVE_API_URL=http://localhost:8080 encl --username admin --password admin user list`,
+		Args: cobra.NoArgs,
+		RunE: runMockServer,
+	}
+	cmd.Flags().String("listen", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().String("fixture", "", "YAML file with seed data")
+
+	return cmd
+}
+
+func runMockServer(cmd *cobra.Command, _ []string) error {
+	addr, _ := cmd.Flags().GetString("listen")
+	fixturePath, _ := cmd.Flags().GetString("fixture")
+
+	fixture, err := mockserver.LoadFixture(fixturePath)
+	if err != nil {
+		return err
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(cmd.Context(), "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	srv := &http.Server{
+		Handler:           mockserver.New(fixture).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(
+		cmd.Context(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(
+			context.WithoutCancel(ctx),
+			5*time.Second,
+		)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(
+		cmd.ErrOrStderr(),
+		"Mock Enclave API listening on http://%s\n",
+		ln.Addr(),
+	)
+	if err := srv.Serve(ln); err != nil &&
+		!errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/output"
+	"cli/internal/picker"
+	"cli/internal/resource"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newPatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "patch <resource> <name> -p <patch>",
+		Short: "Change fields of a user, role, or resource group",
+		Long: "Apply a JSON patch to the document form of an object, the one " +
+			"encl edit opens, and update the object on the server.\n\n" +
+			"--type merge (the default) is a JSON merge patch: fields in the " +
+			"patch replace those of the object, null clears a field, and " +
+			"lists are replaced as a whole. --type strategic adds the " +
+			"entries of patched lists to the existing ones instead, and " +
+			"removes the entries listed under " +
+			"\"$deleteFromPrimitiveList/<field>\".\n\n" +
+			"Resources are user (displayName, roles), role (users), and " +
+			"resource-group (endpoints). Names cannot be changed. Nothing is " +
+			"sent when the patch changes nothing. Patches of roles and " +
+			"resource groups are recorded for encl rbac undo, which puts the " +
+			"previous users or endpoints back.",
+		Example: `  encl patch user alice -p '{"displayName":"Alice Smith"}'
+  encl patch role developers --type strategic -p '{"users":["bob"]}'
+  encl patch rg artifacts --type strategic \
+    -p '{"$deleteFromPrimitiveList/endpoints":["/v1/artifact/upload"]}'`,
+		Args: cobra.ExactArgs(2),
+		RunE: runPatch,
+	}
+	cmd.Flags().StringP("patch", "p", "", "The patch, as JSON")
+	cmd.Flags().String(
+		"type",
+		resource.PatchMerge,
+		"Patch type: "+strings.Join(resource.PatchTypes, ", "),
+	)
+	_ = cmd.MarkFlagRequired("patch")
+
+	return cmd
+}
+
+func runPatch(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+
+	kind, err := resource.Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	patch, _ := cmd.Flags().GetString("patch")
+	if strings.TrimSpace(patch) == "" {
+		return errors.New("--patch must not be empty")
+	}
+	patchType, _ := cmd.Flags().GetString("type")
+	if !slices.Contains(resource.PatchTypes, patchType) {
+		return fmt.Errorf(
+			"unknown patch type %q (available: %s)",
+			patchType,
+			strings.Join(resource.PatchTypes, ", "),
+		)
+	}
+
+	old, err := kind.Get(cmd.Context(), c, args[1])
+	if err != nil {
+		return picker.Suggest(cmd.Context(), kind.Source(c), args[1], err)
+	}
+	doc, err := kind.Patch(old, []byte(patch), patchType)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(doc, old) {
+		_, err = fmt.Fprintln(cmd.ErrOrStderr(), i18n.T("No changes."))
+
+		return err
+	}
+
+	obj, err := kind.Update(cmd.Context(), c, old, doc)
+	if err != nil {
+		return err
+	}
+
+	return output.FromConfig(cfg, kind.Columns, os.Stdout).Print([]any{obj})
+}
@@ -2,6 +2,7 @@ package policy
 
 import (
 	"cli/internal/client"
+	"cli/internal/history"
 	"cli/internal/output"
 	"fmt"
 	"os"
@@ -23,16 +24,18 @@ func newCreateCmd() *cobra.Command {
 func runCreate(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.PolicyColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.PolicyColumns, os.Stdout)
 
 	p := policyFromFlags(cmd)
 	if err := c.CreatePolicy(cmd.Context(), p); err != nil {
 		return fmt.Errorf("create policy: %w", err)
 	}
+	history.RecordChange(
+		cmd.Context(),
+		history.KindPolicy,
+		history.ActionCreate,
+		p,
+	)
 
 	return printer.Print([]any{p})
 }
@@ -2,6 +2,7 @@ package policy
 
 import (
 	"cli/internal/client"
+	"cli/internal/history"
 	"cli/internal/output"
 	"fmt"
 	"os"
@@ -23,16 +24,18 @@ func newDeleteCmd() *cobra.Command {
 func runDelete(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.PolicyColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.PolicyColumns, os.Stdout)
 
 	p := policyFromFlags(cmd)
 	if err := c.DeletePolicy(cmd.Context(), p); err != nil {
 		return fmt.Errorf("delete policy: %w", err)
 	}
+	history.RecordChange(
+		cmd.Context(),
+		history.KindPolicy,
+		history.ActionDelete,
+		p,
+	)
 
 	return printer.Print([]any{p})
 }
@@ -2,9 +2,13 @@ package policy
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/progress"
+	"cli/internal/watch"
+	"context"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
@@ -19,6 +23,7 @@ func newListCmd() *cobra.Command {
 	cmd.Flags().String("role", "", "Filter by role")
 	cmd.Flags().String("resource-group", "", "Filter by resource group")
 	cmd.Flags().String("method", "", "Filter by HTTP method")
+	watch.AddFlag(cmd)
 
 	return cmd
 }
@@ -26,11 +31,6 @@ func newListCmd() *cobra.Command {
 func runList(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.PolicyColumns,
-		os.Stdout,
-	)
 
 	var opts []enclave.ListPoliciesOption
 	if v, _ := cmd.Flags().GetString("role"); v != "" {
@@ -43,10 +43,19 @@ func runList(cmd *cobra.Command, _ []string) error {
 		opts = append(opts, enclave.FilterPolicyByMethod(v))
 	}
 
-	policies, err := enclave.Collect(c.ListPolicies(cmd.Context(), opts...))
-	if err != nil {
-		return fmt.Errorf("list policies: %w", err)
-	}
-
-	return printer.Print(policies)
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, output.PolicyColumns, w)
+
+		policies, err := progress.Spin(
+			i18n.T("Listing policies"),
+			func() ([]enclave.Policy, error) {
+				return enclave.Collect(c.ListPolicies(ctx, opts...))
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list policies: %w", err)
+		}
+
+		return printer.Print(policies)
+	})
 }
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"cli/internal/client"
+	"cli/internal/proxy"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newProxyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Serve artifact downloads through a local pull-through cache",
+		Long: `Run an HTTP proxy in front of the configured Enclave API that caches
+artifact downloads on disk. Versions are fetched from the API on first
+request and served from the cache afterwards; tags are resolved against the
+API on every request, since they move. Other requests are forwarded to the
+API unchanged.
+
+Point clients at the proxy with api_url or ENCLAVE_API_URL. Artifacts are
+fetched into the cache with the proxy's credentials, but every download is
+first looked up on the API with the caller's own credentials, so callers
+only get artifacts they may download directly. Fetched content is checked
+against its SHA-256 version hash before it is cached.`,
+		Example: `  encl proxy --listen :8080
+  ENCLAVE_API_URL=http://build-cache:8080 encl artifact download plugins scanner latest`,
+		Args: cobra.NoArgs,
+		RunE: runProxy,
+	}
+	cmd.Flags().String("listen", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().String(
+		"cache-dir",
+		"",
+		"Directory for cached artifacts (default: user cache dir/enclave/artifacts)",
+	)
+
+	return cmd
+}
+
+func runProxy(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+
+	addr, _ := cmd.Flags().GetString("listen")
+	dir, _ := cmd.Flags().GetString("cache-dir")
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return fmt.Errorf("locate cache dir: %w", err)
+		}
+		dir = filepath.Join(base, "enclave", "artifacts")
+	}
+
+	p, err := proxy.New(c, cfg.APIURL, client.ExternalTransport(cfg), dir)
+	if err != nil {
+		return err
+	}
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(cmd.Context(), "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	srv := &http.Server{
+		Handler:           p.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(
+		cmd.Context(),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(
+			context.WithoutCancel(ctx),
+			5*time.Second,
+		)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	_, _ = fmt.Fprintf(
+		cmd.ErrOrStderr(),
+		"Caching proxy for %s listening on http://%s (cache: %s)\n",
+		cfg.APIURL,
+		ln.Addr(),
+		dir,
+	)
+	if err := srv.Serve(ln); err != nil &&
+		!errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package rbac
+
+import (
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/rbac"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+func newEndpointCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "endpoint",
+		Short: "Assign API endpoints to resource groups",
+	}
+	cmd.AddCommand(newEndpointImportCmd())
+
+	return cmd
+}
+
+func newEndpointImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Add the paths of an OpenAPI spec to resource groups",
+		Long: "Read the paths of an OpenAPI document and add them as " +
+			"endpoints to resource groups, following --map rules. Each path " +
+			"goes to the first rule that matches it:\n\n" +
+			"  tag:<tag>=<group>      operations tagged <tag>\n" +
+			"  path:<prefix>=<group>  paths starting with <prefix>\n" +
+			"  *=<group>              every path\n\n" +
+			"Path parameters become wildcards, e.g. /v1/artifact/{ns} " +
+			"becomes /v1/artifact/*. Missing resource groups are created; " +
+			"existing ones keep their endpoints. Paths no rule matches are " +
+			"listed and skipped.",
+		Example: "  encl rbac endpoint import --openapi spec.yaml \\\n" +
+			"    --map tag:artifacts=artifact-rg --map 'path:/v1/task=tasks'",
+		Args: cobra.NoArgs,
+		RunE: runEndpointImport,
+	}
+	cmd.Flags().String("openapi", "", "OpenAPI document (YAML or JSON)")
+	cmd.Flags().StringArray(
+		"map",
+		nil,
+		"Mapping rule tag:<tag>=<group>, path:<prefix>=<group>, or *=<group> (repeatable)",
+	)
+	cmd.Flags().Bool("dry-run", false, "Only list the changes")
+	_ = cmd.MarkFlagRequired("openapi")
+	_ = cmd.MarkFlagRequired("map")
+	rbac.AddYesFlag(cmd)
+	rbac.AddAtomicFlag(cmd)
+
+	return cmd
+}
+
+func runEndpointImport(cmd *cobra.Command, _ []string) error {
+	specs, _ := cmd.Flags().GetStringArray("map")
+	rules := make([]rbac.EndpointRule, 0, len(specs))
+	for _, s := range specs {
+		r, err := rbac.ParseEndpointRule(s)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, r)
+	}
+
+	path, _ := cmd.Flags().GetString("openapi")
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("open openapi document: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	paths, err := rbac.OpenAPIPaths(f)
+	if err != nil {
+		return err
+	}
+
+	m, unmapped := rbac.MapEndpoints(paths, rules)
+	for _, p := range unmapped {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), i18n.T("Not mapped:"), p)
+	}
+
+	steps, err := rbac.Plan(
+		cmd.Context(),
+		client.FromContext(cmd.Context()),
+		&m,
+	)
+	if err != nil {
+		return err
+	}
+
+	return rbac.Apply(cmd, steps)
+}
@@ -0,0 +1,50 @@
+package rbac
+
+import (
+	"cli/internal/client"
+	"cli/internal/rbac"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the RBAC configuration as Rego or a Casbin policy",
+		Long: "Write the roles, resource groups, and policies on the server " +
+			"in a format policy tools understand.\n\n" +
+			"The rego format is a module holding the objects as data and an " +
+			"allow rule for input of the form {\"user\", \"method\", " +
+			"\"path\"}, ready for opa eval or conftest. The casbin format is " +
+			"the policy file rbac import reads.",
+		Example: "  encl rbac export --format rego > enclave.rego\n" +
+			"  encl rbac export --format casbin --output-file policy.csv",
+		Args: cobra.NoArgs,
+		RunE: runExport,
+	}
+	cmd.Flags().String("format", "rego", "Output format: rego, casbin")
+	cmd.Flags().
+		String("package", "enclave.rbac", "Package name of the Rego module")
+
+	return cmd
+}
+
+func runExport(cmd *cobra.Command, _ []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "rego" && format != formatCasbin {
+		return fmt.Errorf("unknown format %q (available: rego, casbin)", format)
+	}
+
+	m, err := rbac.Fetch(cmd.Context(), client.FromContext(cmd.Context()))
+	if err != nil {
+		return err
+	}
+	if format == formatCasbin {
+		return rbac.WriteCasbin(os.Stdout, &m)
+	}
+	pkg, _ := cmd.Flags().GetString("package")
+
+	return rbac.WriteRego(os.Stdout, &m, pkg)
+}
@@ -0,0 +1,69 @@
+package rbac
+
+import (
+	"cli/internal/client"
+	"cli/internal/rbac"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// formatCasbin names the Casbin policy file format of import and export.
+const formatCasbin = "casbin"
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Create roles and policies from a Casbin policy file",
+		Long: "Create the roles, resource groups, and policies described " +
+			"by a policy file from another system. Objects that already " +
+			"exist are kept and only extended.\n\n" +
+			"The casbin format reads RBAC policy CSV files with resource " +
+			"roles:\n\n" +
+			"  p, <role>, <endpoint or resource group>, <method>\n" +
+			"  g, <user>, <role>\n" +
+			"  g2, <endpoint>, <resource group>\n\n" +
+			"Endpoints not grouped with g2 get a resource group of their " +
+			"own, named after the endpoint. The actions read and write " +
+			"stand for GET and HEAD, and for POST, PUT, PATCH, and DELETE. " +
+			"Deny rules and role hierarchies are rejected.",
+		Example: "  encl rbac import --format casbin policy.csv",
+		Args:    cobra.ExactArgs(1),
+		RunE:    runImport,
+	}
+	cmd.Flags().String("format", formatCasbin, "Format of the file: casbin")
+	rbac.AddYesFlag(cmd)
+	rbac.AddAtomicFlag(cmd)
+
+	return cmd
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != formatCasbin {
+		return fmt.Errorf("unknown format %q (available: casbin)", format)
+	}
+
+	f, err := os.Open(filepath.Clean(args[0]))
+	if err != nil {
+		return fmt.Errorf("open policy file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	m, err := rbac.ParseCasbin(f)
+	if err != nil {
+		return err
+	}
+
+	steps, err := rbac.Plan(
+		cmd.Context(),
+		client.FromContext(cmd.Context()),
+		&m,
+	)
+	if err != nil {
+		return err
+	}
+
+	return rbac.Apply(cmd, steps)
+}
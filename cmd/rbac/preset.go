@@ -0,0 +1,90 @@
+package rbac
+
+import (
+	"cli/internal/client"
+	"cli/internal/rbac"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newPresetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Create roles and policies from built-in templates",
+	}
+
+	apply := &cobra.Command{
+		Use:   "apply <name>",
+		Short: "Create the roles, resource groups, and policies of a preset",
+		Long: "Create the roles, resource groups, and policies of a " +
+			"built-in preset. Objects that already exist are kept, so " +
+			"applying a preset again changes nothing.",
+		Example: "  encl rbac preset apply read-only\n" +
+			"  encl rbac preset apply artifact-publisher --prefix team-",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: rbac.PresetNames(),
+		RunE:      runPresetApply,
+	}
+	apply.Flags().String(
+		"prefix",
+		"",
+		"Prepend to the names of created roles and resource groups",
+	)
+	apply.Flags().
+		BoolP("yes", "y", false, "Apply without asking for confirmation")
+	rbac.AddAtomicFlag(apply)
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the built-in presets",
+			Args:  cobra.NoArgs,
+			RunE:  runPresetList,
+		},
+		apply,
+	)
+
+	return cmd
+}
+
+func runPresetList(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	for _, p := range rbac.Presets() {
+		if _, err := fmt.Fprintf(
+			out,
+			"%-20s %s\n",
+			p.Name,
+			p.Description,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runPresetApply(cmd *cobra.Command, args []string) error {
+	preset, ok := rbac.LookupPreset(args[0])
+	if !ok {
+		return fmt.Errorf(
+			"unknown preset %q (available: %s)",
+			args[0],
+			strings.Join(rbac.PresetNames(), ", "),
+		)
+	}
+	prefix, _ := cmd.Flags().GetString("prefix")
+	m := preset.Model(prefix)
+
+	steps, err := rbac.Plan(
+		cmd.Context(),
+		client.FromContext(cmd.Context()),
+		&m,
+	)
+	if err != nil {
+		return err
+	}
+
+	return rbac.Apply(cmd, steps)
+}
@@ -0,0 +1,23 @@
+package rbac
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the "rbac" command group.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rbac",
+		Short: "Work with roles, resource groups, and policies as a whole",
+	}
+	cmd.AddCommand(
+		newUndoCmd(),
+		newPresetCmd(),
+		newImportCmd(),
+		newExportCmd(),
+		newDiffCmd(),
+		newEndpointCmd(),
+	)
+
+	return cmd
+}
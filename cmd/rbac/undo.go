@@ -0,0 +1,161 @@
+package rbac
+
+import (
+	"cli/internal/client"
+	"cli/internal/history"
+	"cli/internal/i18n"
+	"cli/internal/prompt"
+	"cli/internal/rbac"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newUndoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Reverse the last RBAC change made from this machine",
+		Long: "Reverse the most recent role, resource group, or policy " +
+			"change recorded in the local command history for the current " +
+			"server: created objects are deleted, deleted objects are " +
+			"recreated, and updated roles and resource groups get their " +
+			"previous users or endpoints back. Running undo again reverses " +
+			"the change before that, or retries the changes that could not " +
+			"be undone.\n\n" +
+			"Only what the CLI recorded is restored; e.g. policies the " +
+			"server removed together with a deleted role are not recreated.",
+		Args: cobra.NoArgs,
+		RunE: runUndo,
+	}
+	cmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+
+	return cmd
+}
+
+func runUndo(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+
+	entries, err := history.Read()
+	if err != nil {
+		return err
+	}
+	target, pending := lastUndoable(entries, cfg.APIURL)
+	if target == nil {
+		return errors.New(
+			i18n.Sprintf("no RBAC changes to undo for %s", cfg.APIURL),
+		)
+	}
+
+	steps := make([]rbac.Step, 0, len(pending))
+	for _, i := range slices.Backward(pending) {
+		step, err := rbac.Inverse(&target.Changes[i])
+		if err != nil {
+			return err
+		}
+		steps = append(steps, step)
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(
+		out,
+		i18n.T("Undoing %q run at %s against %s:\n"),
+		strings.Join(append([]string{target.Command}, target.Args...), " "),
+		target.Time.Local().Format(time.DateTime),
+		target.Server,
+	)
+	for _, s := range steps {
+		_, _ = fmt.Fprintln(out, "  "+s.Desc)
+	}
+
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		ok, err := prompt.Confirm(i18n.T("Apply these changes?"))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New(i18n.T("undo canceled"))
+		}
+	}
+
+	var (
+		errs []error
+		done []int
+	)
+	for i, s := range steps {
+		if err := s.Apply(cmd.Context(), c); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Desc, err))
+
+			continue
+		}
+		done = append(done, pending[len(pending)-1-i])
+	}
+	// Only the changes actually undone count as reverted, so running undo
+	// again retries the rest.
+	if err := errors.Join(errs...); err != nil {
+		if len(done) > 0 {
+			history.SetReverts(cmd.Context(), target.Time, done)
+		}
+
+		return err
+	}
+	history.SetReverts(cmd.Context(), target.Time, nil)
+	_, err = fmt.Fprintln(out, i18n.T("Done."))
+
+	return err
+}
+
+// lastUndoable returns the most recent entry for server that changed RBAC
+// objects and was not fully undone yet, with the indexes of the changes left
+// to undo in recording order, or nil.
+func lastUndoable(
+	entries []history.Entry,
+	server string,
+) (target *history.Entry, pending []int) {
+	// reverted maps the time of an entry to the indexes of its changes that
+	// were undone, or to nil once all of them were.
+	reverted := map[int64]map[int]bool{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := &entries[i]
+		if e.Server != server {
+			continue
+		}
+		if !e.Reverts.IsZero() {
+			key := e.Reverts.UnixNano()
+			done, seen := reverted[key]
+			switch {
+			case len(e.Reverted) == 0:
+				reverted[key] = nil
+			case !seen || done != nil:
+				if done == nil {
+					done = map[int]bool{}
+					reverted[key] = done
+				}
+				for _, c := range e.Reverted {
+					done[c] = true
+				}
+			}
+
+			continue
+		}
+		done, seen := reverted[e.Time.UnixNano()]
+		if seen && done == nil {
+			continue
+		}
+		pending = nil
+		for c := range e.Changes {
+			if !done[c] {
+				pending = append(pending, c)
+			}
+		}
+		if len(pending) > 0 {
+			return e, pending
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,134 @@
+package release
+
+import (
+	"cli/internal/client"
+	"cli/internal/output"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+func newPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <namespace> <name>",
+		Short: "Promote the version in one stage to the next",
+		Long: "Promote an artifact version from one release stage to the " +
+			"next. Stages are tags, ordered by promotion.stages in the config " +
+			"(default dev, staging, prod). The version tagged with --from " +
+			"receives the --to tag, which is removed from the version that " +
+			"held it, so every stage tag points at exactly one version.\n\n" +
+			"Stages cannot be skipped unless --skip-stages is given.",
+		Example: "  encl release promote plugins filter --to prod\n" +
+			"  encl release promote plugins filter --from dev --to prod --skip-stages",
+		Args: cobra.ExactArgs(2),
+		RunE: runPromote,
+	}
+	cmd.Flags().
+		String("from", "", "Stage to promote from (default: the stage before --to)")
+	cmd.Flags().String("to", "", "Stage to promote to")
+	cmd.Flags().
+		Bool("skip-stages", false, "Allow promoting past intermediate stages")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func runPromote(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+	printer := output.FromConfig(cfg, output.ArtifactColumns, os.Stdout)
+
+	namespace, name := args[0], args[1]
+	from, _ := cmd.Flags().GetString("from")
+	to, _ := cmd.Flags().GetString("to")
+	skip, _ := cmd.Flags().GetBool("skip-stages")
+	from, err := checkStages(cfg.Promotion.Stages, from, to, skip)
+	if err != nil {
+		return err
+	}
+
+	src, err := c.GetArtifactByTag(cmd.Context(), namespace, name, from)
+	if err != nil {
+		return fmt.Errorf("get %s version: %w", from, err)
+	}
+	if slices.Contains(src.Tags, to) {
+		return printer.Print([]any{src})
+	}
+
+	prev, err := c.GetArtifactByTag(cmd.Context(), namespace, name, to)
+	hasPrev := err == nil
+	if err != nil && !errors.Is(err, enclave.ErrNotFound) {
+		return fmt.Errorf("get %s version: %w", to, err)
+	}
+
+	// Tag the new version first, so a failure never leaves the stage
+	// without a version.
+	promoted, err := c.UpdateArtifactTagsByHash(
+		cmd.Context(),
+		namespace,
+		name,
+		src.VersionHash,
+		append(src.Tags, to),
+	)
+	if err != nil {
+		return fmt.Errorf("tag version as %s: %w", to, err)
+	}
+	if hasPrev && prev.VersionHash != src.VersionHash {
+		tags := slices.DeleteFunc(prev.Tags, func(t string) bool { return t == to })
+		if _, err := c.UpdateArtifactTagsByHash(
+			cmd.Context(),
+			namespace,
+			name,
+			prev.VersionHash,
+			tags,
+		); err != nil {
+			return fmt.Errorf("remove %s tag from previous version: %w", to, err)
+		}
+	}
+
+	return printer.Print([]any{promoted})
+}
+
+// checkStages validates a promotion along stages and returns the source
+// stage, defaulting to the one before to.
+func checkStages(stages []string, from, to string, skip bool) (string, error) {
+	pipeline := strings.Join(stages, " → ")
+	toIdx := slices.Index(stages, to)
+	if toIdx < 0 {
+		return "", fmt.Errorf("unknown stage %q (stages: %s)", to, pipeline)
+	}
+	if from == "" {
+		if toIdx == 0 {
+			return "", fmt.Errorf("%q is the first stage; nothing precedes it", to)
+		}
+
+		return stages[toIdx-1], nil
+	}
+
+	fromIdx := slices.Index(stages, from)
+	switch {
+	case fromIdx < 0:
+		return "", fmt.Errorf("unknown stage %q (stages: %s)", from, pipeline)
+	case fromIdx >= toIdx:
+		return "", fmt.Errorf(
+			"cannot promote from %s to %s (stages: %s)",
+			from,
+			to,
+			pipeline,
+		)
+	case fromIdx+1 < toIdx && !skip:
+		return "", fmt.Errorf(
+			"promoting from %s to %s skips %s; pass --skip-stages to allow it",
+			from,
+			to,
+			strings.Join(stages[fromIdx+1:toIdx], ", "),
+		)
+	}
+
+	return from, nil
+}
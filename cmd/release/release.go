@@ -0,0 +1,18 @@
+package release
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the "release" command group.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release",
+		Short: "Move artifact versions through release stages",
+	}
+	cmd.AddCommand(
+		newPromoteCmd(),
+	)
+
+	return cmd
+}
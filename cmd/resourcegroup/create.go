@@ -2,6 +2,7 @@ package resourcegroup
 
 import (
 	"cli/internal/client"
+	"cli/internal/history"
 	"cli/internal/output"
 	"fmt"
 	"os"
@@ -25,17 +26,19 @@ func newCreateCmd() *cobra.Command {
 func runCreate(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ResourceGroupColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.ResourceGroupColumns, os.Stdout)
 
 	endpoints, _ := cmd.Flags().GetStringSlice("endpoints")
 	rg, err := c.CreateResourceGroup(cmd.Context(), args[0], endpoints)
 	if err != nil {
 		return fmt.Errorf("create resource group: %w", err)
 	}
+	history.RecordChange(
+		cmd.Context(),
+		history.KindResourceGroup,
+		history.ActionCreate,
+		rg,
+	)
 
 	return printer.Print([]any{rg})
 }
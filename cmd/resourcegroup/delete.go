@@ -2,35 +2,109 @@ package resourcegroup
 
 import (
 	"cli/internal/client"
+	"cli/internal/history"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/parallel"
+	"cli/internal/picker"
+	"cli/internal/rbac"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
 )
 
 func newDeleteCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "delete <name>",
-		Short: "Delete a resource group",
-		Args:  cobra.ExactArgs(1),
+	cmd := &cobra.Command{
+		Use:   "delete <name>...",
+		Short: "Delete one or more resource groups",
+		Args:  picker.MinimumNArgs(1),
 		RunE:  runDelete,
 	}
+	parallel.AddFlag(cmd)
+	rbac.AddAtomicFlag(cmd)
+	rbac.AddForceFlag(cmd)
+	cmd.Flags().String(
+		"move-endpoints-to",
+		"",
+		"Add the endpoints of the deleted groups to this resource group first",
+	)
+	cmd.Flags().Bool(
+		"move-policies",
+		false,
+		"Also grant the policies of the deleted groups on the new group",
+	)
+
+	return cmd
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ResourceGroupColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.ResourceGroupColumns, os.Stdout)
 
-	rg, err := c.DeleteResourceGroup(cmd.Context(), args[0])
+	args, err := picker.Fill(cmd.Context(), args, picker.ResourceGroups(c))
 	if err != nil {
-		return fmt.Errorf("delete resource group: %w", err)
+		return err
+	}
+	target, _ := cmd.Flags().GetString("move-endpoints-to")
+	movePolicies, _ := cmd.Flags().GetBool("move-policies")
+	if movePolicies && target == "" {
+		return errors.New("--move-policies requires --move-endpoints-to")
+	}
+	// Moved policies no longer depend on the deleted groups.
+	if !movePolicies {
+		if err := rbac.CheckReferences(
+			cmd,
+			history.KindResourceGroup,
+			args,
+		); err != nil {
+			return err
+		}
+	}
+
+	since := rbac.Recorded(cmd.Context())
+	if target != "" {
+		err := moveEndpoints(cmd, c, args, target, movePolicies)
+		if err != nil && rbac.Atomic(cmd) {
+			return rbac.Rollback(cmd.Context(), c, cmd.OutOrStdout(), since, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	deleted, err := parallel.Map(
+		cmd,
+		i18n.T("Deleting resource groups"),
+		args,
+		func(ctx context.Context, name string) (enclave.ResourceGroup, error) {
+			v, err := c.DeleteResourceGroup(ctx, name)
+			if err != nil {
+				return v, fmt.Errorf(
+					"delete resource group %s: %w",
+					name,
+					picker.Suggest(ctx, picker.ResourceGroups(c), name, err),
+				)
+			}
+			history.RecordChange(
+				ctx,
+				history.KindResourceGroup,
+				history.ActionDelete,
+				v,
+			)
+
+			return v, nil
+		},
+	)
+	if err != nil && rbac.Atomic(cmd) {
+		return rbac.Rollback(cmd.Context(), c, cmd.OutOrStdout(), since, err)
+	}
+	if len(deleted) > 0 {
+		err = errors.Join(printer.Print(deleted), err)
 	}
 
-	return printer.Print([]any{rg})
+	return err
 }
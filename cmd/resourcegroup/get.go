@@ -3,6 +3,7 @@ package resourcegroup
 import (
 	"cli/internal/client"
 	"cli/internal/output"
+	"cli/internal/picker"
 	"fmt"
 	"os"
 
@@ -13,7 +14,7 @@ func newGetCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get <name>",
 		Short: "Get a resource group by name",
-		Args:  cobra.ExactArgs(1),
+		Args:  picker.ExactArgs(1),
 		RunE:  runGet,
 	}
 }
@@ -21,15 +22,24 @@ func newGetCmd() *cobra.Command {
 func runGet(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ResourceGroupColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.ResourceGroupColumns, os.Stdout)
+
+	args, err := picker.Fill(cmd.Context(), args, picker.ResourceGroups(c))
+	if err != nil {
+		return err
+	}
 
 	rg, err := c.GetResourceGroup(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("get resource group: %w", err)
+		return fmt.Errorf(
+			"get resource group: %w",
+			picker.Suggest(
+				cmd.Context(),
+				picker.ResourceGroups(c),
+				args[0],
+				err,
+			),
+		)
 	}
 
 	return printer.Print([]any{rg})
@@ -2,35 +2,46 @@ package resourcegroup
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/progress"
+	"cli/internal/watch"
+	"context"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
 )
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all resource groups",
 		RunE:  runList,
 	}
+	watch.AddFlag(cmd)
+
+	return cmd
 }
 
 func runList(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.ResourceGroupColumns,
-		os.Stdout,
-	)
-
-	rgs, err := enclave.Collect(c.ListResourceGroups(cmd.Context()))
-	if err != nil {
-		return fmt.Errorf("list resource groups: %w", err)
-	}
 
-	return printer.Print(rgs)
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, output.ResourceGroupColumns, w)
+
+		rgs, err := progress.Spin(
+			i18n.T("Listing resource groups"),
+			func() ([]enclave.ResourceGroup, error) {
+				return enclave.Collect(c.ListResourceGroups(ctx))
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list resource groups: %w", err)
+		}
+
+		return printer.Print(rgs)
+	})
 }
@@ -0,0 +1,70 @@
+package resourcegroup
+
+import (
+	"cli/internal/picker"
+	"cli/internal/rbac"
+	"fmt"
+	"slices"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+// moveEndpoints adds the endpoints of the resource groups names to target
+// and, with policies set, grants the roles of their policies the same
+// methods on target, so access survives their deletion.
+func moveEndpoints(
+	cmd *cobra.Command,
+	c *enclave.Client,
+	names []string,
+	target string,
+	policies bool,
+) error {
+	ctx := cmd.Context()
+	if slices.Contains(names, target) {
+		return fmt.Errorf(
+			"cannot move endpoints to %s, it is being deleted",
+			target,
+		)
+	}
+	if _, err := c.GetResourceGroup(ctx, target); err != nil {
+		return fmt.Errorf(
+			"get resource group %s: %w",
+			target,
+			picker.Suggest(ctx, picker.ResourceGroups(c), target, err),
+		)
+	}
+
+	current, err := rbac.Fetch(ctx, c)
+	if err != nil {
+		return err
+	}
+	want := rbac.ResourceGroup{Name: target}
+	for _, rg := range current.ResourceGroups {
+		if slices.Contains(names, rg.Name) {
+			want.Endpoints = append(want.Endpoints, rg.Endpoints...)
+		}
+	}
+	m := rbac.Model{ResourceGroups: []rbac.ResourceGroup{want}}
+	if policies {
+		for _, p := range current.Policies {
+			if slices.Contains(names, p.ResourceGroup) {
+				p.ResourceGroup = target
+				m.Policies = append(m.Policies, p)
+			}
+		}
+	}
+
+	steps, err := rbac.Plan(ctx, c, &m)
+	if err != nil {
+		return err
+	}
+	for _, s := range steps {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), s.Desc)
+		if err := s.Apply(ctx, c); err != nil {
+			return fmt.Errorf("%s: %w", s.Desc, err)
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package role
 
 import (
 	"cli/internal/client"
+	"cli/internal/history"
 	"cli/internal/output"
 	"fmt"
 	"os"
@@ -24,17 +25,14 @@ func newCreateCmd() *cobra.Command {
 func runCreate(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.RoleColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.RoleColumns, os.Stdout)
 
 	users, _ := cmd.Flags().GetStringSlice("users")
 	r, err := c.CreateRole(cmd.Context(), args[0], users)
 	if err != nil {
 		return fmt.Errorf("create role: %w", err)
 	}
+	history.RecordChange(cmd.Context(), history.KindRole, history.ActionCreate, r)
 
 	return printer.Print([]any{r})
 }
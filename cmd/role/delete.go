@@ -2,35 +2,73 @@ package role
 
 import (
 	"cli/internal/client"
+	"cli/internal/history"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/parallel"
+	"cli/internal/picker"
+	"cli/internal/rbac"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
 )
 
 func newDeleteCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "delete <role>",
-		Short: "Delete a role",
-		Args:  cobra.ExactArgs(1),
+	cmd := &cobra.Command{
+		Use:   "delete <role>...",
+		Short: "Delete one or more roles",
+		Args:  picker.MinimumNArgs(1),
 		RunE:  runDelete,
 	}
+	parallel.AddFlag(cmd)
+	rbac.AddAtomicFlag(cmd)
+	rbac.AddForceFlag(cmd)
+
+	return cmd
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.RoleColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.RoleColumns, os.Stdout)
 
-	r, err := c.DeleteRole(cmd.Context(), args[0])
+	args, err := picker.Fill(cmd.Context(), args, picker.Roles(c))
 	if err != nil {
-		return fmt.Errorf("delete role: %w", err)
+		return err
+	}
+	if err := rbac.CheckReferences(cmd, history.KindRole, args); err != nil {
+		return err
+	}
+
+	since := rbac.Recorded(cmd.Context())
+	deleted, err := parallel.Map(
+		cmd,
+		i18n.T("Deleting roles"),
+		args,
+		func(ctx context.Context, name string) (enclave.Role, error) {
+			v, err := c.DeleteRole(ctx, name)
+			if err != nil {
+				return v, fmt.Errorf(
+					"delete role %s: %w",
+					name,
+					picker.Suggest(ctx, picker.Roles(c), name, err),
+				)
+			}
+			history.RecordChange(ctx, history.KindRole, history.ActionDelete, v)
+
+			return v, nil
+		},
+	)
+	if err != nil && rbac.Atomic(cmd) {
+		return rbac.Rollback(cmd.Context(), c, cmd.OutOrStdout(), since, err)
+	}
+	if len(deleted) > 0 {
+		err = errors.Join(printer.Print(deleted), err)
 	}
 
-	return printer.Print([]any{r})
+	return err
 }
@@ -2,35 +2,89 @@ package role
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/picker"
+	"cli/internal/progress"
 	"fmt"
 	"os"
 
+	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
 )
 
 func newGetCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "get <role>",
 		Short: "Get a role by name",
-		Args:  cobra.ExactArgs(1),
+		Args:  picker.ExactArgs(1),
 		RunE:  runGet,
 	}
+	cmd.Flags().
+		Bool("members", false, "List the role's members with their user details")
+
+	return cmd
 }
 
 func runGet(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.RoleColumns,
-		os.Stdout,
-	)
+
+	args, err := picker.Fill(cmd.Context(), args, picker.Roles(c))
+	if err != nil {
+		return err
+	}
 
 	r, err := c.GetRole(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("get role: %w", err)
+		return fmt.Errorf(
+			"get role: %w",
+			picker.Suggest(cmd.Context(), picker.Roles(c), args[0], err),
+		)
+	}
+
+	if members, _ := cmd.Flags().GetBool("members"); members {
+		users, err := roleMembers(cmd, c, r)
+		if err != nil {
+			return err
+		}
+
+		return output.FromConfig(cfg, output.UserColumns, os.Stdout).Print(users)
+	}
+
+	return output.FromConfig(cfg, output.RoleColumns, os.Stdout).Print([]any{r})
+}
+
+// roleMembers resolves the users of r with a single user listing instead of
+// one lookup per member. Members missing from the listing are reported by
+// name only.
+func roleMembers(
+	cmd *cobra.Command,
+	c *enclave.Client,
+	r enclave.Role,
+) ([]enclave.User, error) {
+	all, err := progress.Spin(
+		i18n.T("Listing users"),
+		func() ([]enclave.User, error) {
+			return enclave.Collect(c.ListUsers(cmd.Context()))
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+
+	byName := make(map[string]enclave.User, len(all))
+	for _, u := range all {
+		byName[u.Name] = u
+	}
+	members := make([]enclave.User, 0, len(r.Users))
+	for _, name := range r.Users {
+		u, ok := byName[name]
+		if !ok {
+			u = enclave.User{Name: name}
+		}
+		members = append(members, u)
 	}
 
-	return printer.Print([]any{r})
+	return members, nil
 }
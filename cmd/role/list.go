@@ -2,35 +2,46 @@ package role
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/progress"
+	"cli/internal/watch"
+	"context"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
 )
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all roles",
 		RunE:  runList,
 	}
+	watch.AddFlag(cmd)
+
+	return cmd
 }
 
 func runList(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.RoleColumns,
-		os.Stdout,
-	)
-
-	roles, err := enclave.Collect(c.ListRoles(cmd.Context()))
-	if err != nil {
-		return fmt.Errorf("list roles: %w", err)
-	}
 
-	return printer.Print(roles)
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, output.RoleColumns, w)
+
+		roles, err := progress.Spin(
+			i18n.T("Listing roles"),
+			func() ([]enclave.Role, error) {
+				return enclave.Collect(c.ListRoles(ctx))
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list roles: %w", err)
+		}
+
+		return printer.Print(roles)
+	})
 }
@@ -2,17 +2,43 @@ package cmd
 
 import (
 	"cli/cmd/artifact"
+	"cli/cmd/auth"
+	"cli/cmd/bench"
+	"cli/cmd/bootstrap"
+	configcmd "cli/cmd/config"
+	historycmd "cli/cmd/history"
 	"cli/cmd/policy"
+	"cli/cmd/rbac"
+	"cli/cmd/release"
 	"cli/cmd/resourcegroup"
 	"cli/cmd/role"
 	"cli/cmd/task"
+	telemetrycmd "cli/cmd/telemetry"
+	testcmd "cli/cmd/test"
 	"cli/cmd/user"
+	"cli/internal/ci"
 	"cli/internal/client"
 	"cli/internal/config"
+	"cli/internal/history"
+	"cli/internal/hooks"
+	"cli/internal/i18n"
+	"cli/internal/output"
+	"cli/internal/prompt"
+	"cli/internal/styles"
+	"cli/internal/summary"
+	"cli/internal/telemetry"
 	"cli/internal/tui"
+	iv "cli/internal/version"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
+	"charm.land/lipgloss/v2"
+	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -20,13 +46,17 @@ import (
 )
 
 var rootCmd = &cobra.Command{
-	Use:          "encl",
-	Short:        "Enclave CLI — manage users, roles, tasks, and artifacts",
-	SilenceUsage: true,
-	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
-		// Skip setup for commands that don't need the SDK client.
-		if cmd.Name() == "version" || cmd.Name() == "help" ||
-			cmd.Name() == "completion" {
+	Use:           "encl",
+	Short:         "Enclave CLI — manage users, roles, tasks, and artifacts",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if skipSetup(cmd) {
+			// Local commands still speak the configured language.
+			if cfg, err := config.Load(cmd.Root().PersistentFlags()); err == nil {
+				_ = i18n.SetLanguage(cfg.Language)
+			}
+
 			return nil
 		}
 
@@ -41,9 +71,49 @@ var rootCmd = &cobra.Command{
 			level = zerolog.InfoLevel
 		}
 		zerolog.SetGlobalLevel(level)
-		log.Logger = zerolog.New(
-			zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05"},
-		).With().Timestamp().Logger()
+		if err := ci.Set(cfg.CI); err != nil {
+			return err
+		}
+		ci.Mask(cfg.Password)
+		var logOut io.Writer = zerolog.ConsoleWriter{
+			Out:        ci.Redacting(os.Stderr),
+			TimeFormat: "15:04:05",
+		}
+		if ci.Mode() != "" {
+			logOut = ci.LogWriter(logOut, os.Stderr)
+		}
+		log.Logger = zerolog.New(logOut).
+			Hook(summary.Hook{}).
+			With().
+			Timestamp().
+			Logger()
+
+		if err := applyTheme(cfg); err != nil {
+			return err
+		}
+		if err := output.CheckFilters(cfg); err != nil {
+			return err
+		}
+		if cfg.OutputFile != "" {
+			if err := output.RedirectToFile(cfg.OutputFile); err != nil {
+				return err
+			}
+		}
+		if err := i18n.SetLanguage(cfg.Language); err != nil {
+			return fmt.Errorf("set language: %w", err)
+		}
+
+		profiles, err := fanOutProfiles(cmd)
+		if err != nil {
+			return err
+		}
+		if profiles != nil {
+			cmd.RunE = func(cmd *cobra.Command, args []string) error {
+				return runFanOut(cmd, args, cfg, profiles)
+			}
+
+			return nil
+		}
 
 		// Build the SDK client.
 		c, err := client.New(cfg)
@@ -51,16 +121,21 @@ var rootCmd = &cobra.Command{
 			return err
 		}
 
-		// Store both in the command context for subcommands.
+		// Store them in the command context for subcommands.
 		ctx := client.WithClient(cmd.Context(), c)
 		ctx = client.WithConfig(ctx, cfg)
+		ctx = history.WithJournal(ctx)
+		ctx = client.WithRequestID(ctx)
 		cmd.SetContext(ctx)
 
-		return nil
+		return hooks.RunPre(ctx, cfg, hooks.Invocation{
+			Command: commandName(cmd),
+			Args:    history.RedactArgs(cmd, args),
+		})
 	},
 	RunE: func(cmd *cobra.Command, _ []string) error {
 		// When run with no subcommand and attached to a TTY, launch TUI.
-		if term.IsTerminal(int(os.Stdout.Fd())) {
+		if term.IsTerminal(int(os.Stdout.Fd())) && prompt.Interactive() {
 			c := client.FromContext(cmd.Context())
 			cfg := client.ConfigFromContext(cmd.Context())
 
@@ -79,18 +154,235 @@ var rootCmd = &cobra.Command{
 // Execute is the entry point called from main.
 func Execute(version string) {
 	appVersion = version
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	// Requests printed by --curl are aborted on purpose; that is not a failure.
+	if errors.Is(err, client.ErrNotSent) {
+		err = nil
+	}
+	err = client.WrapRequestID(cmd.Context(), err)
+	if fileErr := output.FinishFile(err == nil); fileErr != nil && err == nil {
+		err = fileErr
+	}
+
+	// Post hooks, history, and telemetry only run for commands that got
+	// past setup.
+	if cfg := client.ConfigFromContext(cmd.Context()); cfg != nil {
+		hooks.RunPost(cmd.Context(), cfg, hooks.Invocation{
+			Command: commandName(cmd),
+			Args:    history.RedactArgs(cmd, cmd.Flags().Args()),
+			Err:     err,
+		})
+		recordHistory(cmd, cfg, start, err)
+		telemetry.Record(cmd.Context(), cfg, commandName(cmd), appVersion, err)
+	}
+
+	if closeErr := client.Close(); closeErr != nil {
+		log.Warn().Err(closeErr).Msg("flush http recording")
+	}
+
+	notifyUpdate(cmd)
+
+	exitCode := 0
+	if err != nil {
+		exitCode = 1
 	}
+	writeSummary(cmd, start, exitCode, err)
+	if err != nil {
+		printError(cmd, err)
+		os.Exit(exitCode)
+	}
+}
+
+// writeSummary writes the --summary-file, if one was requested. Commands
+// that failed before setup still get one as long as the flag was parsed.
+func writeSummary(
+	cmd *cobra.Command,
+	start time.Time,
+	exitCode int,
+	err error,
+) {
+	cfg := client.ConfigFromContext(cmd.Context())
+	if cfg == nil {
+		cfg, _ = config.Load(rootCmd.PersistentFlags())
+	}
+	if cfg == nil || cfg.SummaryFile == "" {
+		return
+	}
+	changes, _ := history.Changes(cmd.Context())
+	s := summary.New(commandName(cmd), start, changes, exitCode, err)
+	if writeErr := summary.Write(cfg.SummaryFile, s); writeErr != nil {
+		log.Warn().Err(writeErr).Msg("write summary")
+	}
+}
+
+// recordHistory appends the finished command to the local history. Dry runs
+// with --curl or --replay change nothing and are not recorded.
+func recordHistory(
+	cmd *cobra.Command,
+	cfg *config.Config,
+	start time.Time,
+	err error,
+) {
+	if !cfg.History || cfg.Curl || cfg.Replay != "" {
+		return
+	}
+	e := history.NewEntry(cmd, commandName(cmd), cmd.Flags().Args())
+	e.Time = start
+	e.Duration = time.Since(start)
+	e.Profile = cfg.Profile
+	e.Server = cfg.APIURL
+	e.User = cfg.Username
+	e.Changes, e.Reverts = history.Changes(cmd.Context())
+	e.Reverted = history.Reverted(cmd.Context())
+	if err != nil {
+		e.Error = err.Error()
+		e.RequestID = client.RequestID(err)
+	}
+	if err := history.Append(&e); err != nil {
+		log.Warn().Err(err).Msg("record history")
+	}
+}
+
+// notifyUpdate hints at a newer release on stderr. It is skipped when
+// disabled in the config and when stderr is not a terminal, so scripts and
+// pipelines never see it.
+func notifyUpdate(cmd *cobra.Command) {
+	cfg := client.ConfigFromContext(cmd.Context())
+	stderr := int(os.Stderr.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if cfg == nil || !cfg.UpdateCheck || !term.IsTerminal(stderr) {
+		return
+	}
+	iv.Notify(cmd.Context(), os.Stderr, appVersion)
+}
+
+// printError reports err on stderr. API errors include the server request ID
+// when one was returned. With --ci the error becomes an
+// annotation, otherwise it is rendered as JSON with --output json.
+func printError(cmd *cobra.Command, err error) {
+	var status int
+	var apiErr *enclave.APIError
+	if errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	requestID := client.RequestID(err)
+
+	msg := err.Error()
+	if ci.Mode() != "" {
+		ci.Error(os.Stderr, msg)
+
+		return
+	}
+
+	cfg := client.ConfigFromContext(cmd.Context())
+	if cfg != nil && output.ParseFormat(cfg.Output) == output.FormatJSON {
+		enc := json.NewEncoder(os.Stderr)
+		enc.SetIndent("", "  ")
+		encErr := enc.Encode(struct {
+			Error     string `json:"error"`
+			Status    int    `json:"status,omitempty"`
+			RequestID string `json:"requestId,omitempty"`
+		}{err.Error(), status, requestID})
+		if encErr == nil {
+			return
+		}
+	}
+
+	_, _ = fmt.Fprintln(os.Stderr, i18n.T("Error:"), msg)
+}
+
+// applyTheme activates the configured color theme, or plain output. The
+// terminal background is only queried when the theme is "auto", stdout is a
+// terminal, and input is interactive.
+func applyTheme(cfg *config.Config) error {
+	dark := true
+	stdout := int(os.Stdout.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if (cfg.Theme.Name == "" || cfg.Theme.Name == "auto") && !cfg.Plain &&
+		term.IsTerminal(stdout) && prompt.Interactive() {
+		dark = lipgloss.HasDarkBackground(os.Stdin, os.Stdout)
+	}
+	if err := styles.Apply(cfg.Theme.Name, dark, cfg.Theme.Colors); err != nil {
+		return fmt.Errorf("apply theme: %w", err)
+	}
+	if cfg.Plain {
+		styles.SetPlain()
+	}
+
+	return nil
+}
+
+// skipSetup reports whether cmd runs without the SDK client, i.e. it or one
+// of its parents is a local command.
+func skipSetup(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "version", "help", "completion", "mock-server", "config",
+			"telemetry", "history", "favorite", "recent":
+			return true
+		}
+	}
+
+	return false
+}
+
+// commandName returns the command path without the binary name, e.g.
+// "artifact upload".
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
 }
 
 func init() {
+	// Argument validation asks pickers whether they may prompt before
+	// PersistentPreRunE runs, so --non-interactive is applied earlier. --ci
+	// is applied here too so argument errors are annotated; invalid values
+	// are reported by PersistentPreRunE.
+	cobra.OnInitialize(func() {
+		cfg, err := config.Load(rootCmd.PersistentFlags())
+		if err != nil {
+			return
+		}
+		if cfg.NonInteractive {
+			prompt.SetNonInteractive()
+		}
+		_ = ci.Set(cfg.CI)
+	})
+
 	pf := rootCmd.PersistentFlags()
 	pf.String(
 		"api-url",
 		"",
 		"Enclave API URL (overrides config and ENCLAVE_API_URL)",
 	)
+	pf.String(
+		"profile",
+		"",
+		"Config profile to use (overrides config and ENCLAVE_PROFILE)",
+	)
+	pf.StringSlice(
+		"profiles",
+		nil,
+		"Run a read-only command against these profiles and merge the results",
+	)
+	pf.Bool(
+		"all-profiles",
+		false,
+		"Run a read-only command against every configured profile",
+	)
+	pf.Bool(
+		"non-interactive",
+		false,
+		"Never prompt; fail when input is missing (implied when stdin is not a terminal)",
+	)
+	pf.String(
+		"ci",
+		"",
+		"Format errors and warnings for a CI system: github, gitlab, auto",
+	)
+	pf.String(
+		"summary-file",
+		"",
+		"Write a JSON summary of what the command did to a file",
+	)
 	pf.String("username", "", "Username (overrides config and ENCLAVE_USERNAME)")
 	pf.String("password", "", "Password (overrides config and ENCLAVE_PASSWORD)")
 	pf.String(
@@ -98,15 +390,98 @@ func init() {
 		"",
 		"Log level: trace, debug, info, warn, error (default: info)",
 	)
-	pf.String("output", "table", "Output format: table, json, yaml")
+	pf.String("output", "table", "Output format: table, wide, json, yaml")
+	pf.String(
+		"output-file",
+		"",
+		"Write the command's output to a file instead of stdout",
+	)
+	pf.String(
+		"jq",
+		"",
+		"Filter JSON output with a jq expression (e.g. '.[].Name')",
+	)
+	pf.String(
+		"jsonpath",
+		"",
+		"Render output with a JSONPath template (e.g. '{.items[*].Name}')",
+	)
+	pf.Bool(
+		"plain",
+		false,
+		"Plain output without colors, box drawing, or animations",
+	)
+	pf.StringSlice(
+		"columns",
+		nil,
+		"Table columns to show, in order (e.g. name,tags,pulls)",
+	)
+	pf.Bool("no-headers", false, "Omit the header row from table output")
+	pf.String(
+		"table-style",
+		"",
+		"Table style: default, minimal (alias plain), rounded, markdown, github",
+	)
+	pf.Bool(
+		"all-fields",
+		false,
+		"Show every field the API returns instead of the table columns",
+	)
+	pf.Bool("utc", false, "Show timestamps in UTC instead of the local timezone")
+	pf.Bool(
+		"raw",
+		false,
+		"Show sizes as exact byte counts instead of KiB/MiB/GiB",
+	)
+	pf.Bool(
+		"relative-time",
+		false,
+		"Show table timestamps relative to now (e.g. 3 days ago)",
+	)
+	pf.String(
+		"filter",
+		"",
+		"Filter list output by column, e.g. 'roles=admin,name~^ci-' (= != ~ !~)",
+	)
+	pf.String(
+		"sort",
+		"",
+		"Sort list output by a column, optionally descending (e.g. pulls:desc)",
+	)
+	pf.Bool(
+		"curl",
+		false,
+		"Print the equivalent curl command for each API request instead of sending it",
+	)
+	pf.Bool("no-cache", false, "Bypass the response cache for this command")
+	pf.String("record", "", "Record all HTTP interactions to a HAR file")
+	pf.String(
+		"replay",
+		"",
+		"Serve API responses from a HAR file instead of the network",
+	)
 
 	rootCmd.AddCommand(
 		user.NewCmd(),
 		role.NewCmd(),
 		resourcegroup.NewCmd(),
 		policy.NewCmd(),
+		rbac.NewCmd(),
+		bootstrap.NewCmd(),
+		auth.NewCmd(),
 		task.NewCmd(),
 		artifact.NewCmd(),
+		release.NewCmd(),
+		testcmd.NewCmd(),
+		bench.NewCmd(),
+		configcmd.NewCmd(),
+		historycmd.NewCmd(),
+		telemetrycmd.NewCmd(),
+		newDashboardCmd(),
 		newVersionCmd(),
+		newMockServerCmd(),
+		newProxyCmd(),
+		newEditCmd(),
+		newPatchCmd(),
 	)
 }
@@ -18,12 +18,7 @@ func newCreateCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 		RunE:  runCreate,
 	}
-	cmd.Flags().StringSlice("args", nil, "Arguments to pass to the task")
-	cmd.Flags().
-		StringArray("env", nil, "Environment variables in KEY=VALUE format")
-	cmd.Flags().String("callback", "", "Callback URL to invoke on completion")
-	cmd.Flags().Int("retries", 0, "Maximum number of retries")
-	cmd.Flags().String("retention", "", "Retention duration (e.g. 24h)")
+	addCreateFlags(cmd)
 
 	return cmd
 }
@@ -31,12 +26,28 @@ func newCreateCmd() *cobra.Command {
 func runCreate(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.TaskColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.TaskColumns, os.Stdout)
+
+	t, err := c.CreateTask(cmd.Context(), args[0], createOptions(cmd)...)
+	if err != nil {
+		return fmt.Errorf("create task: %w", err)
+	}
+
+	return printer.Print([]any{t})
+}
 
+// addCreateFlags registers the task options shared by create and load.
+func addCreateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("args", nil, "Arguments to pass to the task")
+	cmd.Flags().
+		StringArray("env", nil, "Environment variables in KEY=VALUE format")
+	cmd.Flags().String("callback", "", "Callback URL to invoke on completion")
+	cmd.Flags().Int("retries", 0, "Maximum number of retries")
+	cmd.Flags().String("retention", "", "Retention duration (e.g. 24h)")
+}
+
+// createOptions builds task options from the flags of addCreateFlags.
+func createOptions(cmd *cobra.Command) []enclave.CreateTaskOption {
 	var opts []enclave.CreateTaskOption
 
 	if taskArgs, _ := cmd.Flags().GetStringSlice("args"); len(taskArgs) > 0 {
@@ -68,10 +79,5 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		opts = append(opts, enclave.WithRetention(v))
 	}
 
-	t, err := c.CreateTask(cmd.Context(), args[0], opts...)
-	if err != nil {
-		return fmt.Errorf("create task: %w", err)
-	}
-
-	return printer.Print([]any{t})
+	return opts
 }
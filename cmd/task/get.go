@@ -21,11 +21,7 @@ func newGetCmd() *cobra.Command {
 func runGet(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.TaskColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.TaskColumns, os.Stdout)
 
 	t, err := c.GetTask(cmd.Context(), args[0])
 	if err != nil {
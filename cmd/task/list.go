@@ -2,9 +2,13 @@ package task
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/progress"
+	"cli/internal/watch"
+	"context"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
@@ -18,6 +22,7 @@ func newListCmd() *cobra.Command {
 	}
 	cmd.Flags().
 		String("state", "", "Filter by state (e.g. running, failed, completed)")
+	watch.AddFlag(cmd)
 
 	return cmd
 }
@@ -25,21 +30,25 @@ func newListCmd() *cobra.Command {
 func runList(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.TaskColumns,
-		os.Stdout,
-	)
 
 	var opts []enclave.ListTasksOption
 	if v, _ := cmd.Flags().GetString("state"); v != "" {
 		opts = append(opts, enclave.FilterByState(v))
 	}
 
-	tasks, err := enclave.Collect(c.ListTasks(cmd.Context(), opts...))
-	if err != nil {
-		return fmt.Errorf("list tasks: %w", err)
-	}
-
-	return printer.Print(tasks)
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, output.TaskColumns, w)
+
+		tasks, err := progress.Spin(
+			i18n.T("Listing tasks"),
+			func() ([]enclave.Task, error) {
+				return enclave.Collect(c.ListTasks(ctx, opts...))
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list tasks: %w", err)
+		}
+
+		return printer.Print(tasks)
+	})
 }
@@ -0,0 +1,126 @@
+package task
+
+import (
+	"cli/internal/bench"
+	"cli/internal/client"
+	"cli/internal/output"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+// waitPoll is how often --wait checks a task's state.
+const waitPoll = 250 * time.Millisecond
+
+// finalStates end a task run.
+var finalStates = []string{"completed", "done", "failed", "error"}
+
+func newLoadCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load <source>",
+		Short: "Create tasks at a steady rate and report latency",
+		Long: "Create tasks for a source at a fixed rate for a duration and " +
+			"report latency percentiles and the error rate. New tasks are " +
+			"started on schedule even when earlier ones are slow, so the " +
+			"offered load stays constant.\n\n" +
+			"By default the latency of creating a task is measured; with " +
+			"--wait it runs until the task completed or failed, and failed " +
+			"tasks count as errors. Interrupt to stop early and still get " +
+			"the report.",
+		Example: "  encl task load hello --rate 100 --duration 60s --wait",
+		Args:    cobra.ExactArgs(1),
+		RunE:    runLoad,
+	}
+	addCreateFlags(cmd)
+	cmd.Flags().Float64("rate", 10, "Tasks to create per second")
+	cmd.Flags().
+		Duration("duration", 30*time.Second, "How long to keep creating tasks")
+	cmd.Flags().Bool("wait", false, "Measure until each task finished")
+	cmd.Flags().Int("max-in-flight", 200, "Maximum number of unfinished requests")
+
+	return cmd
+}
+
+func runLoad(cmd *cobra.Command, args []string) error {
+	c := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+	printer := output.FromConfig(cfg, output.BenchColumns, os.Stdout)
+
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	duration, _ := cmd.Flags().GetDuration("duration")
+	wait, _ := cmd.Flags().GetBool("wait")
+	maxInFlight, _ := cmd.Flags().GetInt("max-in-flight")
+	if rate <= 0 || duration <= 0 {
+		return errors.New("--rate and --duration must be positive")
+	}
+
+	ctx, stop := signal.NotifyContext(
+		client.WithoutCache(cmd.Context()),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	source := args[0]
+	opts := createOptions(cmd)
+	name := "task create"
+	if wait {
+		name = "task run"
+	}
+	report := bench.RunRate(
+		ctx,
+		name,
+		rate,
+		duration,
+		maxInFlight,
+		func(ctx context.Context, _ int) (int64, error) {
+			t, err := c.CreateTask(ctx, source, opts...)
+			if err != nil || !wait {
+				return 0, err
+			}
+
+			return 0, waitForTask(ctx, c, &t)
+		},
+	)
+
+	return printer.Print([]any{report})
+}
+
+// waitForTask polls t until it reaches a final state and returns an error
+// if it failed.
+func waitForTask(
+	ctx context.Context,
+	c *enclave.Client,
+	t *enclave.Task,
+) error {
+	for !slices.Contains(finalStates, t.Status.State) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitPoll):
+		}
+		latest, err := c.GetTask(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		*t = latest
+	}
+	if t.Status.State == "failed" || t.Status.State == "error" {
+		return fmt.Errorf(
+			"task %s %s: %s",
+			t.ID,
+			t.Status.State,
+			t.Status.LastError,
+		)
+	}
+
+	return nil
+}
@@ -2,27 +2,55 @@ package task
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/prompt"
+	"cli/internal/styles"
+	"cli/internal/tui"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
 	"time"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func newLogsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "logs <id>",
 		Short: "Get logs for a task",
-		Args:  cobra.ExactArgs(1),
-		RunE:  runLogs,
+		Long: "Get logs for a task.\n\n" +
+			"--since and --until take RFC3339 timestamps or durations " +
+			"relative to now, e.g. 1h. --grep keeps entries whose message " +
+			"matches a regular expression and highlights the matches on a " +
+			"terminal.\n\n" +
+			"--export writes the entries to a file as newline-delimited " +
+			"JSON instead of printing them.\n\n" +
+			"With --tui the logs open in a scrollable viewer: press / to " +
+			"filter by regular expression, f to follow new entries, and q " +
+			"to quit.",
+		Args: cobra.ExactArgs(1),
+		RunE: runLogs,
 	}
 	cmd.Flags().
 		String("level", "", "Filter by log level (trace, debug, info, warn, error)")
 	cmd.Flags().String("issuer", "", "Filter by issuer")
-	cmd.Flags().String("since", "", "Include logs after this time (RFC3339)")
-	cmd.Flags().String("until", "", "Include logs before this time (RFC3339)")
+	cmd.Flags().
+		String("since", "", "Include logs after this time (RFC3339 or duration, e.g. 1h)")
+	cmd.Flags().
+		String("until", "", "Include logs before this time (RFC3339 or duration)")
+	cmd.Flags().
+		String("grep", "", "Only show entries whose message matches this regular expression")
+	cmd.Flags().
+		String("export", "", "Write the entries to this file as NDJSON")
+	cmd.Flags().Bool("tui", false, "Browse the logs in an interactive viewer")
+	cmd.Flags().
+		BoolP("follow", "f", false, "Keep loading new entries (with --tui)")
+	cmd.MarkFlagsMutuallyExclusive("export", "tui")
 
 	return cmd
 }
@@ -30,12 +58,120 @@ func newLogsCmd() *cobra.Command {
 func runLogs(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.TaskLogColumns,
-		os.Stdout,
-	)
+	var grep *regexp.Regexp
+	if v, _ := cmd.Flags().GetString("grep"); v != "" {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return fmt.Errorf("invalid --grep: %w", err)
+		}
+		grep = re
+	}
+	printer := output.FromConfig(cfg, logColumns(grep), os.Stdout)
+
+	if useTUI, _ := cmd.Flags().GetBool("tui"); useTUI {
+		return browseLogs(cmd, c, args[0], grep)
+	}
+
+	opts, err := logOptions(cmd)
+	if err != nil {
+		return err
+	}
+	logs, err := c.GetTaskLogs(cmd.Context(), args[0], opts...)
+	if err != nil {
+		return fmt.Errorf("get task logs: %w", err)
+	}
+	logs = grepLogs(logs, grep)
+
+	if path, _ := cmd.Flags().GetString("export"); path != "" {
+		if err := output.ExportNDJSON(path, logs); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(
+			cmd.OutOrStdout(),
+			i18n.T("Exported %d log entries to %s\n"),
+			len(logs),
+			path,
+		)
+
+		return err
+	}
+
+	return printer.Print(logs)
+}
+
+// grepLogs drops the entries whose message does not match grep, if set.
+func grepLogs(logs []enclave.TaskLog, grep *regexp.Regexp) []enclave.TaskLog {
+	if grep == nil {
+		return logs
+	}
 
+	return slices.DeleteFunc(logs, func(l enclave.TaskLog) bool {
+		return !grep.MatchString(l.Message)
+	})
+}
+
+// logColumns returns the log table columns, with the matches of grep
+// highlighted in messages when writing to a terminal.
+func logColumns(grep *regexp.Regexp) []output.Column {
+	stdout := int(os.Stdout.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if grep == nil || styles.Plain || !term.IsTerminal(stdout) {
+		return output.TaskLogColumns
+	}
+	columns := slices.Clone(output.TaskLogColumns)
+	for i, col := range columns {
+		if col.Header != "MESSAGE" {
+			continue
+		}
+		extract := col.Extract
+		columns[i].Extract = func(r any) string {
+			return grep.ReplaceAllStringFunc(extract(r), func(m string) string {
+				return styles.MatchStyle.Render(m)
+			})
+		}
+	}
+
+	return columns
+}
+
+// browseLogs opens the log viewer. Every load reaches the server, so
+// following picks up new entries.
+func browseLogs(
+	cmd *cobra.Command,
+	c *enclave.Client,
+	id string,
+	grep *regexp.Regexp,
+) error {
+	stdin := int(os.Stdin.Fd())   // #nosec G115 -- file descriptors fit in an int
+	stdout := int(os.Stdout.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) ||
+		!prompt.Interactive() {
+		return errors.New("--tui needs an interactive terminal")
+	}
+	if _, err := logOptions(cmd); err != nil {
+		return err
+	}
+
+	ctx := client.WithoutCache(cmd.Context())
+	fetch := func() ([]enclave.TaskLog, error) {
+		opts, err := logOptions(cmd)
+		if err != nil {
+			return nil, err
+		}
+		logs, err := c.GetTaskLogs(ctx, id, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("get task logs: %w", err)
+		}
+
+		return grepLogs(logs, grep), nil
+	}
+	follow, _ := cmd.Flags().GetBool("follow")
+
+	return tui.RunLogs("Task "+id, fetch, follow)
+}
+
+// logOptions builds the server-side filters from the flags. An open-ended
+// --since range ends now, so it is rebuilt for every load.
+func logOptions(cmd *cobra.Command) ([]enclave.TaskLogOption, error) {
 	var opts []enclave.TaskLogOption
 
 	if v, _ := cmd.Flags().GetString("level"); v != "" {
@@ -51,15 +187,15 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		var from, to time.Time
 		var err error
 		if since != "" {
-			from, err = time.Parse(time.RFC3339, since)
+			from, err = parseLogTime(since)
 			if err != nil {
-				return fmt.Errorf("invalid --since: %w", err)
+				return nil, fmt.Errorf("invalid --since: %w", err)
 			}
 		}
 		if until != "" {
-			to, err = time.Parse(time.RFC3339, until)
+			to, err = parseLogTime(until)
 			if err != nil {
-				return fmt.Errorf("invalid --until: %w", err)
+				return nil, fmt.Errorf("invalid --until: %w", err)
 			}
 		}
 		if from.IsZero() {
@@ -71,10 +207,14 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		opts = append(opts, enclave.FilterLogByTimeRange(from, to))
 	}
 
-	logs, err := c.GetTaskLogs(cmd.Context(), args[0], opts...)
-	if err != nil {
-		return fmt.Errorf("get task logs: %w", err)
+	return opts, nil
+}
+
+// parseLogTime parses an RFC3339 timestamp or a duration back from now.
+func parseLogTime(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
 	}
 
-	return printer.Print(logs)
+	return time.Parse(time.RFC3339, s)
 }
@@ -13,6 +13,7 @@ func NewCmd() *cobra.Command {
 		newGetCmd(),
 		newCreateCmd(),
 		newLogsCmd(),
+		newLoadCmd(),
 	)
 
 	return cmd
@@ -0,0 +1,36 @@
+package telemetry
+
+import (
+	"cli/internal/config"
+	"cli/internal/i18n"
+	it "cli/internal/telemetry"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newOffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "off",
+		Short: "Stop recording usage metrics and discard unsent ones",
+		Args:  cobra.NoArgs,
+		RunE:  runOff,
+	}
+}
+
+func runOff(cmd *cobra.Command, _ []string) error {
+	if _, err := config.Set("telemetry", "off"); err != nil {
+		return err
+	}
+	pending, _ := it.Pending()
+	if err := it.Clear(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(
+		cmd.OutOrStdout(),
+		i18n.T("Telemetry disabled; %d unsent events discarded.\n"),
+		pending,
+	)
+
+	return err
+}
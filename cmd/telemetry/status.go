@@ -0,0 +1,55 @@
+package telemetry
+
+import (
+	"cli/internal/config"
+	"cli/internal/i18n"
+	it "cli/internal/telemetry"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether usage metrics are recorded",
+		Args:  cobra.NoArgs,
+		RunE:  runStatus,
+	}
+}
+
+func runStatus(cmd *cobra.Command, _ []string) error {
+	cfg, err := config.Load(cmd.Root().PersistentFlags())
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	path, err := it.SpoolPath()
+	if err != nil {
+		return err
+	}
+	pending, err := it.Pending()
+	if err != nil {
+		return err
+	}
+
+	state := "off"
+	if cfg.TelemetryEnabled() {
+		state = "on"
+	}
+	endpoint := cfg.TelemetryEndpoint
+	if endpoint == "" {
+		endpoint = i18n.T("none (kept locally)")
+	}
+	_, err = fmt.Fprintf(
+		cmd.OutOrStdout(),
+		i18n.T(
+			"Telemetry: %s\nEndpoint:  %s\nPending:   %d events in %s\n",
+		),
+		state,
+		endpoint,
+		pending,
+		path,
+	)
+
+	return err
+}
@@ -0,0 +1,23 @@
+package telemetry
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd returns the "telemetry" command group.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Inspect or disable anonymous usage metrics",
+		Long: "Anonymous usage metrics are off unless enabled with " +
+			"\"encl config set telemetry on\". Only the command run, the " +
+			"CLI version, the platform, and the class of error a command " +
+			"failed with are recorded; never arguments, names, or messages.",
+	}
+	cmd.AddCommand(
+		newStatusCmd(),
+		newOffCmd(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,290 @@
+// Package test implements encl test, which runs suites of task invocations
+// and checks their outcome.
+package test
+
+import (
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/parallel"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// pollInterval is how often a running task is checked.
+	pollInterval = 500 * time.Millisecond
+	// defaultTimeout bounds each test unless the suite sets a timeout.
+	defaultTimeout = time.Minute
+)
+
+// terminalStates are the task states that end an invocation.
+var terminalStates = []string{"completed", "done", "failed", "error"}
+
+// suite is the file format read by encl test.
+type suite struct {
+	Tests []testCase `yaml:"tests"`
+}
+
+// testCase is one invocation and its expected outcome.
+type testCase struct {
+	Name    string            `yaml:"name"`
+	Source  string            `yaml:"source"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+	Timeout time.Duration     `yaml:"timeout"`
+	Expect  expectation       `yaml:"expect"`
+}
+
+// expectation lists the checks applied to a finished task.
+type expectation struct {
+	// State is the final task state (default completed).
+	State string `yaml:"state"`
+	// Logs must each appear in at least one log message.
+	Logs []string `yaml:"logs"`
+	// Error must appear in the task's last error.
+	Error string `yaml:"error"`
+}
+
+// result is the outcome of one test.
+type result struct {
+	Name     string
+	TaskID   string
+	Duration time.Duration
+	// Failures lists unmet expectations; empty when the test passed.
+	Failures []string
+}
+
+// NewCmd returns the "test" command.
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Run a suite of task invocations and check their outcome",
+		Long: "Run the invocations defined in a suite file as tasks, wait for " +
+			"them to finish, and compare the final state, error, and log " +
+			"messages to the expectations. Exits non-zero when a test fails.\n\n" +
+			"Suite format:\n\n" +
+			"  tests:\n" +
+			"    - name: greets\n" +
+			"      source: hello\n" +
+			"      args: [world]\n" +
+			"      env: {GREETING: hi}\n" +
+			"      timeout: 30s\n" +
+			"      expect:\n" +
+			"        state: completed\n" +
+			"        logs: [\"hi world\"]\n",
+		Args: cobra.NoArgs,
+		RunE: runTest,
+	}
+	cmd.Flags().StringP("file", "f", "", "Suite file (YAML)")
+	cmd.Flags().String("run", "", "Only run tests whose name matches this regex")
+	_ = cmd.MarkFlagRequired("file")
+	parallel.AddFlag(cmd)
+
+	return cmd
+}
+
+func runTest(cmd *cobra.Command, _ []string) error {
+	c := client.FromContext(cmd.Context())
+
+	file, _ := cmd.Flags().GetString("file")
+	tests, err := loadSuite(file)
+	if err != nil {
+		return err
+	}
+	if pattern, _ := cmd.Flags().GetString("run"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid --run: %w", err)
+		}
+		tests = slices.DeleteFunc(tests, func(t testCase) bool {
+			return !re.MatchString(t.Name)
+		})
+	}
+	if len(tests) == 0 {
+		return errors.New("no tests to run")
+	}
+
+	// Polling must reach the server every time.
+	ctx := client.WithoutCache(cmd.Context())
+	results, _ := parallel.Map(
+		cmd,
+		i18n.T("Running tests"),
+		tests,
+		func(_ context.Context, t testCase) (result, error) {
+			return runCase(ctx, c, &t), nil
+		},
+	)
+
+	failed := report(cmd.OutOrStdout(), results)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, len(results))
+	}
+
+	return nil
+}
+
+// loadSuite reads and validates a suite file.
+func loadSuite(path string) ([]testCase, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read suite: %w", err)
+	}
+	var s suite
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i := range s.Tests {
+		t := &s.Tests[i]
+		if t.Source == "" {
+			return nil, fmt.Errorf("%s: test %d has no source", path, i+1)
+		}
+		if t.Name == "" {
+			t.Name = fmt.Sprintf("%s#%d", t.Source, i+1)
+		}
+	}
+
+	return s.Tests, nil
+}
+
+// runCase starts the task for t, waits for it to finish, and checks it.
+func runCase(ctx context.Context, c *enclave.Client, t *testCase) result {
+	start := time.Now()
+	r := result{Name: t.Name}
+	fail := func(format string, args ...any) result {
+		r.Failures = append(r.Failures, fmt.Sprintf(format, args...))
+		r.Duration = time.Since(start)
+
+		return r
+	}
+
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	task, err := c.CreateTask(ctx, t.Source, taskOptions(t)...)
+	if err != nil {
+		return fail("create task: %v", err)
+	}
+	r.TaskID = task.ID
+	for !slices.Contains(terminalStates, task.Status.State) {
+		select {
+		case <-ctx.Done():
+			return fail(
+				"still %s after %s",
+				task.Status.State,
+				timeout,
+			)
+		case <-time.After(pollInterval):
+		}
+		if task, err = c.GetTask(ctx, r.TaskID); err != nil {
+			return fail("get task: %v", err)
+		}
+	}
+
+	want := t.Expect.State
+	if want == "" {
+		want = "completed"
+	}
+	if task.Status.State != want {
+		r.Failures = append(r.Failures, fmt.Sprintf(
+			"state: want %s, got %s",
+			want,
+			task.Status.State,
+		))
+	}
+	if t.Expect.Error != "" &&
+		!strings.Contains(task.Status.LastError, t.Expect.Error) {
+		r.Failures = append(r.Failures, fmt.Sprintf(
+			"error: want %q in %q",
+			t.Expect.Error,
+			task.Status.LastError,
+		))
+	}
+	if len(t.Expect.Logs) > 0 {
+		logs, err := c.GetTaskLogs(ctx, r.TaskID)
+		if err != nil {
+			return fail("get task logs: %v", err)
+		}
+		for _, want := range t.Expect.Logs {
+			if !slices.ContainsFunc(logs, func(l enclave.TaskLog) bool {
+				return strings.Contains(l.Message, want)
+			}) {
+				r.Failures = append(
+					r.Failures,
+					fmt.Sprintf("logs: no message contains %q", want),
+				)
+			}
+		}
+	}
+	r.Duration = time.Since(start)
+
+	return r
+}
+
+func taskOptions(t *testCase) []enclave.CreateTaskOption {
+	var opts []enclave.CreateTaskOption
+	if len(t.Args) > 0 {
+		opts = append(opts, enclave.WithArgs(t.Args...))
+	}
+	if len(t.Env) > 0 {
+		keys := make([]string, 0, len(t.Env))
+		for k := range t.Env {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		envs := make([]enclave.EnvironmentVariable, 0, len(keys))
+		for _, k := range keys {
+			envs = append(envs, enclave.EnvironmentVariable{Key: k, Value: t.Env[k]})
+		}
+		opts = append(opts, enclave.WithEnv(envs...))
+	}
+
+	return opts
+}
+
+// report prints one line per test, the failed expectations, and a summary.
+// It returns the number of failed tests.
+func report(w io.Writer, results []result) int {
+	failed := 0
+	for _, r := range results {
+		status := "PASS"
+		if len(r.Failures) > 0 {
+			status = "FAIL"
+			failed++
+		}
+		_, _ = fmt.Fprintf(
+			w,
+			"%s  %s  (%s, task %s)\n",
+			status,
+			r.Name,
+			r.Duration.Round(time.Millisecond),
+			r.TaskID,
+		)
+		for _, f := range r.Failures {
+			_, _ = fmt.Fprintln(w, "      "+f)
+		}
+	}
+	_, _ = fmt.Fprintf(
+		w,
+		i18n.T("\n%d passed, %d failed\n"),
+		len(results)-failed,
+		failed,
+	)
+
+	return failed
+}
@@ -2,7 +2,9 @@ package user
 
 import (
 	"cli/internal/client"
+	"cli/internal/history"
 	"cli/internal/output"
+	"cli/internal/password"
 	"fmt"
 	"os"
 
@@ -15,17 +17,18 @@ func newCreateCmd() *cobra.Command {
 		Short: "Create a new user",
 		Args:  cobra.ExactArgs(3),
 		RunE:  runCreate,
+		// Keep the password out of the local command history.
+		Annotations: map[string]string{history.SensitiveArgs: "2"},
 	}
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.UserColumns, os.Stdout)
+	if err := password.Check(&cfg.PasswordPolicy, args[2]); err != nil {
+		return err
+	}
 
 	u, err := c.CreateUser(cmd.Context(), args[0], args[2], args[1])
 	if err != nil {
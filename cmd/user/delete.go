@@ -2,35 +2,61 @@ package user
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/parallel"
+	"cli/internal/picker"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
 )
 
 func newDeleteCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "delete <username>",
-		Short: "Delete a user",
-		Args:  cobra.ExactArgs(1),
+	cmd := &cobra.Command{
+		Use:   "delete <username>...",
+		Short: "Delete one or more users",
+		Args:  picker.MinimumNArgs(1),
 		RunE:  runDelete,
 	}
+	parallel.AddFlag(cmd)
+
+	return cmd
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.UserColumns, os.Stdout)
 
-	u, err := c.DeleteUser(cmd.Context(), args[0])
+	args, err := picker.Fill(cmd.Context(), args, picker.Users(c))
 	if err != nil {
-		return fmt.Errorf("delete user: %w", err)
+		return err
+	}
+
+	deleted, err := parallel.Map(
+		cmd,
+		i18n.T("Deleting users"),
+		args,
+		func(ctx context.Context, name string) (enclave.User, error) {
+			v, err := c.DeleteUser(ctx, name)
+			if err != nil {
+				return v, fmt.Errorf(
+					"delete user %s: %w",
+					name,
+					picker.Suggest(ctx, picker.Users(c), name, err),
+				)
+			}
+
+			return v, nil
+		},
+	)
+	if len(deleted) > 0 {
+		err = errors.Join(printer.Print(deleted), err)
 	}
 
-	return printer.Print([]any{u})
+	return err
 }
@@ -3,6 +3,7 @@ package user
 import (
 	"cli/internal/client"
 	"cli/internal/output"
+	"cli/internal/picker"
 	"fmt"
 	"os"
 
@@ -13,7 +14,7 @@ func newGetCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get <username>",
 		Short: "Get a user by username",
-		Args:  cobra.ExactArgs(1),
+		Args:  picker.ExactArgs(1),
 		RunE:  runGet,
 	}
 }
@@ -21,15 +22,19 @@ func newGetCmd() *cobra.Command {
 func runGet(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.UserColumns, os.Stdout)
+
+	args, err := picker.Fill(cmd.Context(), args, picker.Users(c))
+	if err != nil {
+		return err
+	}
 
 	u, err := c.GetUser(cmd.Context(), args[0])
 	if err != nil {
-		return fmt.Errorf("get user: %w", err)
+		return fmt.Errorf(
+			"get user: %w",
+			picker.Suggest(cmd.Context(), picker.Users(c), args[0], err),
+		)
 	}
 
 	return printer.Print([]any{u})
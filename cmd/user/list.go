@@ -2,35 +2,46 @@ package user
 
 import (
 	"cli/internal/client"
+	"cli/internal/i18n"
 	"cli/internal/output"
+	"cli/internal/progress"
+	"cli/internal/watch"
+	"context"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 	"github.com/spf13/cobra"
 )
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all users",
 		RunE:  runList,
 	}
+	watch.AddFlag(cmd)
+
+	return cmd
 }
 
 func runList(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
-
-	users, err := enclave.Collect(c.ListUsers(cmd.Context()))
-	if err != nil {
-		return fmt.Errorf("list users: %w", err)
-	}
 
-	return printer.Print(users)
+	return watch.Run(cmd, func(ctx context.Context, w io.Writer) error {
+		printer := output.FromConfig(cfg, output.UserColumns, w)
+
+		users, err := progress.Spin(
+			i18n.T("Listing users"),
+			func() ([]enclave.User, error) {
+				return enclave.Collect(c.ListUsers(ctx))
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("list users: %w", err)
+		}
+
+		return printer.Print(users)
+	})
 }
@@ -20,11 +20,7 @@ func newDeleteCmd() *cobra.Command {
 func runDelete(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.UserColumns, os.Stdout)
 
 	u, err := c.DeleteMe(cmd.Context())
 	if err != nil {
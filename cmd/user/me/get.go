@@ -20,11 +20,7 @@ func newGetCmd() *cobra.Command {
 func runGet(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.UserColumns, os.Stdout)
 
 	u, err := c.GetMe(cmd.Context())
 	if err != nil {
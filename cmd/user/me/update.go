@@ -3,6 +3,7 @@ package me
 import (
 	"cli/internal/client"
 	"cli/internal/output"
+	"cli/internal/password"
 	"fmt"
 	"os"
 
@@ -25,17 +26,16 @@ func newUpdateCmd() *cobra.Command {
 func runUpdate(cmd *cobra.Command, _ []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.UserColumns, os.Stdout)
 
 	var opts []enclave.UpdateUserOption
 	if v, _ := cmd.Flags().GetString("display-name"); v != "" {
 		opts = append(opts, enclave.WithDisplayName(v))
 	}
 	if v, _ := cmd.Flags().GetString("password"); v != "" {
+		if err := password.Check(&cfg.PasswordPolicy, v); err != nil {
+			return err
+		}
 		opts = append(opts, enclave.WithPassword(v))
 	}
 
@@ -0,0 +1,234 @@
+package user
+
+import (
+	"cli/internal/ci"
+	"cli/internal/client"
+	"cli/internal/config"
+	"cli/internal/i18n"
+	"cli/internal/password"
+	"cli/internal/rbac"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync --from <profile>",
+		Short: "Copy missing users from another server",
+		Long: "Create the users of the server of the --from profile that " +
+			"are missing on the target server: the server of --to, or the " +
+			"current one.\n\n" +
+			"Passwords cannot be read from a server, so each copied user " +
+			"gets a generated password that follows password_policy. It is " +
+			"printed once the user is created.\n\n" +
+			"With --roles, copied users keep their roles, and users on both " +
+			"servers get the roles they are missing on the target. Roles " +
+			"that do not exist on the target are skipped. Users whose " +
+			"display names differ are reported and left unchanged.",
+		Example: "  encl user sync --from staging --to prod --dry-run\n" +
+			"  encl user sync --from staging --roles -y",
+		Args: cobra.NoArgs,
+		RunE: runSync,
+	}
+	cmd.Flags().String("from", "", "Profile of the server to copy users from")
+	cmd.Flags().
+		String("to", "", "Profile of the server to copy users to (default: current)")
+	cmd.Flags().Bool("roles", false, "Also copy role assignments")
+	cmd.Flags().Bool("dry-run", false, "Only show what would change")
+	rbac.AddYesFlag(cmd)
+	rbac.AddAtomicFlag(cmd)
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func runSync(cmd *cobra.Command, _ []string) error {
+	from, _ := cmd.Flags().GetString("from")
+	source, _, err := profileClient(from)
+	if err != nil {
+		return err
+	}
+	if to, _ := cmd.Flags().GetString("to"); to != "" {
+		target, cfg, err := profileClient(to)
+		if err != nil {
+			return err
+		}
+		// Changes are recorded in the history against the target.
+		ctx := client.WithClient(cmd.Context(), target)
+		cmd.SetContext(client.WithConfig(ctx, cfg))
+	}
+	target := client.FromContext(cmd.Context())
+	cfg := client.ConfigFromContext(cmd.Context())
+
+	srcUsers, err := enclave.Collect(source.ListUsers(cmd.Context()))
+	if err != nil {
+		return fmt.Errorf("profile %s: list users: %w", from, err)
+	}
+	dstUsers, err := enclave.Collect(target.ListUsers(cmd.Context()))
+	if err != nil {
+		return fmt.Errorf("list users: %w", err)
+	}
+	roles, err := enclave.Collect(target.ListRoles(cmd.Context()))
+	if err != nil {
+		return fmt.Errorf("list roles: %w", err)
+	}
+	known := make([]string, 0, len(roles))
+	for _, r := range roles {
+		known = append(known, r.Name)
+	}
+
+	withRoles, _ := cmd.Flags().GetBool("roles")
+	out := cmd.OutOrStdout()
+	var steps []rbac.Step
+	var conflicts []string
+	for _, u := range srcUsers {
+		var wanted []string
+		if withRoles {
+			for _, r := range u.Roles {
+				if slices.Contains(known, r) {
+					wanted = append(wanted, r)
+				} else {
+					conflicts = append(conflicts, i18n.Sprintf(
+						"user %s: role %s does not exist",
+						u.Name,
+						r,
+					))
+				}
+			}
+		}
+
+		i := slices.IndexFunc(dstUsers, func(d enclave.User) bool {
+			return d.Name == u.Name
+		})
+		if i < 0 {
+			pw, err := password.Generate(&cfg.PasswordPolicy, 24)
+			if err != nil {
+				return err
+			}
+			ci.Mask(pw)
+			steps = append(steps, syncCreateStep(out, u, pw, wanted))
+
+			continue
+		}
+
+		existing := dstUsers[i]
+		if existing.DisplayName != u.DisplayName {
+			conflicts = append(conflicts, i18n.Sprintf(
+				"user %s: display name %q differs from %q",
+				u.Name,
+				existing.DisplayName,
+				u.DisplayName,
+			))
+		}
+		if step, ok := syncRolesStep(existing, wanted); ok {
+			steps = append(steps, step)
+		}
+	}
+
+	for _, c := range conflicts {
+		_, _ = fmt.Fprintln(out, i18n.T("Not synchronized:"), c)
+	}
+
+	return rbac.Apply(cmd, steps)
+}
+
+// profileClient returns a client and the config of the named profile.
+func profileClient(name string) (*enclave.Client, *config.Config, error) {
+	cfg, err := config.LoadProfile(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load config: %w", err)
+	}
+	c, err := client.NewAdditional(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+
+	return c, cfg, nil
+}
+
+// syncCreateStep creates u with the generated password pw and prints the
+// password once the user exists.
+func syncCreateStep(
+	out io.Writer,
+	u enclave.User,
+	pw string,
+	roles []string,
+) rbac.Step {
+	desc := i18n.Sprintf("create user %s", u.Name)
+	if len(roles) > 0 {
+		desc += i18n.Sprintf(" with %s", strings.Join(roles, ", "))
+	}
+
+	return rbac.Step{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			if _, err := c.CreateUser(
+				ctx,
+				u.Name,
+				pw,
+				u.DisplayName,
+				enclave.WithRoles(roles...),
+			); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(
+				out,
+				i18n.T("  password of %s: %s\n"),
+				u.Name,
+				pw,
+			)
+
+			return err
+		},
+		Revert: func(ctx context.Context, c *enclave.Client) error {
+			_, err := c.DeleteUser(ctx, u.Name)
+
+			return err
+		},
+	}
+}
+
+// syncRolesStep adds the roles of wanted that existing lacks, if any.
+func syncRolesStep(existing enclave.User, wanted []string) (rbac.Step, bool) {
+	roles := slices.Clone(existing.Roles)
+	for _, r := range wanted {
+		if !slices.Contains(roles, r) {
+			roles = append(roles, r)
+		}
+	}
+	if len(roles) == len(existing.Roles) {
+		return rbac.Step{}, false
+	}
+
+	return rbac.Step{
+		Desc: i18n.Sprintf(
+			"add roles %s to user %s",
+			strings.Join(roles[len(existing.Roles):], ", "),
+			existing.Name,
+		),
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			_, err := c.UpdateUser(
+				ctx,
+				existing.Name,
+				enclave.WithUserRoles(roles...),
+			)
+
+			return err
+		},
+		Revert: func(ctx context.Context, c *enclave.Client) error {
+			_, err := c.UpdateUser(
+				ctx,
+				existing.Name,
+				enclave.WithUserRoles(existing.Roles...),
+			)
+
+			return err
+		},
+	}, true
+}
@@ -3,6 +3,8 @@ package user
 import (
 	"cli/internal/client"
 	"cli/internal/output"
+	"cli/internal/password"
+	"cli/internal/picker"
 	"fmt"
 	"os"
 
@@ -14,7 +16,7 @@ func newUpdateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "update <username>",
 		Short: "Update a user",
-		Args:  cobra.ExactArgs(1),
+		Args:  picker.ExactArgs(1),
 		RunE:  runUpdate,
 	}
 	cmd.Flags().String("display-name", "", "New display name")
@@ -26,23 +28,30 @@ func newUpdateCmd() *cobra.Command {
 func runUpdate(cmd *cobra.Command, args []string) error {
 	c := client.FromContext(cmd.Context())
 	cfg := client.ConfigFromContext(cmd.Context())
-	printer := output.New(
-		output.ParseFormat(cfg.Output),
-		output.UserColumns,
-		os.Stdout,
-	)
+	printer := output.FromConfig(cfg, output.UserColumns, os.Stdout)
+
+	args, err := picker.Fill(cmd.Context(), args, picker.Users(c))
+	if err != nil {
+		return err
+	}
 
 	var opts []enclave.UpdateUserOption
 	if v, _ := cmd.Flags().GetString("display-name"); v != "" {
 		opts = append(opts, enclave.WithDisplayName(v))
 	}
 	if v, _ := cmd.Flags().GetString("password"); v != "" {
+		if err := password.Check(&cfg.PasswordPolicy, v); err != nil {
+			return err
+		}
 		opts = append(opts, enclave.WithPassword(v))
 	}
 
 	u, err := c.UpdateUser(cmd.Context(), args[0], opts...)
 	if err != nil {
-		return fmt.Errorf("update user: %w", err)
+		return fmt.Errorf(
+			"update user: %w",
+			picker.Suggest(cmd.Context(), picker.Users(c), args[0], err),
+		)
 	}
 
 	return printer.Print([]any{u})
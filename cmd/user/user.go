@@ -18,6 +18,7 @@ func NewCmd() *cobra.Command {
 		newCreateCmd(),
 		newUpdateCmd(),
 		newDeleteCmd(),
+		newSyncCmd(),
 		me.NewCmd(),
 	)
 
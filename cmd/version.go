@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"fmt"
-
+	"cli/internal/i18n"
 	iv "cli/internal/version"
+	"fmt"
 
 	"github.com/spf13/cobra"
 )
@@ -23,7 +23,11 @@ func newVersionCmd() *cobra.Command {
 			// Check remote version (best-effort)
 			remote, newer, err := iv.CheckRemote(appVersion)
 			if err == nil && newer {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "New version available:", remote)
+				_, _ = fmt.Fprintln(
+					cmd.OutOrStdout(),
+					i18n.T("New version available:"),
+					remote,
+				)
 			}
 
 			return nil
@@ -0,0 +1,205 @@
+// Package bench runs repeated operations with bounded concurrency and
+// summarizes their latency and throughput.
+package bench
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Op is a single measured operation. It returns the number of bytes it
+// transferred, if any.
+type Op func(ctx context.Context, i int) (int64, error)
+
+// Report summarizes a benchmark run.
+type Report struct {
+	Operation   string        `json:"operation"`
+	Requests    int           `json:"requests"`
+	Errors      int           `json:"errors"`
+	Concurrency int           `json:"concurrency"`
+	Elapsed     time.Duration `json:"elapsed"`
+	Bytes       int64         `json:"bytes"`
+	Min         time.Duration `json:"min"`
+	P50         time.Duration `json:"p50"`
+	P90         time.Duration `json:"p90"`
+	P99         time.Duration `json:"p99"`
+	Max         time.Duration `json:"max"`
+	// FirstError is the first failure seen, to explain a non-zero Errors.
+	FirstError string `json:"firstError,omitempty"`
+}
+
+// Rate returns the completed operations per second.
+func (r *Report) Rate() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// Throughput returns the transferred bytes per second.
+func (r *Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(r.Bytes) / r.Elapsed.Seconds()
+}
+
+// ErrorRate returns the share of failed operations, from 0 to 1.
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Run calls op n times, at most concurrency at once, and reports the
+// latencies of the successful calls. Cancelling ctx stops starting new
+// calls.
+func Run(
+	ctx context.Context,
+	name string,
+	n, concurrency int,
+	op Op,
+) Report {
+	concurrency = max(concurrency, 1)
+	rec := newRecorder(name, concurrency)
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			rec.measure(ctx, i, op)
+		}()
+	}
+	wg.Wait()
+
+	return rec.finish()
+}
+
+// RunRate starts op rate times per second for duration, regardless of how
+// long earlier calls take, so slow responses do not lower the offered load.
+// At most maxInFlight calls run at once; ticks beyond that are counted as
+// errors. Cancelling ctx stops starting new calls.
+func RunRate(
+	ctx context.Context,
+	name string,
+	rate float64,
+	duration time.Duration,
+	maxInFlight int,
+	op Op,
+) Report {
+	maxInFlight = max(maxInFlight, 1)
+	rec := newRecorder(name, maxInFlight)
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer ticker.Stop()
+	deadline := time.After(duration)
+	sem := make(chan struct{}, maxInFlight)
+	var wg sync.WaitGroup
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+		case <-deadline:
+		case <-ticker.C:
+			select {
+			case sem <- struct{}{}:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-sem }()
+					rec.measure(ctx, i, op)
+				}()
+			default:
+				rec.record(0, 0, errSaturated)
+			}
+
+			continue
+		}
+
+		break
+	}
+	wg.Wait()
+
+	return rec.finish()
+}
+
+// errSaturated marks calls skipped because maxInFlight calls were running.
+var errSaturated = errors.New(
+	"too many calls in flight; raise the limit or lower the rate",
+)
+
+// recorder collects results from concurrent calls.
+type recorder struct {
+	mu        sync.Mutex
+	start     time.Time
+	latencies []time.Duration
+	report    Report
+}
+
+func newRecorder(name string, concurrency int) *recorder {
+	return &recorder{
+		start:  time.Now(),
+		report: Report{Operation: name, Concurrency: concurrency},
+	}
+}
+
+func (r *recorder) measure(ctx context.Context, i int, op Op) {
+	t := time.Now()
+	size, err := op(ctx, i)
+	r.record(time.Since(t), size, err)
+}
+
+func (r *recorder) record(d time.Duration, size int64, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Requests++
+	if err != nil {
+		r.report.Errors++
+		if r.report.FirstError == "" {
+			r.report.FirstError = err.Error()
+		}
+
+		return
+	}
+	r.report.Bytes += size
+	r.latencies = append(r.latencies, d)
+}
+
+func (r *recorder) finish() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.report.Elapsed = time.Since(r.start)
+
+	l := r.latencies
+	slices.Sort(l)
+	if len(l) > 0 {
+		r.report.Min = l[0]
+		r.report.P50 = percentile(l, 50)
+		r.report.P90 = percentile(l, 90)
+		r.report.P99 = percentile(l, 99)
+		r.report.Max = l[len(l)-1]
+	}
+
+	return r.report
+}
+
+// percentile returns the p-th percentile of sorted, using the nearest-rank
+// method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+
+	return sorted[max(rank, 1)-1]
+}
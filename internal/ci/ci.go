@@ -0,0 +1,176 @@
+// Package ci formats output for CI pipeline UIs: errors and warnings become
+// workflow annotations, verbose sections collapse into groups, and secrets
+// are masked in logs.
+package ci
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supported CI systems.
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+)
+
+var (
+	// mode is the CI system set by --ci, or empty when off.
+	mode string
+
+	mu      sync.Mutex
+	secrets []string
+	// sections counts GitLab sections so their names stay unique.
+	sections int
+)
+
+// Set selects the CI system: github, gitlab, auto to detect it from the
+// environment, or empty for none.
+func Set(name string) error {
+	switch strings.ToLower(name) {
+	case "", "none", "off":
+		mode = ""
+	case GitHub:
+		mode = GitHub
+	case GitLab:
+		mode = GitLab
+	case "auto":
+		mode = detect()
+	default:
+		return fmt.Errorf(
+			"unknown CI system %q (available: github, gitlab, auto)",
+			name,
+		)
+	}
+
+	return nil
+}
+
+// Mode returns the active CI system, or "" when CI output is off.
+func Mode() string {
+	return mode
+}
+
+// detect returns the CI system the environment variables of the runner
+// point to.
+func detect() string {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return GitHub
+	case os.Getenv("GITLAB_CI") == "true":
+		return GitLab
+	default:
+		return ""
+	}
+}
+
+// Mask hides secret in all further log output. GitHub masks it in the whole
+// job log; elsewhere it is replaced in messages written by this package.
+func Mask(secret string) {
+	if mode == "" || secret == "" {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	secrets = append(secrets, secret)
+	if mode == GitHub {
+		_, _ = fmt.Fprintf(os.Stderr, "::add-mask::%s\n", escape(secret))
+	}
+}
+
+// Redact replaces every masked secret in s.
+func Redact(s string) string {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, "***")
+	}
+
+	return s
+}
+
+// Error writes msg as an error annotation.
+func Error(w io.Writer, msg string) {
+	annotate(w, "error", msg)
+}
+
+// Warning writes msg as a warning annotation.
+func Warning(w io.Writer, msg string) {
+	annotate(w, "warning", msg)
+}
+
+// annotate writes msg at level, "error" or "warning". GitLab has no
+// annotations, so the message is highlighted in the job log instead.
+func annotate(w io.Writer, level, msg string) {
+	msg = Redact(msg)
+	switch mode {
+	case GitHub:
+		_, _ = fmt.Fprintf(w, "::%s::%s\n", level, escape(msg))
+	case GitLab:
+		color := "31"
+		if level == "warning" {
+			color = "33"
+		}
+		_, _ = fmt.Fprintf(
+			w,
+			"\x1b[%s;1m%s:\x1b[0m %s\n",
+			color,
+			strings.ToUpper(level),
+			msg,
+		)
+	default:
+		_, _ = fmt.Fprintf(w, "%s: %s\n", level, msg)
+	}
+}
+
+// sectionName matches the characters GitLab allows in section names.
+var sectionName = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// Group starts a collapsible section titled title on w and returns the
+// function that ends it. Outside CI both are no-ops.
+func Group(w io.Writer, title string) func() {
+	switch mode {
+	case GitHub:
+		_, _ = fmt.Fprintf(w, "::group::%s\n", escape(title))
+
+		return func() { _, _ = fmt.Fprintln(w, "::endgroup::") }
+	case GitLab:
+		mu.Lock()
+		sections++
+		name := fmt.Sprintf(
+			"encl_%d_%s",
+			sections,
+			strings.Trim(sectionName.ReplaceAllString(title, "_"), "_"),
+		)
+		mu.Unlock()
+		_, _ = fmt.Fprintf(
+			w,
+			"\x1b[0Ksection_start:%d:%s[collapsed=true]\r\x1b[0K%s\n",
+			time.Now().Unix(),
+			name,
+			title,
+		)
+
+		return func() {
+			_, _ = fmt.Fprintf(
+				w,
+				"\x1b[0Ksection_end:%d:%s\r\x1b[0K\n",
+				time.Now().Unix(),
+				name,
+			)
+		}
+	default:
+		return func() {}
+	}
+}
+
+// escape encodes the characters GitHub workflow commands treat specially.
+func escape(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").
+		Replace(s)
+}
@@ -0,0 +1,64 @@
+package ci
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// LogWriter returns a zerolog writer for CI output. Warnings and errors
+// become annotations on out; other levels go to console.
+func LogWriter(console, out io.Writer) zerolog.LevelWriter {
+	return &logWriter{console: console, out: out}
+}
+
+type logWriter struct {
+	console io.Writer
+	out     io.Writer
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	return w.console.Write(p)
+}
+
+func (w *logWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < zerolog.WarnLevel || level > zerolog.PanicLevel {
+		return w.console.Write(p)
+	}
+	var event struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(p, &event); err != nil {
+		return w.console.Write(p)
+	}
+	msg := event.Message
+	if event.Error != "" {
+		msg += ": " + event.Error
+	}
+	if level == zerolog.WarnLevel {
+		Warning(w.out, msg)
+	} else {
+		Error(w.out, msg)
+	}
+
+	return len(p), nil
+}
+
+// Redacting returns a writer that masks secrets in everything written to w.
+func Redacting(w io.Writer) io.Writer {
+	return redactWriter{w}
+}
+
+type redactWriter struct {
+	w io.Writer
+}
+
+func (r redactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, Redact(string(p))); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"cli/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// errNoSize is returned when the server omits the content length.
+var errNoSize = errors.New("server did not report a size")
+
+// ArtifactSize returns the stored size in bytes of an artifact version. The
+// artifact metadata does not carry it, so it is read from the Content-Length
+// of a HEAD request for the raw content. The API only documents GET for it,
+// so servers may count the request as a pull or reject it, leaving the size
+// unknown. The SDK has no HEAD call; the request goes through the same
+// http.DefaultTransport middleware as the SDK's requests.
+func ArtifactSize(
+	ctx context.Context,
+	cfg *config.Config,
+	namespace, name, hash string,
+) (int64, error) {
+	u := strings.TrimRight(cfg.APIURL, "/") + "/v1/artifact/raw/" +
+		url.PathEscape(namespace) + "/" + url.PathEscape(name) +
+		"/hash/" + url.PathEscape(hash)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, http.NoBody)
+	if err != nil {
+		return 0, fmt.Errorf("artifact size: %w", err)
+	}
+	req.SetBasicAuth(cfg.Username, cfg.Password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("artifact size: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("artifact size: %s", resp.Status)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("artifact size: %w", errNoSize)
+	}
+
+	return resp.ContentLength, nil
+}
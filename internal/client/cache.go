@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultCacheTTL applies when caching is enabled without an explicit TTL.
+const defaultCacheTTL = 5 * time.Minute
+
+type (
+	noCacheKey struct{}
+	staleKey   struct{}
+)
+
+// WithoutCache returns a context whose requests bypass the response cache,
+// e.g. for --watch where every refresh must hit the server.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// WithStaleFallback returns a context whose requests are answered from
+// expired cache entries when the server cannot be reached, e.g. for shell
+// completion while offline.
+func WithStaleFallback(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staleKey{}, true)
+}
+
+// cachedResponse is the on-disk form of a cached GET response.
+type cachedResponse struct {
+	StoredAt time.Time   `json:"storedAt"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+// responseCache serves repeated GET requests from disk for up to ttl.
+// Any other method clears the cache, since it may change what GETs return.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+func newResponseCache(dir string, ttl time.Duration) (*responseCache, error) {
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("locate cache dir: %w", err)
+		}
+		dir = filepath.Join(base, "enclave", "http")
+	}
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	return &responseCache{dir: dir, ttl: ttl}, nil
+}
+
+func (c *responseCache) wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodGet {
+			c.clear()
+
+			return next.RoundTrip(req)
+		}
+		if !cacheable(req) {
+			return next.RoundTrip(req)
+		}
+
+		path := c.path(req)
+		if resp := c.load(req, path, c.ttl); resp != nil {
+			log.Debug().Str("url", req.URL.String()).Msg("cache hit")
+
+			return resp, nil
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			if stale, _ := req.Context().Value(staleKey{}).(bool); stale {
+				if resp := c.load(req, path, 0); resp != nil {
+					return resp, nil
+				}
+			}
+
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return resp, nil
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read response body: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		c.store(path, &cachedResponse{
+			StoredAt: time.Now(),
+			Status:   resp.StatusCode,
+			Header:   resp.Header,
+			Body:     body,
+		})
+
+		return resp, nil
+	})
+}
+
+// cacheable excludes volatile and bulky endpoints: task state changes
+// without client mutations, and artifact downloads can be large.
+func cacheable(req *http.Request) bool {
+	if noCache, _ := req.Context().Value(noCacheKey{}).(bool); noCache {
+		return false
+	}
+	p := req.URL.Path
+
+	return !strings.HasPrefix(p, "/v1/task") &&
+		!strings.HasPrefix(p, "/v1/artifact/raw/")
+}
+
+// path derives the cache file for req. Credentials are part of the key so
+// different users never share entries.
+func (c *responseCache) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(
+		req.URL.String() + "\n" + req.Header.Get("Authorization"),
+	))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached response for req if it is at most maxAge old, or
+// of any age if maxAge is 0.
+func (c *responseCache) load(
+	req *http.Request,
+	path string,
+	maxAge time.Duration,
+) *http.Response {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(b, &cached); err != nil ||
+		(maxAge > 0 && time.Since(cached.StoredAt) > maxAge) {
+		return nil
+	}
+
+	return &http.Response{
+		Status: fmt.Sprintf(
+			"%d %s",
+			cached.Status,
+			http.StatusText(cached.Status),
+		),
+		StatusCode:    cached.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cached.Header,
+		Body:          io.NopCloser(bytes.NewReader(cached.Body)),
+		ContentLength: int64(len(cached.Body)),
+		Request:       req,
+	}
+}
+
+// store writes an entry. Failures only cost a future cache miss.
+func (c *responseCache) store(path string, r *cachedResponse) {
+	b, err := json.Marshal(r)
+	if err == nil {
+		err = os.MkdirAll(c.dir, 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, b, 0o600)
+	}
+	if err != nil {
+		log.Debug().Err(err).Msg("write cache entry")
+	}
+}
+
+func (c *responseCache) clear() {
+	err := os.RemoveAll(c.dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Debug().Err(err).Msg("clear cache")
+	}
+}
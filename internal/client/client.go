@@ -2,33 +2,86 @@ package client
 
 import (
 	"cli/internal/config"
+	"cli/internal/dockercreds"
+	"context"
 	"errors"
+	"fmt"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 )
 
 // New constructs an authenticated Enclave SDK client from cfg.
-// Returns an error if api_url, username, or password are unset.
+// Returns an error if api_url, username, or password are unset. Credentials
+// are optional when replaying a HAR recording, and a configured credential
+// helper fills in missing ones.
 func New(cfg *config.Config) (*enclave.Client, error) {
+	if err := checkConfig(cfg); err != nil {
+		return nil, err
+	}
+	if err := installTransport(cfg); err != nil {
+		return nil, err
+	}
+
+	return enclave.New(cfg.APIURL, cfg.Username, cfg.Password)
+}
+
+// NewAdditional constructs a client for a second server, such as another
+// profile to compare against. It shares the transport installed by New, so
+// the connection settings of the primary config apply.
+func NewAdditional(cfg *config.Config) (*enclave.Client, error) {
+	if err := checkConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return enclave.New(cfg.APIURL, cfg.Username, cfg.Password)
+}
+
+// checkConfig fills in credentials from the credential helper and reports
+// missing connection settings.
+func checkConfig(cfg *config.Config) error {
 	if cfg.APIURL == "" {
-		return nil, errors.New(
+		return errors.New(
 			"api_url is required (set --api-url, ENCLAVE_API_URL, or api_url in config)",
 		)
 	}
-	if cfg.Username == "" {
-		return nil, errors.New(
+	if err := lookupCredentials(cfg); err != nil {
+		return err
+	}
+	if cfg.Username == "" && cfg.Replay == "" {
+		return errors.New(
 			"username is required (set --username, ENCLAVE_USERNAME, or username in config)",
 		)
 	}
-	if cfg.Password == "" {
-		return nil, errors.New(
+	if cfg.Password == "" && cfg.Replay == "" {
+		return errors.New(
 			"password is required (set --password, ENCLAVE_PASSWORD, or password in config)",
 		)
 	}
-	c, err := enclave.New(cfg.APIURL, cfg.Username, cfg.Password)
+
+	return nil
+}
+
+// lookupCredentials fills an unset username or password from the configured
+// credential helper.
+func lookupCredentials(cfg *config.Config) error {
+	if cfg.CredentialHelper == "" || cfg.Replay != "" ||
+		(cfg.Username != "" && cfg.Password != "") {
+		return nil
+	}
+	username, secret, err := dockercreds.Lookup(
+		context.Background(),
+		cfg.CredentialHelper,
+		cfg.APIURL,
+	)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("credential helper: %w", err)
+	}
+	if cfg.Username == "" {
+		cfg.Username = username
+	}
+	if cfg.Password == "" {
+		cfg.Password = secret
 	}
 
-	return c, nil
+	return nil
 }
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog/log"
+)
+
+// rawArtifactPrefix is the path prefix of artifact upload and download
+// endpoints, the only ones that move enough data to be worth compressing.
+const rawArtifactPrefix = "/v1/artifact/raw/"
+
+// Supported content encodings.
+const (
+	encodingGzip = "gzip"
+	encodingZstd = "zstd"
+)
+
+// compressionTransport compresses artifact uploads with encoding ("gzip" or
+// "zstd"; "" disables it) and negotiates compressed artifact downloads,
+// decoding them transparently. If the server rejects a compressed upload with
+// 415 Unsupported Media Type, the upload is retried uncompressed and
+// compression is not attempted again.
+func compressionTransport(
+	next http.RoundTripper,
+	encoding string,
+) (http.RoundTripper, error) {
+	switch encoding {
+	case "", encodingGzip, encodingZstd:
+	default:
+		return nil, fmt.Errorf(
+			"unsupported http.compression %q (use gzip or zstd)",
+			encoding,
+		)
+	}
+
+	// Once the server accepted a compressed upload, later uploads need no
+	// copy to fall back on.
+	var rejected, accepted atomic.Bool
+
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if !strings.HasPrefix(req.URL.Path, rawArtifactPrefix) {
+			return next.RoundTrip(req)
+		}
+
+		switch {
+		case req.Method == http.MethodGet:
+			return download(next, req)
+		case req.Method == http.MethodPost && encoding != "" &&
+			!rejected.Load() && req.Body != nil:
+			resp, retried, err := upload(next, req, encoding, !accepted.Load())
+			switch {
+			case retried:
+				rejected.Store(true)
+			case err == nil && resp.StatusCode < http.StatusBadRequest:
+				accepted.Store(true)
+			}
+
+			return resp, err
+		default:
+			return next.RoundTrip(req)
+		}
+	}), nil
+}
+
+// upload streams a compressed copy of req as it is read. If retry is set
+// and the server rejects the encoding, req is resent uncompressed, from
+// req.GetBody when the body can be recreated and otherwise from a copy of
+// the original bytes spooled to a temporary file while they are sent.
+func upload(
+	next http.RoundTripper,
+	req *http.Request,
+	encoding string,
+	retry bool,
+) (resp *http.Response, retried bool, err error) {
+	src := io.Reader(req.Body)
+	var spool *os.File
+	if retry && req.GetBody == nil {
+		if spool, err = os.CreateTemp("", "encl-upload-*"); err != nil {
+			_ = req.Body.Close()
+
+			return nil, false, fmt.Errorf("create upload spool: %w", err)
+		}
+		src = io.TeeReader(req.Body, spool)
+	}
+	cleanup := func() {
+		_ = req.Body.Close()
+		if spool != nil {
+			_ = spool.Close()
+			_ = os.Remove(spool.Name())
+		}
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pw.CloseWithError(compress(pw, src, encoding))
+	}()
+
+	creq := req.Clone(req.Context())
+	creq.Body = pr
+	creq.GetBody = nil
+	creq.ContentLength = -1
+	creq.Header.Set("Content-Encoding", encoding)
+	resp, err = next.RoundTrip(creq)
+	// The transport closes the pipe once it is done with the body, which
+	// ends the compression.
+	<-done
+	if err != nil || !retry ||
+		resp.StatusCode != http.StatusUnsupportedMediaType {
+		cleanup()
+
+		return resp, false, err
+	}
+
+	_ = resp.Body.Close()
+	log.Debug().Str("encoding", encoding).
+		Msg("server rejected compressed upload, retrying uncompressed")
+	rreq := req.Clone(req.Context())
+	if spool == nil {
+		cleanup()
+		if rreq.Body, err = req.GetBody(); err != nil {
+			return nil, true, fmt.Errorf("rewind request body: %w", err)
+		}
+	} else {
+		if _, err := spool.Seek(0, io.SeekStart); err != nil {
+			cleanup()
+
+			return nil, true, fmt.Errorf("rewind request body: %w", err)
+		}
+		// The server may have answered before the whole body was sent; the
+		// rest is still unread in req.Body.
+		rreq.Body = readCloser{
+			Reader: io.MultiReader(spool, req.Body),
+			close: func() error {
+				cleanup()
+
+				return nil
+			},
+		}
+	}
+	resp, err = next.RoundTrip(rreq)
+
+	return resp, true, err
+}
+
+// compress writes the content of r to w compressed with encoding.
+func compress(w io.Writer, r io.Reader, encoding string) error {
+	var zw io.WriteCloser
+	if encoding == encodingZstd {
+		enc, err := zstd.NewWriter(w)
+		if err != nil {
+			return fmt.Errorf("zstd: %w", err)
+		}
+		zw = enc
+	} else {
+		zw = gzip.NewWriter(w)
+	}
+	if _, err := io.Copy(zw, r); err != nil {
+		_ = zw.Close()
+
+		return fmt.Errorf("compress upload: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("compress upload: %w", err)
+	}
+
+	return nil
+}
+
+// download requests a compressed response and decodes it. Setting
+// Accept-Encoding disables the transport's built-in gzip handling, so both
+// encodings are decoded here.
+func download(
+	next http.RoundTripper,
+	req *http.Request,
+) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "zstd, gzip")
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	switch resp.Header.Get("Content-Encoding") {
+	case encodingZstd:
+		dec, err := zstd.NewReader(resp.Body)
+		if err != nil {
+			_ = resp.Body.Close()
+
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+		body = readCloser{Reader: dec, close: func() error {
+			dec.Close()
+
+			return resp.Body.Close()
+		}}
+	case encodingGzip:
+		dec, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			_ = resp.Body.Close()
+
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+		body = readCloser{Reader: dec, close: resp.Body.Close}
+	default:
+		return resp, nil
+	}
+
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return resp, nil
+}
+
+// readCloser pairs a reader with the close function of the body it reads
+// from.
+type readCloser struct {
+	io.Reader
+
+	close func() error
+}
+
+func (b readCloser) Close() error {
+	return b.close()
+}
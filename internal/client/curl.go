@@ -0,0 +1,89 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrNotSent is returned for requests that were printed as curl commands
+// instead of being sent (see --curl).
+var ErrNotSent = errors.New("request not sent (--curl)")
+
+// maxCurlBody is the largest request body that is inlined into the emitted
+// curl command. Larger or binary bodies are replaced by a file placeholder.
+const maxCurlBody = 64 * 1024
+
+// curlTransport prints every request as an equivalent curl command to w and
+// aborts it with ErrNotSent. It terminates the middleware chain.
+func curlTransport(w io.Writer) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		cmd, err := curlCommand(req)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fmt.Fprintln(w, cmd); err != nil {
+			return nil, err
+		}
+
+		return nil, ErrNotSent
+	})
+}
+
+// curlCommand renders req as a shell-quoted curl invocation. Credentials are
+// replaced by references to the ENCLAVE_USERNAME/ENCLAVE_PASSWORD variables.
+func curlCommand(req *http.Request) (string, error) {
+	parts := []string{"curl", "-X", req.Method, shellQuote(req.URL.String())}
+
+	if _, _, ok := req.BasicAuth(); ok {
+		parts = append(parts, "-u", `"$ENCLAVE_USERNAME:$ENCLAVE_PASSWORD"`)
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if name != "Authorization" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, v := range req.Header.Values(name) {
+			parts = append(parts, "-H", shellQuote(name+": "+v))
+		}
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxCurlBody+1))
+		_ = req.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("read request body: %w", err)
+		}
+		if len(body) > maxCurlBody || !isText(req.Header.Get("Content-Type")) {
+			parts = append(parts, "--data-binary", "@FILE")
+		} else {
+			parts = append(
+				parts,
+				"--data-raw",
+				shellQuote(string(bytes.TrimSpace(body))),
+			)
+		}
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+// isText reports whether a request body of the given content type can be
+// safely inlined into a shell command.
+func isText(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/json") ||
+		strings.HasPrefix(contentType, "text/")
+}
+
+// shellQuote wraps s in single quotes for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,376 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// HAR 1.2 document types. Only the fields the CLI reads or writes are
+// modelled; see http://www.softwareishard.com/blog/har-12-spec/.
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name string `json:"name"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harRecorder collects HTTP interactions and writes them as a HAR file.
+type harRecorder struct {
+	path    string
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+// wrap returns a RoundTripper that records every exchange passing through
+// next.
+func (r *harRecorder) wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var reqBody []byte
+		if req.Body != nil && req.Body != http.NoBody {
+			b, err := io.ReadAll(req.Body)
+			_ = req.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("read request body: %w", err)
+			}
+			reqBody = b
+			req.Body = io.NopCloser(bytes.NewReader(b))
+		}
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		wait := time.Since(start)
+
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read response body: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		total := time.Since(start)
+
+		r.mu.Lock()
+		r.entries = append(r.entries, harEntry{
+			StartedDateTime: start,
+			Time:            millis(total),
+			Request:         harRequestOf(req, reqBody),
+			Response:        harResponseOf(resp, respBody),
+			Timings: harTimings{
+				Wait:    millis(wait),
+				Receive: millis(total - wait),
+			},
+		})
+		r.mu.Unlock()
+
+		return resp, nil
+	})
+}
+
+// flush writes the recorded entries to the HAR file.
+func (r *harRecorder) flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "encl"},
+		Entries: r.entries,
+	}}
+	if doc.Log.Entries == nil {
+		doc.Log.Entries = []harEntry{}
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode har: %w", err)
+	}
+	if err := os.WriteFile(filepath.Clean(r.path), b, 0o600); err != nil {
+		return fmt.Errorf("write har: %w", err)
+	}
+
+	return nil
+}
+
+func harRequestOf(req *http.Request, body []byte) harRequest {
+	h := harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: "HTTP/1.1",
+		Headers:     harHeaders(req.Header),
+		QueryString: []harNameValue{},
+		Cookies:     []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+	for name, values := range req.URL.Query() {
+		for _, v := range values {
+			h.QueryString = append(
+				h.QueryString,
+				harNameValue{Name: name, Value: v},
+			)
+		}
+	}
+	if len(body) > 0 {
+		h.PostData = &harPostData{MimeType: req.Header.Get("Content-Type")}
+		if utf8.Valid(body) {
+			h.PostData.Text = harRedactBody(body)
+		}
+	}
+
+	return h
+}
+
+// harSecretFields are parts of JSON field names, such as "password", whose
+// values are redacted in recorded request bodies.
+var harSecretFields = []string{"password", "secret", "token"}
+
+// harRedactBody returns body with the values of secret fields redacted.
+// Bodies that are not JSON are returned unchanged.
+func harRedactBody(body []byte) string {
+	var v any
+	if json.Unmarshal(body, &v) != nil || !harRedact(v) {
+		return string(body)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(b)
+}
+
+// harRedact redacts secret fields in the decoded JSON value v and reports
+// whether it found any.
+func harRedact(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			name := strings.ToLower(k)
+			if slices.ContainsFunc(harSecretFields, func(s string) bool {
+				return strings.Contains(name, s)
+			}) {
+				v[k] = "REDACTED"
+				found = true
+			} else if harRedact(x) {
+				found = true
+			}
+		}
+	case []any:
+		for _, x := range v {
+			if harRedact(x) {
+				found = true
+			}
+		}
+	}
+
+	return found
+}
+
+func harResponseOf(resp *http.Response, body []byte) harResponse {
+	content := harContent{
+		Size:     int64(len(body)),
+		MimeType: resp.Header.Get("Content-Type"),
+	}
+	if utf8.Valid(body) {
+		content.Text = string(body)
+	} else {
+		content.Text = base64.StdEncoding.EncodeToString(body)
+		content.Encoding = "base64"
+	}
+
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Headers:     harHeaders(resp.Header),
+		Cookies:     []harNameValue{},
+		Content:     content,
+		HeadersSize: -1,
+		BodySize:    int64(len(body)),
+	}
+}
+
+// harHeaders converts h to HAR name/value pairs, redacting credentials.
+func harHeaders(h http.Header) []harNameValue {
+	out := []harNameValue{}
+	for name, values := range h {
+		for _, v := range values {
+			if name == "Authorization" {
+				v = "REDACTED"
+			}
+			out = append(out, harNameValue{Name: name, Value: v})
+		}
+	}
+
+	return out
+}
+
+// replayTransport serves responses from a HAR file instead of the network.
+// Entries are matched by method, path, and query; repeated requests consume
+// matching entries in recorded order, and the last one is reused once the
+// recording is exhausted.
+func replayTransport(path string) (http.RoundTripper, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read har: %w", err)
+	}
+	var doc harFile
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("decode har %s: %w", path, err)
+	}
+
+	var mu sync.Mutex
+	queues := map[string][]*harResponse{}
+	for i := range doc.Log.Entries {
+		e := &doc.Log.Entries[i]
+		key, err := replayKey(e.Request.Method, e.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("decode har %s: %w", path, err)
+		}
+		queues[key] = append(queues[key], &e.Response)
+	}
+
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		key, _ := replayKey(req.Method, req.URL.String())
+
+		mu.Lock()
+		q := queues[key]
+		if len(q) == 0 {
+			mu.Unlock()
+
+			return nil, fmt.Errorf("no recorded response for %s", key)
+		}
+		r := q[0]
+		if len(q) > 1 {
+			queues[key] = q[1:]
+		}
+		mu.Unlock()
+
+		return replayResponse(req, r)
+	}), nil
+}
+
+// replayKey identifies a request independently of the server it was
+// recorded against.
+func replayKey(method, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	return method + " " + u.RequestURI(), nil
+}
+
+func replayResponse(req *http.Request, r *harResponse) (*http.Response, error) {
+	body := []byte(r.Content.Text)
+	if strings.EqualFold(r.Content.Encoding, "base64") {
+		b, err := base64.StdEncoding.DecodeString(r.Content.Text)
+		if err != nil {
+			return nil, fmt.Errorf("decode recorded body: %w", err)
+		}
+		body = b
+	}
+
+	header := http.Header{}
+	for _, h := range r.Headers {
+		// The recorded body is already decoded and sized.
+		switch http.CanonicalHeaderKey(h.Name) {
+		case "Content-Encoding", "Content-Length", "Transfer-Encoding":
+			continue
+		}
+		header.Add(h.Name, h.Value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
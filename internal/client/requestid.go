@@ -0,0 +1,109 @@
+package client
+
+import (
+	"cli/internal/i18n"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+)
+
+// requestIDHeaders are the response headers that may carry a server-side
+// correlation ID, in order of preference.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Correlation-Id",
+	"Request-Id",
+}
+
+// RequestIDError is an API error together with the server request ID of the
+// response it was built from.
+type RequestIDError struct {
+	ID  string
+	Err error
+}
+
+func (e *RequestIDError) Error() string {
+	return e.Err.Error() + i18n.Sprintf(" (request ID: %s)", e.ID)
+}
+
+func (e *RequestIDError) Unwrap() error {
+	return e.Err
+}
+
+type requestIDKey struct{}
+
+// failedRequest is the last failed response seen under a context.
+type failedRequest struct {
+	mu     sync.Mutex
+	status int
+	id     string
+}
+
+// WithRequestID returns a context that keeps the request ID of the last
+// failed API response made with it, for WrapRequestID. Calls running at
+// the same time should each use their own.
+func WithRequestID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, &failedRequest{})
+}
+
+// WrapRequestID returns err as a RequestIDError if it is an API error and
+// the last failed response under ctx, with the same status, carried a
+// request ID. Other errors, and errors that already carry an ID, are
+// returned unchanged.
+func WrapRequestID(ctx context.Context, err error) error {
+	var apiErr *enclave.APIError
+	if !errors.As(err, &apiErr) || RequestID(err) != "" {
+		return err
+	}
+	f, ok := ctx.Value(requestIDKey{}).(*failedRequest)
+	if !ok {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.id == "" || f.status != apiErr.StatusCode {
+		return err
+	}
+
+	return &RequestIDError{ID: f.id, Err: err}
+}
+
+// RequestID returns the server request ID carried by err, or "" if there
+// is none.
+func RequestID(err error) string {
+	var idErr *RequestIDError
+	if errors.As(err, &idErr) {
+		return idErr.ID
+	}
+
+	return ""
+}
+
+// requestIDTransport notes the request ID of every failed response in the
+// context of its request, see WithRequestID.
+func requestIDTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp.StatusCode < http.StatusBadRequest {
+			return resp, err
+		}
+		f, ok := req.Context().Value(requestIDKey{}).(*failedRequest)
+		if !ok {
+			return resp, nil
+		}
+		for _, h := range requestIDHeaders {
+			if id := resp.Header.Get(h); id != "" {
+				f.mu.Lock()
+				f.status, f.id = resp.StatusCode, id
+				f.mu.Unlock()
+
+				break
+			}
+		}
+
+		return resp, nil
+	})
+}
@@ -0,0 +1,161 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// maxRetryAfter caps how long the CLI waits for a retry before giving up.
+const maxRetryAfter = time.Minute
+
+// rateLimitHeaders are logged at debug level when present.
+var rateLimitHeaders = []string{
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+}
+
+// retryTransport retries requests up to retries times. Rate-limited requests
+// (429, or 503 with Retry-After) are always retried, honoring Retry-After.
+// Idempotent requests are also retried after network errors and 502, 503,
+// and 504 responses. Without Retry-After the wait starts at backoff and
+// doubles each attempt. Requests whose body cannot be replayed are not
+// retried.
+func retryTransport(
+	next http.RoundTripper,
+	retries int,
+	backoff time.Duration,
+) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		wait := backoff
+		for attempt := 1; ; attempt++ {
+			resp, err := next.RoundTrip(req)
+			if resp != nil {
+				logQuota(resp)
+			}
+			if attempt > retries || !shouldRetry(req, resp, err) {
+				return resp, err
+			}
+
+			delay := wait
+			if resp != nil {
+				if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+					delay = d
+				}
+			}
+			if delay > maxRetryAfter {
+				return resp, err
+			}
+			retry, rewindErr := rewind(req)
+			if rewindErr != nil {
+				return resp, err
+			}
+
+			ev := log.Warn().Stringer("wait", delay).Int("attempt", attempt)
+			if resp != nil {
+				_ = resp.Body.Close()
+				ev = ev.Int("status", resp.StatusCode)
+			} else {
+				ev = ev.Err(err)
+			}
+			ev.Msg("request failed, retrying")
+
+			t := time.NewTimer(delay)
+			select {
+			case <-req.Context().Done():
+				t.Stop()
+
+				return nil, fmt.Errorf("wait to retry: %w", req.Context().Err())
+			case <-t.C:
+			}
+			wait *= 2
+			req = retry
+		}
+	})
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return idempotent(req.Method) && req.Context().Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		return resp.Header.Get("Retry-After") != "" || idempotent(req.Method)
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(req.Method)
+	default:
+		return false
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete,
+		http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses a Retry-After value given in seconds or as an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+// rewind returns a copy of req with a fresh body for resending.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	if req.GetBody == nil {
+		return nil, fmt.Errorf("request body of %s cannot be replayed", req.URL)
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retry := req.Clone(req.Context())
+	retry.Body = body
+
+	return retry, nil
+}
+
+// logQuota reports rate-limit headers at debug level.
+func logQuota(resp *http.Response) {
+	if !log.Debug().Enabled() {
+		return
+	}
+	var ev *zerolog.Event
+	for _, h := range rateLimitHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if ev == nil {
+				ev = log.Debug().Str("url", resp.Request.URL.Path)
+			}
+			ev = ev.Str(h, v)
+		}
+	}
+	if ev != nil {
+		ev.Msg("rate limit quota")
+	}
+}
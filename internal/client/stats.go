@@ -0,0 +1,58 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// Transfer counts the API traffic of the running command.
+type Transfer struct {
+	Requests      int64 `json:"requests"`
+	BytesSent     int64 `json:"bytesSent"`
+	BytesReceived int64 `json:"bytesReceived"`
+}
+
+var requests, bytesSent, bytesReceived atomic.Int64
+
+// Stats returns the traffic sent to and received from the API so far. Only
+// request and response bodies are counted, as they go over the wire, so
+// retries count again and compressed uploads count compressed.
+func Stats() Transfer {
+	return Transfer{
+		Requests:      requests.Load(),
+		BytesSent:     bytesSent.Load(),
+		BytesReceived: bytesReceived.Load(),
+	}
+}
+
+// statsTransport counts every request and the body bytes passing through it.
+func statsTransport(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests.Add(1)
+		if req.Body != nil && req.Body != http.NoBody {
+			req = req.Clone(req.Context())
+			req.Body = &countingBody{ReadCloser: req.Body, n: &bytesSent}
+		}
+		resp, err := next.RoundTrip(req)
+		if resp != nil && resp.Body != nil {
+			resp.Body = &countingBody{ReadCloser: resp.Body, n: &bytesReceived}
+		}
+
+		return resp, err
+	})
+}
+
+// countingBody adds the bytes read from the wrapped body to n.
+type countingBody struct {
+	io.ReadCloser
+
+	n *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+
+	return n, err
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"cli/internal/config"
+	"crypto/tls"
+	"net/http"
+	"os"
+)
+
+// baseTransport is the stock transport captured before any middleware is
+// installed.
+var baseTransport = http.DefaultTransport
+
+// StockClient sends requests through the stock transport, bypassing the API
+// middleware the client package installs, for traffic that is not meant
+// for the API, such as update checks and telemetry.
+var StockClient = &http.Client{Transport: baseTransport}
+
+// tunedTransport returns a copy of the stock transport with the connection
+// settings from cfg applied. All API requests share it, so connections are
+// reused across the SDK's calls.
+func tunedTransport(cfg *config.HTTP) http.RoundTripper {
+	base, ok := baseTransport.(*http.Transport)
+	if !ok {
+		return baseTransport
+	}
+	t := base.Clone()
+	if cfg.MaxIdleConns > 0 {
+		t.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	if cfg.Timeout > 0 {
+		t.TLSHandshakeTimeout = cfg.Timeout
+		t.ResponseHeaderTimeout = cfg.Timeout
+	}
+	t.DisableKeepAlives = cfg.DisableKeepAlives
+	if !cfg.HTTP2 {
+		// A non-nil empty map disables the built-in HTTP/2 upgrade.
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(
+			string,
+			*tls.Conn,
+		) http.RoundTripper{}
+	}
+
+	return t
+}
+
+// ExternalTransport returns the transport for requests to services other
+// than the Enclave API, such as OCI registries: the tuned connection settings
+// and retries, without the API-specific middleware.
+func ExternalTransport(cfg *config.Config) http.RoundTripper {
+	return retryTransport(
+		tunedTransport(&cfg.HTTP),
+		cfg.HTTP.Retries,
+		cfg.HTTP.RetryBackoff,
+	)
+}
+
+// recorder is set while --record is active; Close flushes it.
+var recorder *harRecorder
+
+// roundTripFunc adapts a function to the http.RoundTripper interface.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// installTransport replaces http.DefaultTransport with the middleware chain
+// configured by cfg. The SDK's generated client does not accept a custom
+// http.Client, but it falls back to http.DefaultTransport for every request.
+func installTransport(cfg *config.Config) error {
+	rt := statsTransport(tunedTransport(&cfg.HTTP))
+	switch {
+	case cfg.Curl:
+		rt = curlTransport(os.Stderr)
+	case cfg.Replay != "":
+		replay, err := replayTransport(cfg.Replay)
+		if err != nil {
+			return err
+		}
+		rt = replay
+	default:
+		compressed, err := compressionTransport(rt, cfg.HTTP.Compression)
+		if err != nil {
+			return err
+		}
+		rt = retryTransport(
+			compressed,
+			cfg.HTTP.Retries,
+			cfg.HTTP.RetryBackoff,
+		)
+	}
+	if cfg.Record != "" {
+		recorder = &harRecorder{path: cfg.Record}
+		rt = recorder.wrap(rt)
+	}
+	if cfg.Cache.Enabled && !cfg.NoCache && !cfg.Curl && cfg.Replay == "" {
+		cache, err := newResponseCache(cfg.Cache.Dir, cfg.Cache.TTL)
+		if err != nil {
+			return err
+		}
+		rt = cache.wrap(rt)
+	}
+	http.DefaultTransport = requestIDTransport(rt)
+
+	return nil
+}
+
+// Close flushes state held by the transport middleware, such as a pending
+// HAR recording. It is safe to call when no client was created.
+func Close() error {
+	if recorder == nil {
+		return nil
+	}
+
+	return recorder.flush()
+}
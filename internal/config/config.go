@@ -3,7 +3,9 @@ package config
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -11,14 +13,166 @@ import (
 
 // Config holds all resolved configuration values.
 type Config struct {
+	// Profile names the entry of the profiles section whose settings
+	// override the top-level ones.
+	Profile  string `mapstructure:"profile"`
 	APIURL   string `mapstructure:"api_url"`
 	Username string `mapstructure:"username"`
 	Password string `mapstructure:"password"`
-	LogLevel string `mapstructure:"log_level"`
-	Output   string `mapstructure:"output"`
+	// CredentialHelper fills in a missing username or password from a
+	// docker credential store: "docker" follows ~/.docker/config.json,
+	// any other value names a docker-credential-<name> helper.
+	CredentialHelper string `mapstructure:"credential_helper"`
+	LogLevel         string `mapstructure:"log_level"`
+	Output           string `mapstructure:"output"`
+	// OutputFile receives the command's output instead of stdout.
+	OutputFile string `mapstructure:"output_file"`
+	// JQ filters JSON output through a jq expression.
+	JQ string `mapstructure:"jq"`
+	// JSONPath renders output through a kubectl-style JSONPath template.
+	JSONPath string `mapstructure:"jsonpath"`
+	// Columns selects table columns by key, overriding the default set.
+	Columns []string `mapstructure:"columns"`
+	// UTC renders timestamps in UTC instead of the local timezone.
+	UTC bool `mapstructure:"utc"`
+	// RelativeTime renders table timestamps relative to now, e.g.
+	// "3 days ago".
+	RelativeTime bool `mapstructure:"relative_time"`
+	// Raw prints exact byte counts instead of binary units.
+	Raw bool `mapstructure:"raw"`
+	// AllFields renders every field of each result as "Field: value" lines
+	// instead of the table columns.
+	AllFields bool `mapstructure:"all_fields"`
+	// TableStyle selects how tables are drawn: default, minimal (or its
+	// alias plain), rounded, markdown, or github.
+	TableStyle string `mapstructure:"table_style"`
+	// NoHeaders omits the header row from table output.
+	NoHeaders bool `mapstructure:"no_headers"`
+	// Filter narrows list output by column values, e.g. "roles=admin".
+	Filter string `mapstructure:"filter"`
+	// Sort orders list output by a column key, e.g. "created:desc".
+	Sort    string `mapstructure:"sort"`
+	Curl    bool   `mapstructure:"curl"`
+	Record  string `mapstructure:"record"`
+	Replay  string `mapstructure:"replay"`
+	Hooks   Hooks  `mapstructure:"hooks"`
+	Theme   Theme  `mapstructure:"theme"`
+	HTTP    HTTP   `mapstructure:"http"`
+	Cache   Cache  `mapstructure:"cache"`
+	NoCache bool   `mapstructure:"no_cache"`
+	// UpdateCheck prints a hint when a newer release is available. The
+	// lookup runs at most once a day (default true).
+	UpdateCheck bool `mapstructure:"update_check"`
+	// Telemetry is "on" to record anonymous usage metrics: the command run
+	// and the class of error it failed with, never arguments or names.
+	// Off by default.
+	Telemetry string `mapstructure:"telemetry"`
+	// TelemetryEndpoint receives recorded metrics. Without it, metrics are
+	// only kept locally.
+	TelemetryEndpoint string `mapstructure:"telemetry_endpoint"`
+	// History records executed commands in ~/.enclave/history.jsonl
+	// (default true).
+	History bool `mapstructure:"history"`
+	// Language of CLI messages: en, de, or auto to follow the locale
+	// (default auto).
+	Language string `mapstructure:"language"`
+	// NonInteractive turns off prompts, pickers, and interactive views, so
+	// missing input fails instead of waiting. Input is also treated as
+	// non-interactive when stdin is not a terminal.
+	NonInteractive bool `mapstructure:"non_interactive"`
+	// CI formats errors and warnings as annotations for a CI system's UI:
+	// github, gitlab, or auto to detect it from the environment.
+	CI string `mapstructure:"ci"`
+	// SummaryFile receives a JSON record of what the command did: changed
+	// objects, API traffic, warnings, and the exit code.
+	SummaryFile string `mapstructure:"summary_file"`
+	// Plain disables colors, box drawing, and animated progress. It is on
+	// by default when TERM is "dumb".
+	Plain     bool      `mapstructure:"plain"`
+	Promotion Promotion `mapstructure:"promotion"`
+	// PasswordPolicy is checked before passwords are sent to the server.
+	PasswordPolicy PasswordPolicy `mapstructure:"password_policy"`
+	// Favorites are artifacts pinned with encl artifact favorite, as
+	// <namespace>/<name>.
+	Favorites []string `mapstructure:"favorites"`
 }
 
-// OutputFormat returns the output format as a string (table, json, yaml).
+// Promotion defines the release pipeline used by encl release promote.
+type Promotion struct {
+	// Stages are the tags an artifact version moves through, in order
+	// (default dev, staging, prod).
+	Stages []string `mapstructure:"stages"`
+}
+
+// PasswordPolicy lists the rules new passwords must follow. Zero values
+// disable a rule; the server may enforce further rules of its own.
+type PasswordPolicy struct {
+	MinLength int `mapstructure:"min_length"`
+	// Require lists the character classes a password must contain: lower,
+	// upper, digit, or symbol.
+	Require []string `mapstructure:"require"`
+	// Deny lists passwords that are rejected regardless of case.
+	Deny []string `mapstructure:"deny"`
+}
+
+// Cache configures the opt-in on-disk cache for GET responses.
+type Cache struct {
+	Enabled bool `mapstructure:"enabled"`
+	// TTL is how long entries stay valid (default 5m).
+	TTL time.Duration `mapstructure:"ttl"`
+	// Dir defaults to the user cache directory, e.g. ~/.cache/enclave/http.
+	Dir string `mapstructure:"dir"`
+}
+
+// HTTP tunes the transport used for API requests. Zero values keep the Go
+// defaults.
+type HTTP struct {
+	// Timeout bounds how long to wait for the server to start responding to
+	// a request. Transfers of large bodies are not cut off.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Retries is how often failed requests are retried (default 3).
+	Retries int `mapstructure:"retries"`
+	// RetryBackoff is the initial wait between retries, doubled on each
+	// attempt (default 1s).
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+
+	MaxIdleConns        int           `mapstructure:"max_idle_conns"`
+	MaxIdleConnsPerHost int           `mapstructure:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int           `mapstructure:"max_conns_per_host"`
+	IdleConnTimeout     time.Duration `mapstructure:"idle_conn_timeout"`
+	DisableKeepAlives   bool          `mapstructure:"disable_keep_alives"`
+	// HTTP2 allows negotiating HTTP/2 with TLS servers. Defaults to true.
+	HTTP2 bool `mapstructure:"http2"`
+	// Compression compresses artifact uploads: gzip, zstd, or empty for
+	// none.
+	Compression string `mapstructure:"compression"`
+}
+
+// Theme selects the color theme used by tables and the TUI.
+type Theme struct {
+	// Name is auto, dark, light, or high-contrast. auto picks dark or light
+	// from the terminal background.
+	Name string `mapstructure:"name"`
+	// Colors overrides individual palette roles, e.g. primary: "#ff8800".
+	Colors map[string]string `mapstructure:"colors"`
+}
+
+// Hooks lists the external commands run around CLI invocations.
+type Hooks struct {
+	Pre  []Hook `mapstructure:"pre"`
+	Post []Hook `mapstructure:"post"`
+}
+
+// Hook binds a shell command to the CLI commands it should run for.
+// Command matches either the full command path without the binary name
+// (e.g. "artifact upload"), the leaf command name (e.g. "delete"), or "*".
+type Hook struct {
+	Command string `mapstructure:"command"`
+	Run     string `mapstructure:"run"`
+}
+
+// OutputFormat returns the output format as a string (table, wide, json,
+// yaml).
 func (c *Config) OutputFormat() string {
 	if c.Output == "" {
 		return "table"
@@ -27,9 +181,100 @@ func (c *Config) OutputFormat() string {
 	return c.Output
 }
 
+// TelemetryEnabled reports whether usage metrics should be recorded.
+func (c *Config) TelemetryEnabled() bool {
+	switch strings.ToLower(c.Telemetry) {
+	case "on", "true", "yes", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// flagKeys maps persistent flag names to the config keys they override.
+var flagKeys = map[string]string{
+	"api-url":         "api_url",
+	"username":        "username",
+	"password":        "password",
+	"log-level":       "log_level",
+	"output":          "output",
+	"columns":         "columns",
+	"no-headers":      "no_headers",
+	"all-fields":      "all_fields",
+	"table-style":     "table_style",
+	"utc":             "utc",
+	"raw":             "raw",
+	"relative-time":   "relative_time",
+	"filter":          "filter",
+	"sort":            "sort",
+	"output-file":     "output_file",
+	"jq":              "jq",
+	"jsonpath":        "jsonpath",
+	"curl":            "curl",
+	"record":          "record",
+	"replay":          "replay",
+	"no-cache":        "no_cache",
+	"plain":           "plain",
+	"profile":         "profile",
+	"non-interactive": "non_interactive",
+	"ci":              "ci",
+	"summary-file":    "summary_file",
+}
+
 // Load initialises Viper, binds pflags, reads config file(s), and returns
 // a populated Config. flags may be nil.
 func Load(flags *pflag.FlagSet) (*Config, error) {
+	v, err := newViper(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshal(v)
+}
+
+// LoadProfile returns the config with the named profile applied, regardless
+// of the profile selected by flags or the environment. Flags are not
+// applied, so a second server can be addressed alongside the one from Load.
+func LoadProfile(name string) (*Config, error) {
+	v, err := readViper(nil)
+	if err != nil {
+		return nil, err
+	}
+	v.Set("profile", name)
+	if err := applyProfile(v); err != nil {
+		return nil, err
+	}
+
+	return unmarshal(v)
+}
+
+// unmarshal decodes the settings of v into a Config.
+func unmarshal(v *viper.Viper) (*Config, error) {
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// newViper returns a Viper instance with defaults, environment, flags, and
+// the first config file found loaded, with the selected profile applied.
+// flags may be nil.
+func newViper(flags *pflag.FlagSet) (*viper.Viper, error) {
+	v, err := readViper(flags)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyProfile(v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// readViper is newViper without applying a profile.
+func readViper(flags *pflag.FlagSet) (*viper.Viper, error) {
 	v := viper.New()
 
 	v.SetConfigName("config")
@@ -44,37 +289,50 @@ func Load(flags *pflag.FlagSet) (*Config, error) {
 
 	v.SetDefault("log_level", "info")
 	v.SetDefault("output", "table")
+	v.SetDefault("theme.name", "auto")
+	v.SetDefault("http.http2", true)
+	v.SetDefault("http.retries", 3)
+	v.SetDefault("http.retry_backoff", "1s")
+	v.SetDefault("update_check", true)
+	v.SetDefault("telemetry", "off")
+	v.SetDefault("history", true)
+	v.SetDefault("language", "auto")
+	v.SetDefault("plain", os.Getenv("TERM") == "dumb")
+	v.SetDefault("promotion.stages", []string{"dev", "staging", "prod"})
 
 	if flags != nil {
-		if f := flags.Lookup("api-url"); f != nil {
-			_ = v.BindPFlag("api_url", f)
-		}
-		if f := flags.Lookup("username"); f != nil {
-			_ = v.BindPFlag("username", f)
-		}
-		if f := flags.Lookup("password"); f != nil {
-			_ = v.BindPFlag("password", f)
-		}
-		if f := flags.Lookup("log-level"); f != nil {
-			_ = v.BindPFlag("log_level", f)
-		}
-		if f := flags.Lookup("output"); f != nil {
-			_ = v.BindPFlag("output", f)
+		for name, key := range flagKeys {
+			if f := flags.Lookup(name); f != nil {
+				_ = v.BindPFlag(key, f)
+			}
 		}
 	}
 
 	// Ignore config file not found; all settings may come from env/flags.
 	if err := v.ReadInConfig(); err != nil {
 		var configFileNotFoundError viper.ConfigFileNotFoundError
-		if errors.As(err, &configFileNotFoundError) {
+		if !errors.As(err, &configFileNotFoundError) {
 			return nil, fmt.Errorf("read config: %w", err)
 		}
 	}
 
-	var cfg Config
-	if err := v.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("unmarshal config: %w", err)
+	return v, nil
+}
+
+// applyProfile layers the selected profile's settings over the config file.
+// Environment variables and flags still take precedence.
+func applyProfile(v *viper.Viper) error {
+	name := v.GetString("profile")
+	if name == "" {
+		return nil
+	}
+	key := "profiles." + name
+	if !v.IsSet(key) {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	if err := v.MergeConfigMap(v.GetStringMap(key)); err != nil {
+		return fmt.Errorf("apply profile %q: %w", name, err)
 	}
 
-	return &cfg, nil
+	return nil
 }
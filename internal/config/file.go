@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// FilePath returns the config file settings are read from. When none exists
+// yet it returns ~/.enclave/config.yaml, where Set creates one.
+func FilePath() (string, error) {
+	v, err := newViper(nil)
+	if err != nil {
+		return "", err
+	}
+	if used := v.ConfigFileUsed(); used != "" {
+		return used, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate home dir: %w", err)
+	}
+
+	return filepath.Join(home, ".enclave", "config.yaml"), nil
+}
+
+// Settings returns all resolved settings as a nested map, after applying
+// defaults, the config file, environment variables, and flags. flags may be
+// nil.
+func Settings(flags *pflag.FlagSet) (map[string]any, error) {
+	v, err := newViper(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.AllSettings(), nil
+}
+
+// Get returns the resolved value of a dot-separated key, e.g. "cache.ttl".
+// flags may be nil.
+func Get(
+	flags *pflag.FlagSet,
+	key string,
+) (value any, ok bool, err error) {
+	v, err := newViper(flags)
+	if err != nil {
+		return nil, false, err
+	}
+	if !v.IsSet(key) {
+		return nil, false, nil
+	}
+
+	return v.Get(key), true, nil
+}
+
+// Set stores value under a dot-separated key in the config file, creating
+// the file and any intermediate sections as needed. Comments and the order
+// of existing settings are preserved. It returns the path written.
+func Set(key, value string) (string, error) {
+	path, doc, err := readDoc()
+	if err != nil {
+		return "", err
+	}
+	if err := setNode(
+		doc.Content[0],
+		strings.Split(key, "."),
+		&yaml.Node{Kind: yaml.ScalarNode, Value: value},
+	); err != nil {
+		return "", fmt.Errorf("set %s: %w", key, err)
+	}
+
+	return path, writeDoc(path, doc)
+}
+
+// SetList stores values as a list under a dot-separated key in the config
+// file, like Set. It returns the path written.
+func SetList(key string, values []string) (string, error) {
+	path, doc, err := readDoc()
+	if err != nil {
+		return "", err
+	}
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, v := range values {
+		list.Content = append(
+			list.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: v},
+		)
+	}
+	if err := setNode(
+		doc.Content[0],
+		strings.Split(key, "."),
+		list,
+	); err != nil {
+		return "", fmt.Errorf("set %s: %w", key, err)
+	}
+
+	return path, writeDoc(path, doc)
+}
+
+// Unset removes a dot-separated key from the config file, so its default
+// applies again. Missing keys are ignored. It returns the path written.
+func Unset(key string) (string, error) {
+	path, doc, err := readDoc()
+	if err != nil {
+		return "", err
+	}
+	unsetNode(doc.Content[0], strings.Split(key, "."))
+
+	return path, writeDoc(path, doc)
+}
+
+// FileValues returns the settings stored in the config file itself, without
+// defaults, environment variables, or flags, keyed by dot-separated keys,
+// e.g. "theme.name". Sections are flattened.
+func FileValues() (map[string]string, error) {
+	_, doc, err := readDoc()
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	flatten(doc.Content[0], "", values)
+
+	return values, nil
+}
+
+// ProfileNames returns the names of the profiles defined in the config
+// file, sorted.
+func ProfileNames() ([]string, error) {
+	_, doc, err := readDoc()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "profiles" {
+			continue
+		}
+		profiles := root.Content[i+1]
+		for j := 0; j+1 < len(profiles.Content); j += 2 {
+			names = append(names, profiles.Content[j].Value)
+		}
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// readDoc parses the config file, or returns an empty document if there is
+// none yet.
+func readDoc() (string, *yaml.Node, error) {
+	path, err := FilePath()
+	if err != nil {
+		return "", nil, err
+	}
+
+	var doc yaml.Node
+	b, err := os.ReadFile(path) // #nosec G304 -- path is the config file
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return "", nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return "", nil, fmt.Errorf("read config: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode}},
+		}
+	}
+
+	return path, &doc, nil
+}
+
+// writeDoc stores doc in the config file, creating it if needed.
+func writeDoc(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	// Write a temporary file and rename it into place, so a failed write
+	// never leaves a truncated config, e.g. while rotating the password.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config.*.tmp")
+	if err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+
+	return nil
+}
+
+// flatten collects the scalar values below the mapping node m.
+func flatten(m *yaml.Node, prefix string, values map[string]string) {
+	if m.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key := prefix + m.Content[i].Value
+		if v := m.Content[i+1]; v.Kind == yaml.MappingNode {
+			flatten(v, key+".", values)
+		} else if v.Kind == yaml.ScalarNode {
+			values[key] = v.Value
+		}
+	}
+}
+
+// unsetNode removes the path below the mapping node m.
+func unsetNode(m *yaml.Node, path []string) {
+	if m.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			m.Content = slices.Delete(m.Content, i, i+2)
+
+			return
+		}
+		unsetNode(m.Content[i+1], path[1:])
+
+		return
+	}
+}
+
+// setNode assigns value to the path below the mapping node m.
+func setNode(m *yaml.Node, path []string, value *yaml.Node) error {
+	if m.Kind != yaml.MappingNode {
+		return fmt.Errorf("%q is not a section", path[0])
+	}
+
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			m.Content[i+1] = value
+
+			return nil
+		}
+
+		return setNode(m.Content[i+1], path[1:], value)
+	}
+
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: path[0]}
+	if len(path) == 1 {
+		m.Content = append(m.Content, keyNode, value)
+
+		return nil
+	}
+	section := &yaml.Node{Kind: yaml.MappingNode}
+	m.Content = append(m.Content, keyNode, section)
+
+	return setNode(section, path[1:], value)
+}
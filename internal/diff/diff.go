@@ -0,0 +1,201 @@
+// Package diff renders line differences between two texts as unified diffs.
+package diff
+
+import (
+	"cli/internal/styles"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around each change.
+const context = 3
+
+// edit is one line of an edit script: kept (' '), removed ('-'), or added
+// ('+'). a and b are the 0-based positions in the old and new text before
+// the line.
+type edit struct {
+	op   byte
+	text string
+	a, b int
+}
+
+// Unified returns the unified diff that turns a into b, with from and to as
+// the file names in its header. It returns "" when the texts are equal.
+func Unified(from, to, a, b string) string {
+	edits := script(lines(a), lines(b))
+	if !slices.ContainsFunc(edits, func(e edit) bool { return e.op != ' ' }) {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", from, to)
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+
+			continue
+		}
+		// Changes separated by no more than twice the context share a hunk.
+		last := i
+		for j := i; j < len(edits) && j-last <= 2*context; j++ {
+			if edits[j].op != ' ' {
+				last = j
+			}
+		}
+		start := max(i-context, 0)
+		stop := min(last+context+1, len(edits))
+		writeHunk(&sb, edits[start:stop])
+		i = stop
+	}
+
+	return sb.String()
+}
+
+// Write writes the unified diff patch to w, coloring added lines green,
+// removed lines red, and hunk headers when color is set.
+func Write(w io.Writer, patch string, color bool) error {
+	if !color {
+		_, err := io.WriteString(w, patch)
+
+		return err
+	}
+	header := true
+	for line := range strings.Lines(patch) {
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			header = false
+			line = styles.DiffHunkStyle.Render(line)
+		case header:
+			line = styles.TitleStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			line = styles.DiffAddedStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = styles.DiffRemovedStyle.Render(line)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeHunk writes edits as one hunk, headed by the line ranges it covers.
+func writeHunk(sb *strings.Builder, edits []edit) {
+	var oldLines, newLines int
+	for _, e := range edits {
+		if e.op != '+' {
+			oldLines++
+		}
+		if e.op != '-' {
+			newLines++
+		}
+	}
+	fmt.Fprintf(
+		sb,
+		"@@ -%s +%s @@\n",
+		hunkRange(edits[0].a, oldLines),
+		hunkRange(edits[0].b, newLines),
+	)
+	for _, e := range edits {
+		sb.WriteByte(e.op)
+		sb.WriteString(e.text)
+		sb.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the range of n lines after position pos. Empty ranges
+// name the line before them, as diff does.
+func hunkRange(pos, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if n == 1 {
+		return strconv.Itoa(pos + 1)
+	}
+
+	return fmt.Sprintf("%d,%d", pos+1, n)
+}
+
+// lines splits s into lines without their line breaks.
+func lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// script returns the shortest edit script that turns a into b, using
+// Myers' algorithm.
+func script(a, b []string) []edit {
+	n, m := len(a), len(b)
+	// v holds the furthest x reached on each diagonal k = x - y, shifted by
+	// off. trace keeps the part of v each round started from, so the path
+	// can be walked back.
+	off := n + m + 1
+	v := make([]int, 2*off+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v[off-d-1:off+d+2]))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backtrack walks the rounds of script back from the end of both texts and
+// returns the edits in order.
+func backtrack(trace [][]int, a, b []string) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		prev := k - 1
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prev = k + 1
+		}
+		prevX := at(prev)
+		prevY := prevX - prev
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{op: ' ', text: a[x], a: x, b: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{op: '+', text: b[y], a: x, b: y})
+		} else {
+			x--
+			edits = append(edits, edit{op: '-', text: a[x], a: x, b: y})
+		}
+	}
+	slices.Reverse(edits)
+
+	return edits
+}
@@ -0,0 +1,172 @@
+// Package dockercreds resolves logins the way the docker CLI does: through
+// credential helpers (docker-credential-*) and ~/.docker/config.json, so
+// existing logins can be reused.
+package dockercreds
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Docker selects the helper configured in ~/.docker/config.json instead of a
+// specific one.
+const Docker = "docker"
+
+// helperTimeout bounds a credential helper, which may prompt or unlock a
+// keychain.
+const helperTimeout = 30 * time.Second
+
+// ErrNotFound is returned when no credentials are stored for a server.
+var ErrNotFound = errors.New("no stored credentials")
+
+// configFile is the part of ~/.docker/config.json that holds logins.
+type configFile struct {
+	Auths       map[string]authEntry `json:"auths"`
+	CredsStore  string               `json:"credsStore"`
+	CredHelpers map[string]string    `json:"credHelpers"`
+}
+
+type authEntry struct {
+	Auth string `json:"auth"`
+}
+
+// helperResponse is the output of "docker-credential-<name> get".
+type helperResponse struct {
+	Username string `json:"Username"`
+	Secret   string `json:"Secret"`
+}
+
+// Lookup returns the username and secret stored for server, a URL or host
+// name. helper is either Docker, to follow the docker CLI configuration, or
+// the name of a credential helper, e.g. "pass" for docker-credential-pass.
+func Lookup(
+	ctx context.Context,
+	helper, server string,
+) (username, secret string, err error) {
+	host := hostOf(server)
+	if helper != Docker {
+		return runHelper(ctx, helper, host)
+	}
+
+	cfg, err := readConfig()
+	if err != nil {
+		return "", "", err
+	}
+	if name := cfg.CredHelpers[host]; name != "" {
+		return runHelper(ctx, name, host)
+	}
+	if cfg.CredsStore != "" {
+		return runHelper(ctx, cfg.CredsStore, host)
+	}
+	for key, entry := range cfg.Auths {
+		if hostOf(key) == host && entry.Auth != "" {
+			return decodeAuth(entry.Auth)
+		}
+	}
+
+	return "", "", fmt.Errorf("%w for %s", ErrNotFound, host)
+}
+
+// configPath returns $DOCKER_CONFIG/config.json or ~/.docker/config.json.
+func configPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate home dir: %w", err)
+	}
+
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
+
+func readConfig() (*configFile, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	var cfg configFile
+	b, err := os.ReadFile(path) // #nosec G304 -- path is the docker config
+	if errors.Is(err, os.ErrNotExist) {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read docker config: %w", err)
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// runHelper asks docker-credential-<name> for the credentials of host.
+func runHelper(
+	ctx context.Context,
+	name, host string,
+) (username, secret string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, helperTimeout)
+	defer cancel()
+
+	bin := "docker-credential-" + name
+	cmd := exec.CommandContext(
+		ctx,
+		bin,
+		"get",
+	) // #nosec G204 -- helper named in config
+	cmd.Stdin = strings.NewReader(host)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stdout.String() + stderr.String())
+		if strings.Contains(msg, "credentials not found") {
+			return "", "", fmt.Errorf("%w for %s in %s", ErrNotFound, host, bin)
+		}
+
+		return "", "", fmt.Errorf("%s: %w: %s", bin, err, msg)
+	}
+
+	var resp helperResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return "", "", fmt.Errorf("parse %s output: %w", bin, err)
+	}
+
+	return resp.Username, resp.Secret, nil
+}
+
+// decodeAuth splits a base64 "user:password" auth entry.
+func decodeAuth(auth string) (username, secret string, err error) {
+	b, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return "", "", fmt.Errorf("decode docker auth entry: %w", err)
+	}
+	username, secret, ok := strings.Cut(string(b), ":")
+	if !ok {
+		return "", "", errors.New("malformed docker auth entry")
+	}
+
+	return username, secret, nil
+}
+
+// hostOf reduces a URL or docker auths key to its host[:port].
+func hostOf(server string) string {
+	if strings.Contains(server, "://") {
+		if u, err := url.Parse(server); err == nil {
+			return u.Host
+		}
+	}
+	host, _, _ := strings.Cut(server, "/")
+
+	return host
+}
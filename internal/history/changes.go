@@ -0,0 +1,128 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Kinds of objects a Change applies to.
+const (
+	KindPolicy        = "policy"
+	KindRole          = "role"
+	KindResourceGroup = "resource-group"
+)
+
+// Actions a Change records.
+const (
+	ActionCreate = "create"
+	ActionDelete = "delete"
+	// ActionUpdate replaces an existing object in place, keeping what
+	// depends on it, such as the policies of a role.
+	ActionUpdate = "update"
+)
+
+// Change is a single mutation made by a command, recorded so it can be
+// undone later.
+type Change struct {
+	Kind   string `json:"kind"`
+	Action string `json:"action"`
+	// Object is the created, deleted, or updated object, e.g. an
+	// enclave.Policy.
+	Object json.RawMessage `json:"object"`
+	// Previous is the object as it was before an update.
+	Previous json.RawMessage `json:"previous,omitempty"`
+}
+
+type journalKey struct{}
+
+// journal collects the changes made while a command runs.
+type journal struct {
+	mu       sync.Mutex
+	changes  []Change
+	reverts  time.Time
+	reverted []int
+}
+
+// WithJournal returns a context that collects the changes recorded by
+// RecordChange.
+func WithJournal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, journalKey{}, &journal{})
+}
+
+// RecordChange notes that the command running under ctx applied action to
+// object. It is safe for concurrent use and a no-op without a journal.
+func RecordChange(ctx context.Context, kind, action string, object any) {
+	b, err := json.Marshal(object)
+	if err != nil {
+		return
+	}
+	record(ctx, &Change{Kind: kind, Action: action, Object: b})
+}
+
+// RecordUpdate notes that the command running under ctx replaced previous
+// with object. Undoing it puts previous back in place.
+func RecordUpdate(ctx context.Context, kind string, previous, object any) {
+	prev, err := json.Marshal(previous)
+	if err != nil {
+		return
+	}
+	b, err := json.Marshal(object)
+	if err != nil {
+		return
+	}
+	record(ctx, &Change{
+		Kind:     kind,
+		Action:   ActionUpdate,
+		Object:   b,
+		Previous: prev,
+	})
+}
+
+func record(ctx context.Context, ch *Change) {
+	j, ok := ctx.Value(journalKey{}).(*journal)
+	if !ok {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.changes = append(j.changes, *ch)
+}
+
+// SetReverts marks the command running under ctx as undoing the entry
+// recorded at t. changes lists the indexes of the changes of that entry it
+// undid when it did not get to all of them, and is nil otherwise.
+func SetReverts(ctx context.Context, t time.Time, changes []int) {
+	if j, ok := ctx.Value(journalKey{}).(*journal); ok {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.reverts = t
+		j.reverted = changes
+	}
+}
+
+// Reverted returns the indexes passed to SetReverts under ctx.
+func Reverted(ctx context.Context) []int {
+	j, ok := ctx.Value(journalKey{}).(*journal)
+	if !ok {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.reverted
+}
+
+// Changes returns the changes recorded under ctx and the time of the entry
+// they revert, if any.
+func Changes(ctx context.Context) ([]Change, time.Time) {
+	j, ok := ctx.Value(journalKey{}).(*journal)
+	if !ok {
+		return nil, time.Time{}
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]Change(nil), j.changes...), j.reverts
+}
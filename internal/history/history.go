@@ -0,0 +1,181 @@
+// Package history keeps a local journal of executed commands in
+// ~/.enclave/history.jsonl, so changes can be reconstructed later.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// SensitiveArgs is a command annotation listing the comma-separated indexes
+// of positional arguments that must never be recorded, e.g. "2" for a
+// password passed as the third argument.
+const SensitiveArgs = "history.sensitive_args"
+
+// redacted replaces secrets in recorded entries.
+const redacted = "********"
+
+// Entry is a single executed command.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args,omitempty"`
+	// Flags holds the flags set on the command line.
+	Flags    map[string]string `json:"flags,omitempty"`
+	Profile  string            `json:"profile,omitempty"`
+	Server   string            `json:"server"`
+	User     string            `json:"user,omitempty"`
+	Duration time.Duration     `json:"duration"`
+	// Error is the error message; empty when the command succeeded.
+	Error     string `json:"error,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	// Changes lists the mutations the command made, for undo.
+	Changes []Change `json:"changes,omitempty"`
+	// Reverts is the time of the entry this command undid.
+	Reverts time.Time `json:"reverts,omitzero"`
+	// Reverted lists the indexes of the changes of that entry this command
+	// undid, when it failed to undo some of them; empty means all that were
+	// left.
+	Reverted []int `json:"reverted,omitempty"`
+}
+
+// NewEntry describes an invocation of cmd with its positional args.
+// Sensitive arguments and flags, such as passwords, are redacted.
+func NewEntry(cmd *cobra.Command, name string, args []string) Entry {
+	return Entry{
+		Time:    time.Now(),
+		Command: name,
+		Args:    RedactArgs(cmd, args),
+		Flags:   changedFlags(cmd),
+	}
+}
+
+// Failed reports whether the command returned an error.
+func (e *Entry) Failed() bool {
+	return e.Error != ""
+}
+
+// Path returns the history file, ~/.enclave/history.jsonl.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate home dir: %w", err)
+	}
+
+	return filepath.Join(home, ".enclave", "history.jsonl"), nil
+}
+
+// Append adds e to the history file.
+func Append(e *Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode history entry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create history dir: %w", err)
+	}
+	f, err := os.OpenFile( // #nosec G304 -- path is under the home dir
+		path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0o600,
+	)
+	if err != nil {
+		return fmt.Errorf("open history: %w", err)
+	}
+	_, err = f.Write(append(b, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+
+	return nil
+}
+
+// Read returns all recorded entries, oldest first. Malformed lines are
+// skipped.
+func Read() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path) // #nosec G304 -- path is under the home dir
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open history: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	return entries, nil
+}
+
+// RedactArgs returns a copy of the positional args of cmd with those listed
+// in its SensitiveArgs annotation redacted.
+func RedactArgs(cmd *cobra.Command, args []string) []string {
+	out := append([]string(nil), args...)
+	for s := range strings.SplitSeq(cmd.Annotations[SensitiveArgs], ",") {
+		if i, err := strconv.Atoi(strings.TrimSpace(s)); err == nil &&
+			i >= 0 && i < len(out) {
+			out[i] = redacted
+		}
+	}
+
+	return out
+}
+
+func changedFlags(cmd *cobra.Command) map[string]string {
+	flags := map[string]string{}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if sensitive(f.Name) {
+			flags[f.Name] = redacted
+		} else {
+			flags[f.Name] = f.Value.String()
+		}
+	})
+	if len(flags) == 0 {
+		return nil
+	}
+
+	return flags
+}
+
+// sensitive reports whether a flag name suggests a secret value.
+func sensitive(name string) bool {
+	for _, s := range []string{"password", "secret", "token"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
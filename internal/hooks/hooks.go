@@ -0,0 +1,121 @@
+package hooks
+
+import (
+	"cli/internal/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Phase identifies when a hook runs relative to the CLI command.
+type Phase string
+
+const (
+	PhasePre  Phase = "pre"
+	PhasePost Phase = "post"
+)
+
+// Invocation describes the CLI command a hook is run for.
+type Invocation struct {
+	// Command is the command path without the binary name, e.g. "user delete".
+	Command string
+	// Args are the positional arguments, with secrets such as passwords
+	// redacted.
+	Args []string
+	// Err is the command result. Only meaningful for post hooks.
+	Err error
+}
+
+// RunPre executes all matching pre hooks in order. The first hook that exits
+// non-zero aborts the remaining hooks and the command itself.
+func RunPre(ctx context.Context, cfg *config.Config, inv Invocation) error {
+	for _, h := range matching(cfg.Hooks.Pre, inv.Command) {
+		if err := run(ctx, h, PhasePre, inv); err != nil {
+			return fmt.Errorf("pre hook %q: %w", h.Run, err)
+		}
+	}
+
+	return nil
+}
+
+// RunPost executes all matching post hooks. Failures are logged but never
+// change the outcome of the command that already ran.
+func RunPost(ctx context.Context, cfg *config.Config, inv Invocation) {
+	for _, h := range matching(cfg.Hooks.Post, inv.Command) {
+		if err := run(ctx, h, PhasePost, inv); err != nil {
+			log.Warn().Err(err).Str("hook", h.Run).Msg("post hook failed")
+		}
+	}
+}
+
+// matching returns the hooks whose Command pattern applies to command.
+func matching(hooks []config.Hook, command string) []config.Hook {
+	leaf := command
+	if i := strings.LastIndex(command, " "); i >= 0 {
+		leaf = command[i+1:]
+	}
+
+	var out []config.Hook
+	for _, h := range hooks {
+		if h.Run == "" {
+			continue
+		}
+		switch strings.TrimSpace(h.Command) {
+		case "*", command, leaf:
+			out = append(out, h)
+		}
+	}
+
+	return out
+}
+
+// run executes a single hook through the shell. Hook output goes to stderr so
+// it never mixes with the command's data output. ENCLAVE_HOOK_ARGS holds the
+// arguments as a JSON array, so arguments with spaces stay apart.
+func run(
+	ctx context.Context,
+	h config.Hook,
+	phase Phase,
+	inv Invocation,
+) error {
+	args, err := json.Marshal(append([]string{}, inv.Args...))
+	if err != nil {
+		return fmt.Errorf("encode arguments: %w", err)
+	}
+
+	// #nosec G204 -- hooks are configured by the user running the CLI
+	c := exec.CommandContext(ctx, "sh", "-c", h.Run)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	c.Env = append(os.Environ(),
+		"ENCLAVE_HOOK_PHASE="+string(phase),
+		"ENCLAVE_HOOK_COMMAND="+inv.Command,
+		"ENCLAVE_HOOK_ARGS="+string(args),
+	)
+	if phase == PhasePost {
+		status := "success"
+		errMsg := ""
+		if inv.Err != nil {
+			status = "failure"
+			errMsg = inv.Err.Error()
+		}
+		c.Env = append(c.Env,
+			"ENCLAVE_HOOK_STATUS="+status,
+			"ENCLAVE_HOOK_ERROR="+errMsg,
+		)
+	}
+
+	log.Debug().
+		Str("phase", string(phase)).
+		Str("command", inv.Command).
+		Str("hook", h.Run).
+		Msg("running hook")
+
+	return c.Run()
+}
@@ -0,0 +1,103 @@
+package i18n
+
+// de holds the German translations.
+var de = map[string]string{ // #nosec G101 -- translations, not credentials
+	// Errors and prompts.
+	"Error:":                       "Fehler:",
+	" (request ID: %s)":            " (Anfrage-ID: %s)",
+	"%s [y/N]: ":                   "%s [j/N]: ",
+	"Apply these changes?":         "Diese Änderungen anwenden?",
+	"%d planned changes":           "%d geplante Änderungen",
+	"Rolling back:":                "Wird zurückgesetzt:",
+	"%s is still referenced by:\n": "%s wird noch verwendet von:\n",
+	"%d of the given objects are still in use (use --force to delete anyway)": "%d der angegebenen Objekte werden noch verwendet (--force löscht sie trotzdem)",
+	"user %s":                "Benutzer %s",
+	"policy %s":              "Richtlinie %s",
+	"undo %s":                "%s rückgängig machen",
+	"undo canceled":          "Rückgängigmachen abgebrochen",
+	"Done.":                  "Fertig.",
+	"No results.":            "Keine Ergebnisse.",
+	"failed":                 "fehlgeschlagen",
+	"Every %s: %s":           "Alle %s: %s",
+	"New version available:": "Neue Version verfügbar:",
+	"A new version of encl is available: %s (current %s)\n": "Eine neue Version von encl ist verfügbar: %s (aktuell %s)\n",
+
+	// Progress.
+	"Running tests":               "Tests werden ausgeführt",
+	"Collecting tags":             "Tags werden gesammelt",
+	"Searching dependents":        "Abhängige Artefakte werden gesucht",
+	"Listing users":               "Benutzer werden abgerufen",
+	"Listing roles":               "Rollen werden abgerufen",
+	"Listing resource groups":     "Ressourcengruppen werden abgerufen",
+	"Listing policies":            "Richtlinien werden abgerufen",
+	"Listing tasks":               "Tasks werden abgerufen",
+	"Listing artifact namespaces": "Artefakt-Namespaces werden abgerufen",
+	"Listing artifacts":           "Artefakte werden abgerufen",
+	"Listing artifact versions":   "Artefaktversionen werden abgerufen",
+	"Uploading %s":                "%s wird hochgeladen",
+	"Downloading %s":              "%s wird heruntergeladen",
+	"Deleting users":              "Benutzer werden gelöscht",
+	"Deleting roles":              "Rollen werden gelöscht",
+	"Deleting resource groups":    "Ressourcengruppen werden gelöscht",
+	"Deleting artifact versions":  "Artefaktversionen werden gelöscht",
+
+	// Picker.
+	"Select a user":           "Benutzer auswählen",
+	"Select a role":           "Rolle auswählen",
+	"Select a resource group": "Ressourcengruppe auswählen",
+
+	// config, telemetry.
+	"Set %s = %s in %s\n": "%s = %s in %s gesetzt\n",
+	"Settings in %s":      "Einstellungen in %s",
+	"Saved %s to %s\n":    "%s in %s gespeichert\n",
+	"No changes.":         "Keine Änderungen.",
+
+	// edit.
+	"Edit canceled, no changes made.": "Bearbeitung abgebrochen, nichts geändert.",
+	"none":                            "keines",
+	"Telemetry: %s\nEndpoint:  %s\nPending:   %d events in %s\n": "Telemetrie: %s\nEndpunkt:   %s\nAusstehend: %d Ereignisse in %s\n",
+	"none (kept locally)":                               "keiner (nur lokal gespeichert)",
+	"Telemetry disabled; %d unsent events discarded.\n": "Telemetrie deaktiviert; %d nicht gesendete Ereignisse verworfen.\n",
+
+	// rbac undo.
+	"no RBAC changes to undo for %s":     "keine RBAC-Änderungen für %s zum Rückgängigmachen",
+	"Undoing %q run at %s against %s:\n": "Mache %q rückgängig, ausgeführt am %s gegen %s:\n",
+	"delete policy %s":                   "Richtlinie %s löschen",
+	"recreate policy %s":                 "Richtlinie %s wiederherstellen",
+	"delete role %s":                     "Rolle %s löschen",
+	"recreate role %s":                   "Rolle %s wiederherstellen",
+	", unassigning %s":                   ", Zuweisung an %s wird aufgehoben",
+	" for %s":                            " für %s",
+	"delete resource group %s":           "Ressourcengruppe %s löschen",
+	"recreate resource group %s":         "Ressourcengruppe %s wiederherstellen",
+	" with %s":                           " mit %s",
+
+	// rbac preset.
+	"canceled":                 "abgebrochen",
+	"create resource group %s": "Ressourcengruppe %s erstellen",
+	"update resource group %s": "Ressourcengruppe %s aktualisieren",
+	"create role %s":           "Rolle %s erstellen",
+	"update role %s":           "Rolle %s aktualisieren",
+	"create policy %s":         "Richtlinie %s erstellen",
+
+	// auth rotate.
+	"Password rotated. It is not stored in the config file; update it where the old one is kept:\n%s\n": "Passwort geändert. Es steht nicht in der Konfigurationsdatei; bitte dort aktualisieren, wo das alte gespeichert ist:\n%s\n",
+	"Password rotated and saved to %s\n":                                     "Passwort geändert und in %s gespeichert\n",
+	"The new password could not be saved; it is:\n%s\n":                      "Das neue Passwort konnte nicht gespeichert werden; es lautet:\n%s\n",
+	"The old password could not be restored; the password may now be:\n%s\n": "Das alte Passwort konnte nicht wiederhergestellt werden; das Passwort lautet jetzt möglicherweise:\n%s\n",
+
+	// task logs.
+	"Exported %d log entries to %s\n": "%d Logeinträge nach %s exportiert\n",
+
+	// user sync.
+	"user %s: role %s does not exist":          "Benutzer %s: Rolle %s existiert nicht",
+	"user %s: display name %q differs from %q": "Benutzer %s: Anzeigename %q weicht von %q ab",
+	"Not synchronized:":                        "Nicht synchronisiert:",
+	"  password of %s: %s\n":                   "  Passwort von %s: %s\n",
+
+	// artifact verify.
+	"%s matches %s/%s@%s (sha256 %s)\n": "%s stimmt mit %s/%s@%s überein (sha256 %s)\n",
+
+	// test.
+	"\n%d passed, %d failed\n": "\n%d bestanden, %d fehlgeschlagen\n",
+}
@@ -0,0 +1,75 @@
+// Package i18n translates user-facing CLI messages. Messages are looked up
+// by their English text, which is also used when a translation is missing.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Languages lists the supported language codes.
+var Languages = []string{"en", "de"}
+
+// catalogs maps language codes to translations keyed by the English text.
+// English needs no catalog.
+var catalogs = map[string]map[string]string{
+	"de": de,
+}
+
+// current holds the active translations; nil means English.
+var current map[string]string
+
+// SetLanguage activates lang. An empty lang or "auto" picks the language
+// from LC_ALL, LC_MESSAGES, or LANG, falling back to English.
+func SetLanguage(lang string) error {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" || lang == "auto" {
+		lang = fromEnv()
+	}
+	if !slices.Contains(Languages, lang) {
+		return fmt.Errorf(
+			"unsupported language %q (available: %s)",
+			lang,
+			strings.Join(Languages, ", "),
+		)
+	}
+	current = catalogs[lang]
+
+	return nil
+}
+
+// T returns the translation of msg in the active language.
+func T(msg string) string {
+	if s, ok := current[msg]; ok {
+		return s
+	}
+
+	return msg
+}
+
+// Sprintf formats the translation of format in the active language.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// fromEnv returns the supported language named by the locale environment,
+// e.g. "de" for LANG=de_DE.UTF-8, or "en".
+func fromEnv() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(v), "_")
+		lang, _, _ = strings.Cut(lang, ".")
+		if slices.Contains(Languages, lang) {
+			return lang
+		}
+
+		return "en"
+	}
+
+	return "en"
+}
@@ -0,0 +1,116 @@
+package mockserver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is the seed data loaded into the mock server at startup.
+type Fixture struct {
+	Users          []FixtureUser          `yaml:"users"`
+	Roles          []FixtureRole          `yaml:"roles"`
+	ResourceGroups []FixtureResourceGroup `yaml:"resourceGroups"`
+	Policies       []FixturePolicy        `yaml:"policies"`
+	Artifacts      []FixtureArtifact      `yaml:"artifacts"`
+	Tasks          []FixtureTask          `yaml:"tasks"`
+}
+
+// FixtureUser seeds a user. Password is required to authenticate as the user.
+type FixtureUser struct {
+	Name        string `yaml:"name"`
+	DisplayName string `yaml:"displayName"`
+	Password    string `yaml:"password"`
+}
+
+// FixtureRole seeds a role and its members.
+type FixtureRole struct {
+	Name  string   `yaml:"name"`
+	Users []string `yaml:"users"`
+}
+
+// FixtureResourceGroup seeds a resource group.
+type FixtureResourceGroup struct {
+	Name      string   `yaml:"name"`
+	Endpoints []string `yaml:"endpoints"`
+}
+
+// FixturePolicy seeds an RBAC policy.
+type FixturePolicy struct {
+	Role          string `yaml:"role"`
+	ResourceGroup string `yaml:"resourceGroup"`
+	Method        string `yaml:"method"`
+}
+
+// FixtureArtifact seeds an artifact version. The content is taken from File
+// (relative to the fixture) or, if unset, from Content.
+type FixtureArtifact struct {
+	Namespace string   `yaml:"namespace"`
+	Name      string   `yaml:"name"`
+	Tags      []string `yaml:"tags"`
+	File      string   `yaml:"file"`
+	Content   string   `yaml:"content"`
+}
+
+// FixtureTask seeds a task with an optional fixed state.
+type FixtureTask struct {
+	Source string `yaml:"source"`
+	State  string `yaml:"state"`
+	Error  string `yaml:"error"`
+}
+
+// defaultFixture is used when no fixture file is given: a single admin user
+// with full access.
+var defaultFixture = Fixture{
+	Users: []FixtureUser{
+		{Name: "admin", DisplayName: "Administrator", Password: "admin"},
+	},
+	Roles: []FixtureRole{{Name: "admin", Users: []string{"admin"}}},
+	ResourceGroups: []FixtureResourceGroup{
+		{Name: "all", Endpoints: []string{"*"}},
+	},
+	Policies: []FixturePolicy{
+		{Role: "admin", ResourceGroup: "all", Method: "*"},
+	},
+}
+
+// LoadFixture reads a YAML fixture file. An empty path returns the default
+// fixture.
+func LoadFixture(path string) (*Fixture, error) {
+	if path == "" {
+		f := defaultFixture
+
+		return &f, nil
+	}
+
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+	var f Fixture
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+
+	// Resolve artifact files relative to the fixture location.
+	dir := filepath.Dir(path)
+	for i := range f.Artifacts {
+		a := &f.Artifacts[i]
+		if a.File == "" {
+			continue
+		}
+		p := a.File
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		content, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return nil, fmt.Errorf("read fixture artifact: %w", err)
+		}
+		a.Content = string(content)
+	}
+
+	return &f, nil
+}
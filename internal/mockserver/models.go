@@ -0,0 +1,80 @@
+package mockserver
+
+import "time"
+
+// Wire models mirroring the Enclave OpenAPI schemas.
+
+type userResponse struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	Roles       []string `json:"roles"`
+}
+
+type userRequest struct {
+	Password    *string   `json:"password"`
+	DisplayName *string   `json:"displayName"`
+	Roles       *[]string `json:"roles"`
+}
+
+type roleResource struct {
+	Name  string   `json:"name"`
+	Users []string `json:"users"`
+}
+
+type resourceGroupResource struct {
+	Name      string   `json:"name"`
+	Endpoints []string `json:"endpoints"`
+}
+
+type policy struct {
+	Role          string `json:"role"`
+	ResourceGroup string `json:"resourceGroup"`
+	Method        string `json:"method"`
+}
+
+type artifact struct {
+	Namespace   string    `json:"namespace"`
+	Name        string    `json:"name"`
+	VersionHash string    `json:"versionHash"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Pulls       int       `json:"pulls"`
+	Tags        []string  `json:"tags"`
+}
+
+type envVar struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type taskStatus struct {
+	Retries       int        `json:"retries"`
+	State         string     `json:"state"`
+	ResultPayload string     `json:"result_payload,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastFailedAt  *time.Time `json:"last_failed_at,omitempty"`
+	NextProcessAt *time.Time `json:"next_process_at,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+type task struct {
+	ID        string     `json:"id"`
+	Source    string     `json:"source"`
+	Params    []any      `json:"params,omitempty"`
+	Args      []string   `json:"args,omitempty"`
+	Env       []envVar   `json:"env,omitempty"`
+	Callback  string     `json:"callback,omitempty"`
+	Retention string     `json:"retention,omitempty"`
+	Retries   int        `json:"retries,omitempty"`
+	Status    taskStatus `json:"status"`
+}
+
+type taskLog struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Issuer    string    `json:"issuer"`
+	Message   string    `json:"message"`
+}
+
+type errGeneric struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,952 @@
+// Package mockserver implements an in-memory Enclave API for testing scripts
+// and the CLI itself without a real deployment.
+package mockserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/rs/zerolog/log"
+)
+
+// maxUploadSize bounds artifact uploads accepted by the mock server.
+const maxUploadSize = 64 << 20
+
+type mockUser struct {
+	displayName string
+	password    string
+}
+
+type artifactVersion struct {
+	meta    artifact
+	content []byte
+}
+
+// Server is an in-memory implementation of the Enclave API. All state lives
+// in memory and is lost when the process exits.
+type Server struct {
+	mu        sync.Mutex
+	users     map[string]*mockUser
+	roles     map[string][]string
+	groups    map[string][]string
+	policies  []policy
+	artifacts []*artifactVersion
+	tasks     []*task
+	logs      map[string][]taskLog
+}
+
+type contextKey int
+
+const userKey contextKey = iota
+
+// New creates a server seeded with the given fixture.
+func New(f *Fixture) *Server {
+	s := &Server{
+		users:  map[string]*mockUser{},
+		roles:  map[string][]string{},
+		groups: map[string][]string{},
+		logs:   map[string][]taskLog{},
+	}
+	for _, u := range f.Users {
+		s.users[u.Name] = &mockUser{
+			displayName: u.DisplayName,
+			password:    u.Password,
+		}
+	}
+	for _, r := range f.Roles {
+		s.roles[r.Name] = slices.Clone(r.Users)
+	}
+	for _, g := range f.ResourceGroups {
+		s.groups[g.Name] = slices.Clone(g.Endpoints)
+	}
+	for _, p := range f.Policies {
+		s.policies = append(s.policies, policy(p))
+	}
+	for _, a := range f.Artifacts {
+		v := s.addArtifact(a.Namespace, a.Name, []byte(a.Content))
+		s.setTags(v, a.Tags)
+	}
+	for _, t := range f.Tasks {
+		created := s.addTask(&task{Source: t.Source})
+		if t.State != "" {
+			created.Status.State = t.State
+			created.Status.LastError = t.Error
+		}
+	}
+
+	return s
+}
+
+// Handler returns the HTTP handler serving the Enclave API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/user", s.listUsers)
+	mux.HandleFunc("GET /v1/user/me", s.getMe)
+	mux.HandleFunc("PATCH /v1/user/me", s.patchMe)
+	mux.HandleFunc("DELETE /v1/user/me", s.deleteMe)
+	mux.HandleFunc("GET /v1/user/{username}", s.getUser)
+	mux.HandleFunc("PUT /v1/user/{username}", s.putUser)
+	mux.HandleFunc("PATCH /v1/user/{username}", s.patchUser)
+	mux.HandleFunc("DELETE /v1/user/{username}", s.deleteUser)
+
+	mux.HandleFunc("GET /v1/rbac/role", s.listRoles)
+	mux.HandleFunc("GET /v1/rbac/role/{role}", s.getRole)
+	mux.HandleFunc("PUT /v1/rbac/role/{role}", s.putRole)
+	mux.HandleFunc("DELETE /v1/rbac/role/{role}", s.deleteRole)
+
+	mux.HandleFunc("GET /v1/rbac/resource-group", s.listGroups)
+	mux.HandleFunc("GET /v1/rbac/resource-group/{rg}", s.getGroup)
+	mux.HandleFunc("PUT /v1/rbac/resource-group/{rg}", s.putGroup)
+	mux.HandleFunc("DELETE /v1/rbac/resource-group/{rg}", s.deleteGroup)
+
+	mux.HandleFunc("GET /v1/rbac/policy", s.listPolicies)
+	mux.HandleFunc("PUT /v1/rbac/policy", s.putPolicy)
+	mux.HandleFunc("DELETE /v1/rbac/policy", s.deletePolicy)
+
+	mux.HandleFunc("POST /v1/artifact/raw/{ns}/{name}", s.uploadArtifact)
+	mux.HandleFunc(
+		"GET /v1/artifact/raw/{ns}/{name}/tag/{tag}",
+		s.downloadArtifact,
+	)
+	mux.HandleFunc(
+		"GET /v1/artifact/raw/{ns}/{name}/hash/{hash}",
+		s.downloadArtifact,
+	)
+	mux.HandleFunc("GET /v1/artifact", s.listNamespaces)
+	mux.HandleFunc("GET /v1/artifact/{ns}", s.listArtifacts)
+	mux.HandleFunc("GET /v1/artifact/{ns}/{name}", s.listVersions)
+	mux.HandleFunc("GET /v1/artifact/{ns}/{name}/tag/{tag}", s.getArtifact)
+	mux.HandleFunc("GET /v1/artifact/{ns}/{name}/hash/{hash}", s.getArtifact)
+	mux.HandleFunc("PATCH /v1/artifact/{ns}/{name}/tag/{tag}", s.patchArtifact)
+	mux.HandleFunc("PATCH /v1/artifact/{ns}/{name}/hash/{hash}", s.patchArtifact)
+	mux.HandleFunc("DELETE /v1/artifact/{ns}/{name}/tag/{tag}", s.deleteArtifact)
+	mux.HandleFunc(
+		"DELETE /v1/artifact/{ns}/{name}/hash/{hash}",
+		s.deleteArtifact,
+	)
+
+	mux.HandleFunc("GET /v1/task", s.listTasks)
+	mux.HandleFunc("POST /v1/task", s.createTask)
+	mux.HandleFunc("GET /v1/task/{id}", s.getTask)
+	mux.HandleFunc("GET /v1/task/{id}/logs", s.getTaskLogs)
+
+	return s.authenticate(mux)
+}
+
+// authenticate enforces basic auth against the in-memory users and tags
+// every response with a request ID.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", newID())
+		name, pass, ok := r.BasicAuth()
+		s.mu.Lock()
+		u := s.users[name]
+		s.mu.Unlock()
+		if !ok || u == nil || u.password != pass {
+			w.Header().Set("WWW-Authenticate", `Basic realm="enclave"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(
+			context.WithValue(r.Context(), userKey, name),
+		))
+	})
+}
+
+// --- users ---
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.URL.Query().Get("name")
+	display := r.URL.Query().Get("display-name")
+	out := []userResponse{}
+	for _, n := range sortedKeys(s.users) {
+		u := s.users[n]
+		if (name != "" && n != name) ||
+			(display != "" && u.displayName != display) {
+			continue
+		}
+		out = append(out, s.userResponse(n))
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("username")
+	if s.users[name] == nil {
+		writeError(w, http.StatusNotFound, "user not found")
+
+		return
+	}
+	writeJSON(w, http.StatusOK, s.userResponse(name))
+}
+
+func (s *Server) putUser(w http.ResponseWriter, r *http.Request) {
+	var req userRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Password == nil || req.DisplayName == nil {
+		writeError(
+			w,
+			http.StatusBadRequest,
+			"password and displayName are required",
+		)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("username")
+	if s.users[name] != nil {
+		writeError(w, http.StatusConflict, "user already exists")
+
+		return
+	}
+	s.users[name] = &mockUser{
+		displayName: *req.DisplayName,
+		password:    *req.Password,
+	}
+	if req.Roles != nil {
+		s.setUserRoles(name, *req.Roles)
+	}
+	writeJSON(w, http.StatusCreated, s.userResponse(name))
+}
+
+func (s *Server) patchUser(w http.ResponseWriter, r *http.Request) {
+	s.patch(w, r, r.PathValue("username"))
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	s.remove(w, r.PathValue("username"))
+}
+
+func (s *Server) getMe(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.userResponse(currentUser(r)))
+}
+
+func (s *Server) patchMe(w http.ResponseWriter, r *http.Request) {
+	s.patch(w, r, currentUser(r))
+}
+
+func (s *Server) deleteMe(w http.ResponseWriter, r *http.Request) {
+	s.remove(w, currentUser(r))
+}
+
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, name string) {
+	var req userRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.users[name]
+	if u == nil {
+		writeError(w, http.StatusNotFound, "user not found")
+
+		return
+	}
+	if req.DisplayName != nil {
+		u.displayName = *req.DisplayName
+	}
+	if req.Password != nil {
+		u.password = *req.Password
+	}
+	if req.Roles != nil {
+		s.setUserRoles(name, *req.Roles)
+	}
+	writeJSON(w, http.StatusOK, s.userResponse(name))
+}
+
+func (s *Server) remove(w http.ResponseWriter, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.users[name] == nil {
+		writeError(w, http.StatusNotFound, "user not found")
+
+		return
+	}
+	resp := s.userResponse(name)
+	s.setUserRoles(name, nil)
+	delete(s.users, name)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// userResponse renders a user with the roles it is a member of. Callers must
+// hold s.mu.
+func (s *Server) userResponse(name string) userResponse {
+	resp := userResponse{Name: name, Roles: []string{}}
+	if u := s.users[name]; u != nil {
+		resp.DisplayName = u.displayName
+	}
+	for _, role := range sortedKeys(s.roles) {
+		if slices.Contains(s.roles[role], name) {
+			resp.Roles = append(resp.Roles, role)
+		}
+	}
+
+	return resp
+}
+
+// setUserRoles makes name a member of exactly roles, creating missing roles.
+// Callers must hold s.mu.
+func (s *Server) setUserRoles(name string, roles []string) {
+	for role, members := range s.roles {
+		s.roles[role] = slices.DeleteFunc(members, func(m string) bool {
+			return m == name
+		})
+	}
+	for _, role := range roles {
+		s.roles[role] = append(s.roles[role], name)
+	}
+}
+
+// --- roles ---
+
+func (s *Server) listRoles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	filter := r.URL.Query().Get("role")
+	out := []roleResource{}
+	for _, name := range sortedKeys(s.roles) {
+		if filter != "" && name != filter {
+			continue
+		}
+		out = append(out, roleResource{Name: name, Users: nonNil(s.roles[name])})
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) getRole(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("role")
+	users, ok := s.roles[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+
+		return
+	}
+	writeJSON(w, http.StatusOK, roleResource{Name: name, Users: nonNil(users)})
+}
+
+func (s *Server) putRole(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Users []string `json:"users"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("role")
+	s.roles[name] = slices.Clone(req.Users)
+	writeJSON(
+		w,
+		http.StatusCreated,
+		roleResource{Name: name, Users: nonNil(req.Users)},
+	)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("role")
+	users, ok := s.roles[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "role not found")
+
+		return
+	}
+	delete(s.roles, name)
+	s.policies = slices.DeleteFunc(s.policies, func(p policy) bool {
+		return p.Role == name
+	})
+	writeJSON(w, http.StatusOK, roleResource{Name: name, Users: nonNil(users)})
+}
+
+// --- resource groups ---
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	role := r.URL.Query().Get("role")
+	out := []resourceGroupResource{}
+	for _, name := range sortedKeys(s.groups) {
+		if role != "" && !slices.ContainsFunc(s.policies, func(p policy) bool {
+			return p.Role == role && p.ResourceGroup == name
+		}) {
+			continue
+		}
+		out = append(out, resourceGroupResource{
+			Name:      name,
+			Endpoints: nonNil(s.groups[name]),
+		})
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("rg")
+	endpoints, ok := s.groups[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "resource group not found")
+
+		return
+	}
+	writeJSON(w, http.StatusOK, resourceGroupResource{
+		Name:      name,
+		Endpoints: nonNil(endpoints),
+	})
+}
+
+func (s *Server) putGroup(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Endpoints []string `json:"endpoints"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("rg")
+	s.groups[name] = slices.Clone(req.Endpoints)
+	writeJSON(w, http.StatusCreated, resourceGroupResource{
+		Name:      name,
+		Endpoints: nonNil(req.Endpoints),
+	})
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := r.PathValue("rg")
+	endpoints, ok := s.groups[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "resource group not found")
+
+		return
+	}
+	delete(s.groups, name)
+	s.policies = slices.DeleteFunc(s.policies, func(p policy) bool {
+		return p.ResourceGroup == name
+	})
+	writeJSON(w, http.StatusOK, resourceGroupResource{
+		Name:      name,
+		Endpoints: nonNil(endpoints),
+	})
+}
+
+// --- policies ---
+
+func (s *Server) listPolicies(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	out := []policy{}
+	for _, p := range s.policies {
+		if (q.Get("role") != "" && p.Role != q.Get("role")) ||
+			(q.Get("resource-group") != "" &&
+				p.ResourceGroup != q.Get("resource-group")) ||
+			(q.Get("method") != "" && p.Method != q.Get("method")) {
+			continue
+		}
+		out = append(out, p)
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) putPolicy(w http.ResponseWriter, r *http.Request) {
+	var p policy
+	if !decode(w, r, &p) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.roles[p.Role]; !ok && p.Role != "*" {
+		writeError(w, http.StatusNotFound, "role not found")
+
+		return
+	}
+	if _, ok := s.groups[p.ResourceGroup]; !ok && p.ResourceGroup != "*" {
+		writeError(w, http.StatusNotFound, "resource group not found")
+
+		return
+	}
+	if !slices.Contains(s.policies, p) {
+		s.policies = append(s.policies, p)
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) deletePolicy(w http.ResponseWriter, r *http.Request) {
+	var p policy
+	if !decode(w, r, &p) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.policies = slices.DeleteFunc(s.policies, func(q policy) bool {
+		return q == p
+	})
+	w.WriteHeader(http.StatusOK)
+}
+
+// --- artifacts ---
+
+func (s *Server) listNamespaces(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := []artifact{}
+	seen := map[string]bool{}
+	for _, v := range s.artifacts {
+		if !seen[v.meta.Namespace] {
+			seen[v.meta.Namespace] = true
+			out = append(out, artifact{Namespace: v.meta.Namespace, Tags: []string{}})
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) listArtifacts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns := r.PathValue("ns")
+	out := []artifact{}
+	index := map[string]int{}
+	for _, v := range s.artifacts {
+		if v.meta.Namespace != ns {
+			continue
+		}
+		// Report the newest version of each artifact.
+		if i, ok := index[v.meta.Name]; ok {
+			out[i] = v.meta
+		} else {
+			index[v.meta.Name] = len(out)
+			out = append(out, v.meta)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) listVersions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := []artifact{}
+	for _, v := range s.artifacts {
+		if v.meta.Namespace == r.PathValue("ns") &&
+			v.meta.Name == r.PathValue("name") {
+			out = append(out, v.meta)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) uploadArtifact(w http.ResponseWriter, r *http.Request) {
+	body, err := decodeBody(r)
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+
+		return
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(w, body, maxUploadSize))
+	if err != nil {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ns, name := r.PathValue("ns"), r.PathValue("name")
+	sum := sha256.Sum256(content)
+	if s.findArtifact(ns, name, "hash", hex.EncodeToString(sum[:])) != nil {
+		writeError(w, http.StatusConflict, "artifact version already exists")
+
+		return
+	}
+	v := s.addArtifact(ns, name, content)
+	writeJSON(w, http.StatusCreated, map[string]string{
+		"versionHash": v.meta.VersionHash,
+	})
+}
+
+func (s *Server) getArtifact(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.artifactFromPath(r)
+	if v == nil {
+		writeError(w, http.StatusNotFound, "artifact not found")
+
+		return
+	}
+	writeJSON(w, http.StatusOK, v.meta)
+}
+
+func (s *Server) patchArtifact(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Tags *[]string `json:"tags"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.artifactFromPath(r)
+	if v == nil {
+		writeError(w, http.StatusNotFound, "artifact not found")
+
+		return
+	}
+	if req.Tags != nil {
+		s.setTags(v, *req.Tags)
+	}
+	writeJSON(w, http.StatusOK, v.meta)
+}
+
+func (s *Server) deleteArtifact(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := s.artifactFromPath(r)
+	if v == nil {
+		writeError(w, http.StatusNotFound, "artifact not found")
+
+		return
+	}
+	s.artifacts = slices.DeleteFunc(s.artifacts, func(a *artifactVersion) bool {
+		return a == v
+	})
+	writeJSON(w, http.StatusOK, v.meta)
+}
+
+func (s *Server) downloadArtifact(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	v := s.artifactFromPath(r)
+	if v != nil {
+		v.meta.Pulls++
+	}
+	s.mu.Unlock()
+
+	if v == nil {
+		writeError(w, http.StatusNotFound, "artifact not found")
+
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.Itoa(len(v.content)))
+	_, _ = w.Write(v.content)
+}
+
+// artifactFromPath resolves the {ns}/{name}/(tag|hash) path values.
+// Callers must hold s.mu.
+func (s *Server) artifactFromPath(r *http.Request) *artifactVersion {
+	ns, name := r.PathValue("ns"), r.PathValue("name")
+	if hash := r.PathValue("hash"); hash != "" {
+		return s.findArtifact(ns, name, "hash", hash)
+	}
+
+	return s.findArtifact(ns, name, "tag", r.PathValue("tag"))
+}
+
+// findArtifact looks up a version by tag or hash. Callers must hold s.mu.
+func (s *Server) findArtifact(ns, name, kind, ref string) *artifactVersion {
+	for _, v := range s.artifacts {
+		if v.meta.Namespace != ns || v.meta.Name != name {
+			continue
+		}
+		if (kind == "hash" && v.meta.VersionHash == ref) ||
+			(kind == "tag" && slices.Contains(v.meta.Tags, ref)) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// addArtifact stores a new version. Callers must hold s.mu or own s.
+func (s *Server) addArtifact(ns, name string, content []byte) *artifactVersion {
+	sum := sha256.Sum256(content)
+	v := &artifactVersion{
+		meta: artifact{
+			Namespace:   ns,
+			Name:        name,
+			VersionHash: hex.EncodeToString(sum[:]),
+			CreatedAt:   time.Now().UTC().Truncate(time.Second),
+			Tags:        []string{},
+		},
+		content: content,
+	}
+	s.artifacts = append(s.artifacts, v)
+
+	return v
+}
+
+// setTags replaces the tags of v, moving any tag already held by another
+// version of the same artifact. Callers must hold s.mu or own s.
+func (s *Server) setTags(v *artifactVersion, tags []string) {
+	for _, other := range s.artifacts {
+		if other == v || other.meta.Namespace != v.meta.Namespace ||
+			other.meta.Name != v.meta.Name {
+			continue
+		}
+		other.meta.Tags = slices.DeleteFunc(other.meta.Tags, func(t string) bool {
+			return slices.Contains(tags, t)
+		})
+	}
+	v.meta.Tags = nonNil(slices.Clone(tags))
+}
+
+// --- tasks ---
+
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := r.URL.Query().Get("state")
+	out := []task{}
+	for _, t := range s.tasks {
+		if state == "" || t.Status.State == state {
+			out = append(out, *t)
+		}
+	}
+	writeJSON(w, http.StatusOK, paginate(r, out))
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req task
+	if !decode(w, r, &req) {
+		return
+	}
+	if req.Source == "" {
+		writeError(w, http.StatusBadRequest, "source is required")
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, s.addTask(&req))
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.findTask(r.PathValue("id"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+
+		return
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) getTaskLogs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	if s.findTask(id) == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+
+		return
+	}
+
+	q := r.URL.Query()
+	from, _ := time.Parse(time.RFC3339, q.Get("time-range-from"))
+	to, _ := time.Parse(time.RFC3339, q.Get("time-range-to"))
+	out := []taskLog{}
+	for _, l := range s.logs[id] {
+		if (q.Get("level") != "" && l.Level != q.Get("level")) ||
+			(q.Get("issuer") != "" && l.Issuer != q.Get("issuer")) ||
+			(!from.IsZero() && l.Timestamp.Before(from)) ||
+			(!to.IsZero() && l.Timestamp.After(to)) {
+			continue
+		}
+		out = append(out, l)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// addTask stores t as an immediately completed task. The mock server does
+// not execute anything. Callers must hold s.mu or own s.
+func (s *Server) addTask(t *task) *task {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	t.ID = newID()
+	t.Status = taskStatus{State: "completed", CompletedAt: &now}
+	s.tasks = append(s.tasks, t)
+	s.logs[t.ID] = []taskLog{
+		{
+			Timestamp: now,
+			Level:     "info",
+			Issuer:    "mock-server",
+			Message:   "task accepted",
+		},
+		{
+			Timestamp: now,
+			Level:     "info",
+			Issuer:    "mock-server",
+			Message:   "task completed",
+		},
+	}
+
+	return t
+}
+
+// findTask looks up a task by ID. Callers must hold s.mu.
+func (s *Server) findTask(id string) *task {
+	for _, t := range s.tasks {
+		if t.ID == id {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// --- helpers ---
+
+func currentUser(r *http.Request) string {
+	name, _ := r.Context().Value(userKey).(string)
+
+	return name
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil &&
+		!errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+
+		return false
+	}
+
+	return true
+}
+
+// decodeBody undoes a gzip or zstd Content-Encoding on the request body.
+func decodeBody(r *http.Request) (io.ReadCloser, error) {
+	switch enc := r.Header.Get("Content-Encoding"); enc {
+	case "", "identity":
+		return r.Body, nil
+	case "gzip":
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("gzip: %w", err)
+		}
+
+		return zr, nil
+	case "zstd":
+		zr, err := zstd.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("zstd: %w", err)
+		}
+
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", enc)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debug().Err(err).Msg("mock server: write response")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errGeneric{Error: msg})
+}
+
+// paginate applies the limit/offset query parameters to items.
+func paginate[T any](r *http.Request, items []T) []T {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	offset = min(max(offset, 0), len(items))
+	end := len(items)
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil &&
+		limit > 0 {
+		end = min(offset+limit, end)
+	}
+
+	return items[offset:end]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
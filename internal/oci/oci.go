@@ -0,0 +1,257 @@
+// Package oci pulls blobs from OCI registries through the distribution API,
+// authenticating with docker credentials when a registry asks for them.
+package oci
+
+import (
+	"cli/internal/dockercreds"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Scheme prefixes references on the command line, e.g.
+// oci://ghcr.io/acme/filter:1.2.
+const Scheme = "oci://"
+
+// manifestTypes are the manifest media types accepted from registries.
+var manifestTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// Ref is a parsed image reference.
+type Ref struct {
+	Registry   string
+	Repository string
+	// Reference is a tag or a digest.
+	Reference string
+}
+
+// Descriptor identifies a blob in a manifest.
+type Descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// Manifest is an image manifest.
+type Manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []Descriptor `json:"layers"`
+	// Digest is the manifest digest reported by the registry.
+	Digest string `json:"-"`
+}
+
+// Client talks to OCI registries.
+type Client struct {
+	http   *http.Client
+	tokens map[string]string
+}
+
+// ParseRef parses oci://registry/repository[:tag|@digest]. The tag defaults
+// to latest.
+func ParseRef(s string) (Ref, error) {
+	rest, ok := strings.CutPrefix(s, Scheme)
+	if !ok {
+		return Ref{}, fmt.Errorf("%q is not an %s reference", s, Scheme)
+	}
+	registry, repo, ok := strings.Cut(rest, "/")
+	if !ok || registry == "" || repo == "" {
+		return Ref{}, fmt.Errorf("%q lacks a registry or repository", s)
+	}
+
+	ref := Ref{Registry: registry, Repository: repo, Reference: "latest"}
+	if r, digest, ok := strings.Cut(repo, "@"); ok {
+		ref.Repository, ref.Reference = r, digest
+	} else if i := strings.LastIndex(repo, ":"); i > 0 {
+		ref.Repository, ref.Reference = repo[:i], repo[i+1:]
+	}
+
+	return ref, nil
+}
+
+// String formats r as registry/repository:tag or registry/repository@digest.
+func (r Ref) String() string {
+	sep := ":"
+	if strings.Contains(r.Reference, ":") {
+		sep = "@"
+	}
+
+	return r.Registry + "/" + r.Repository + sep + r.Reference
+}
+
+// NewClient returns a client that sends requests through rt.
+func NewClient(rt http.RoundTripper) *Client {
+	return &Client{
+		http:   &http.Client{Transport: rt},
+		tokens: map[string]string{},
+	}
+}
+
+// Manifest fetches the image manifest of ref.
+func (c *Client) Manifest(ctx context.Context, ref Ref) (*Manifest, error) {
+	resp, err := c.get(
+		ctx,
+		ref,
+		"/manifests/"+ref.Reference,
+		strings.Join(manifestTypes, ", "),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var m Manifest
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decode manifest of %s: %w", ref, err)
+	}
+	m.Digest = resp.Header.Get("Docker-Content-Digest")
+
+	return &m, nil
+}
+
+// Blob opens the blob with the given digest. The caller must close it.
+func (c *Client) Blob(
+	ctx context.Context,
+	ref Ref,
+	digest string,
+) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, ref, "/blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// get requests a path below the repository, answering a bearer token
+// challenge once.
+func (c *Client) get(
+	ctx context.Context,
+	ref Ref,
+	path, accept string,
+) (*http.Response, error) {
+	u := "https://" + ref.Registry + "/v2/" + ref.Repository + path
+	if isLocal(ref.Registry) {
+		u = "http://" + ref.Registry + "/v2/" + ref.Repository + path
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if token := c.tokens[ref.Registry]; token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", ref, err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return nil, fmt.Errorf("fetch %s: %s", ref, resp.Status)
+		}
+		if err := c.authenticate(ctx, ref, challenge); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// authenticate obtains a bearer token for ref from the realm named in a
+// WWW-Authenticate challenge. Stored docker credentials are sent when
+// available; otherwise an anonymous token is requested.
+func (c *Client) authenticate(
+	ctx context.Context,
+	ref Ref,
+	challenge string,
+) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("%s: unsupported auth challenge %q", ref.Registry, scheme)
+	}
+	p := parseChallenge(params)
+	if p["realm"] == "" {
+		return fmt.Errorf("%s: auth challenge without realm", ref.Registry)
+	}
+
+	q := url.Values{}
+	if p["service"] != "" {
+		q.Set("service", p["service"])
+	}
+	q.Set("scope", "repository:"+ref.Repository+":pull")
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		p["realm"]+"?"+q.Encode(),
+		http.NoBody,
+	)
+	if err != nil {
+		return err
+	}
+	user, secret, err := dockercreds.Lookup(ctx, dockercreds.Docker, ref.Registry)
+	switch {
+	case err == nil:
+		req.SetBasicAuth(user, secret)
+	case !errors.Is(err, dockercreds.ErrNotFound):
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: request token: %w", ref.Registry, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: request token: %s", ref.Registry, resp.Status)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("%s: decode token: %w", ref.Registry, err)
+	}
+	c.tokens[ref.Registry] = body.Token
+	if body.Token == "" {
+		c.tokens[ref.Registry] = body.AccessToken
+	}
+
+	return nil
+}
+
+// parseChallenge splits key="value" pairs of an auth challenge.
+func parseChallenge(s string) map[string]string {
+	params := map[string]string{}
+	for part := range strings.SplitSeq(s, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[strings.ToLower(k)] = strings.Trim(v, `"`)
+		}
+	}
+
+	return params
+}
+
+// isLocal reports whether registry is on the local machine, where registries
+// usually serve plain HTTP.
+func isLocal(registry string) bool {
+	host := registry
+	if h, _, ok := strings.Cut(registry, ":"); ok {
+		host = h
+	}
+
+	return host == "localhost" || host == "127.0.0.1"
+}
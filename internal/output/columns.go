@@ -1,7 +1,14 @@
 package output
 
 import (
+	"cli/internal/bench"
+	"cli/internal/history"
+	"cli/internal/i18n"
+	"cli/internal/rbac"
+	"cli/internal/recent"
 	"cli/internal/styles"
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -148,11 +155,45 @@ var TaskColumns = []Column{
 	}},
 	{Header: "NEXT PROCESS", Extract: func(r any) string {
 		t, _ := r.(enclave.Task)
-		if t.Status.NextProcessAt.IsZero() {
-			return "-"
-		}
 
-		return t.Status.NextProcessAt.Format(time.RFC3339)
+		return timestamp(t.Status.NextProcessAt, time.RFC3339)
+	}, SortKey: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return sortTime(t.Status.NextProcessAt)
+	}},
+	{Header: "COMPLETED", Wide: true, Extract: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return timestamp(t.Status.CompletedAt, time.RFC3339)
+	}, SortKey: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return sortTime(t.Status.CompletedAt)
+	}},
+	{Header: "LAST FAILED", Wide: true, Extract: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return timestamp(t.Status.LastFailedAt, time.RFC3339)
+	}, SortKey: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return sortTime(t.Status.LastFailedAt)
+	}},
+	{Header: "ARGS", Wide: true, Extract: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return strings.Join(t.Args, " ")
+	}},
+	{Header: "CALLBACK", Wide: true, Extract: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return t.Callback
+	}},
+	{Header: "RETENTION", Wide: true, Extract: func(r any) string {
+		t, _ := r.(enclave.Task)
+
+		return t.Retention
 	}},
 }
 
@@ -161,7 +202,7 @@ var TaskLogColumns = []Column{
 	{Header: "TIME", Extract: func(r any) string {
 		l, _ := r.(enclave.TaskLog)
 
-		return l.Timestamp.Format("15:04:05.000")
+		return localTime(l.Timestamp, "15:04:05.000")
 	}},
 	{
 		Header:   "LEVEL",
@@ -191,12 +232,84 @@ var TaskLogColumns = []Column{
 	},
 }
 
-// ArtifactColumns defines table columns for enclave.Artifact.
+// SizedArtifact is an artifact version together with its stored size.
+type SizedArtifact struct {
+	enclave.Artifact `yaml:",inline"`
+
+	// Size is the content length in bytes, or -1 if unknown.
+	Size int64
+}
+
+// TagUsage counts the artifact versions carrying a tag.
+type TagUsage struct {
+	Tag string
+	// Versions is the number of artifact versions with the tag.
+	Versions int
+	// Artifacts lists the artifacts with the tag, as <namespace>/<name>.
+	Artifacts []string
+}
+
+// TagColumns defines table columns for TagUsage.
+var TagColumns = []Column{
+	{Header: "TAG", Extract: func(r any) string {
+		t, _ := r.(TagUsage)
+
+		return t.Tag
+	}},
+	{Header: "VERSIONS", Extract: func(r any) string {
+		t, _ := r.(TagUsage)
+
+		return strconv.Itoa(t.Versions)
+	}},
+	{Header: "ARTIFACTS", Extract: func(r any) string {
+		t, _ := r.(TagUsage)
+
+		return strconv.Itoa(len(t.Artifacts))
+	}},
+	{Header: "ARTIFACT LIST", Wide: true, Extract: func(r any) string {
+		t, _ := r.(TagUsage)
+
+		return strings.Join(t.Artifacts, ", ")
+	}},
+}
+
+// artifactOf returns the artifact in row and its size, -1 if unknown.
+func artifactOf(row any) (a enclave.Artifact, size int64) {
+	switch v := row.(type) {
+	case SizedArtifact:
+		return v.Artifact, v.Size
+	case enclave.Artifact:
+		return v, -1
+	default:
+		return enclave.Artifact{}, -1
+	}
+}
+
+// DependsPrefix marks artifact tags that declare a dependency, e.g.
+// "depends:plugins/auth:v2". Artifacts have no other metadata to hold it.
+const DependsPrefix = "depends:"
+
+// UserTags returns tags without those declaring dependencies.
+func UserTags(tags []string) []string {
+	return slices.DeleteFunc(slices.Clone(tags), func(t string) bool {
+		return strings.HasPrefix(t, DependsPrefix)
+	})
+}
+
+// DependsTags returns the tags that declare dependencies.
+func DependsTags(tags []string) []string {
+	return slices.DeleteFunc(slices.Clone(tags), func(t string) bool {
+		return !strings.HasPrefix(t, DependsPrefix)
+	})
+}
+
+// ArtifactColumns defines table columns for enclave.Artifact and
+// SizedArtifact.
 var ArtifactColumns = []Column{
 	{
 		Header: "NAMESPACE",
 		Extract: func(r any) string {
-			a, _ := r.(enclave.Artifact)
+			a, _ := artifactOf(r)
 
 			return a.Namespace
 		},
@@ -204,13 +317,13 @@ var ArtifactColumns = []Column{
 	{
 		Header: "NAME",
 		Extract: func(r any) string {
-			a, _ := r.(enclave.Artifact)
+			a, _ := artifactOf(r)
 
 			return a.Name
 		},
 	},
 	{Header: "HASH", MinWidth: 16, Extract: func(r any) string {
-		a, _ := r.(enclave.Artifact)
+		a, _ := artifactOf(r)
 		h := a.VersionHash
 		if len(h) > 16 {
 			return h[:16]
@@ -221,19 +334,269 @@ var ArtifactColumns = []Column{
 	{
 		Header: "TAGS",
 		Extract: func(r any) string {
-			a, _ := r.(enclave.Artifact)
+			a, _ := artifactOf(r)
 
-			return strings.Join(a.Tags, ", ")
+			return strings.Join(UserTags(a.Tags), ", ")
 		},
 	},
 	{Header: "CREATED", Extract: func(r any) string {
-		a, _ := r.(enclave.Artifact)
+		a, _ := artifactOf(r)
+
+		return timestamp(a.CreatedAt, "2006-01-02 15:04")
+	}, SortKey: func(r any) string {
+		a, _ := artifactOf(r)
 
-		return a.CreatedAt.Format("2006-01-02 15:04")
+		return sortTime(a.CreatedAt)
 	}},
 	{Header: "PULLS", Extract: func(r any) string {
-		a, _ := r.(enclave.Artifact)
+		a, _ := artifactOf(r)
 
 		return strconv.Itoa(a.Pulls)
 	}},
+	{Header: "SIZE", Wide: true, Extract: func(r any) string {
+		_, size := artifactOf(r)
+		if size < 0 {
+			return "-"
+		}
+
+		return FormatSize(size, display.raw)
+	}, SortKey: func(r any) string {
+		_, size := artifactOf(r)
+		if size < 0 {
+			return ""
+		}
+
+		return strconv.FormatInt(size, 10)
+	}},
+	{Header: "FQN", Wide: true, Extract: func(r any) string {
+		a, _ := artifactOf(r)
+
+		return a.Namespace + "/" + a.Name + "@" + a.VersionHash
+	}},
+	{Header: "DEPENDS", Wide: true, Extract: func(r any) string {
+		a, _ := artifactOf(r)
+		deps := DependsTags(a.Tags)
+		for i, t := range deps {
+			deps[i] = strings.TrimPrefix(t, DependsPrefix)
+		}
+
+		return strings.Join(deps, ", ")
+	}},
+	{Header: "CREATED AT", Wide: true, Extract: func(r any) string {
+		a, _ := artifactOf(r)
+
+		return localTime(a.CreatedAt, time.RFC3339)
+	}},
+}
+
+// RecentColumns defines table columns for recent.Entry.
+var RecentColumns = []Column{
+	{Header: "ARTIFACT", Extract: func(r any) string {
+		e, _ := r.(recent.Entry)
+
+		return e.Namespace + "/" + e.Name
+	}},
+	{Header: "REF", MinWidth: 16, Extract: func(r any) string {
+		e, _ := r.(recent.Entry)
+		// Version hashes are shortened like in the HASH column.
+		if len(e.Ref) == 64 {
+			return e.Ref[:16]
+		}
+
+		return e.Ref
+	}},
+	{Header: "ACTION", Extract: func(r any) string {
+		e, _ := r.(recent.Entry)
+
+		return e.Action
+	}},
+	{Header: "TIME", Extract: func(r any) string {
+		e, _ := r.(recent.Entry)
+
+		return timestamp(e.Time, time.DateTime)
+	}, SortKey: func(r any) string {
+		e, _ := r.(recent.Entry)
+
+		return sortTime(e.Time)
+	}},
+}
+
+// HistoryColumns defines table columns for history.Entry.
+var HistoryColumns = []Column{
+	{Header: "TIME", Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+
+		return timestamp(e.Time, time.DateTime)
+	}, SortKey: func(r any) string {
+		e, _ := r.(history.Entry)
+
+		return sortTime(e.Time)
+	}},
+	{Header: "PROFILE", Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+		if e.Profile == "" {
+			return "-"
+		}
+
+		return e.Profile
+	}},
+	{
+		Header: "SERVER",
+		Extract: func(r any) string {
+			e, _ := r.(history.Entry)
+
+			return e.Server
+		},
+	},
+	{Header: "COMMAND", MinWidth: 20, Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+
+		return strings.Join(append([]string{e.Command}, e.Args...), " ")
+	}},
+	{Header: "STATUS", Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+		if e.Failed() {
+			return styles.ErrorStyle.Render(i18n.T("failed"))
+		}
+
+		return "ok"
+	}},
+	{Header: "DURATION", Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+
+		return e.Duration.Round(time.Millisecond).String()
+	}},
+	{Header: "USER", Wide: true, Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+
+		return e.User
+	}},
+	{Header: "FLAGS", Wide: true, Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+		flags := make([]string, 0, len(e.Flags))
+		for name, value := range e.Flags {
+			flags = append(flags, "--"+name+"="+value)
+		}
+		slices.Sort(flags)
+
+		return strings.Join(flags, " ")
+	}},
+	{Header: "ERROR", Wide: true, Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+
+		return e.Error
+	}},
+	{Header: "REQUEST ID", Wide: true, Extract: func(r any) string {
+		e, _ := r.(history.Entry)
+
+		return e.RequestID
+	}},
+}
+
+// BenchColumns defines table columns for bench.Report.
+var BenchColumns = []Column{
+	{
+		Header: "OPERATION",
+		Extract: func(r any) string {
+			b, _ := r.(bench.Report)
+
+			return b.Operation
+		},
+	},
+	{Header: "REQUESTS", Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return strconv.Itoa(b.Requests)
+	}},
+	{Header: "ERRORS", Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return strconv.Itoa(b.Errors)
+	}},
+	{Header: "ERROR RATE", Wide: true, Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return fmt.Sprintf("%.1f%%", 100*b.ErrorRate())
+	}},
+	{Header: "RATE", Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return fmt.Sprintf("%.1f/s", b.Rate())
+	}},
+	{Header: "THROUGHPUT", Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+		if b.Bytes == 0 {
+			return "-"
+		}
+
+		return FormatSize(int64(b.Throughput()), display.raw) + "/s"
+	}},
+	{Header: "P50", Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return roundLatency(b.P50)
+	}},
+	{Header: "P90", Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return roundLatency(b.P90)
+	}},
+	{Header: "P99", Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return roundLatency(b.P99)
+	}},
+	{Header: "MIN", Wide: true, Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return roundLatency(b.Min)
+	}},
+	{Header: "MAX", Wide: true, Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return roundLatency(b.Max)
+	}},
+	{Header: "CONCURRENCY", Wide: true, Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return strconv.Itoa(b.Concurrency)
+	}},
+	{Header: "FIRST ERROR", Wide: true, Extract: func(r any) string {
+		b, _ := r.(bench.Report)
+
+		return b.FirstError
+	}},
+}
+
+// roundLatency formats d with a precision that suits its magnitude.
+func roundLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond).String()
+	}
+
+	return d.Round(100 * time.Microsecond).String()
+}
+
+// RBACDiffColumns defines table columns for rbac.Change.
+var RBACDiffColumns = []Column{
+	{Header: "OP", Extract: func(r any) string {
+		c, _ := r.(rbac.Change)
+
+		return c.Op
+	}},
+	{Header: "KIND", Extract: func(r any) string {
+		c, _ := r.(rbac.Change)
+
+		return c.Kind
+	}},
+	{Header: "NAME", Extract: func(r any) string {
+		c, _ := r.(rbac.Change)
+
+		return c.Name
+	}},
+	{Header: "DETAIL", Extract: func(r any) string {
+		c, _ := r.(rbac.Change)
+
+		return c.Detail
+	}},
 }
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FanOutEnv is set in the environment of the per-profile processes run for
+// --profiles. It makes FromConfig emit a FanOut document that the parent
+// process merges.
+const FanOutEnv = "ENCLAVE_FANOUT"
+
+// FanOut is what one profile's process prints under FanOutEnv: the rendered
+// table cells, with filters and sorting applied, and the rows as JSON.
+type FanOut struct {
+	Headers []string          `json:"headers"`
+	Cells   [][]string        `json:"cells"`
+	Items   []json.RawMessage `json:"items"`
+}
+
+type fanOutPrinter struct {
+	table *tablePrinter
+	w     io.Writer
+}
+
+func (p *fanOutPrinter) Print(rows any) error {
+	columns, err := p.table.visibleColumns()
+	if err != nil {
+		return err
+	}
+	out := FanOut{Headers: make([]string, len(columns))}
+	for i, col := range columns {
+		out.Headers[i] = col.Header
+	}
+	for _, row := range toSlice(rows) {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = col.Extract(row)
+		}
+		item, err := json.Marshal(row)
+		if err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+		out.Cells = append(out.Cells, cells)
+		out.Items = append(out.Items, item)
+	}
+
+	return json.NewEncoder(p.w).Encode(out)
+}
+
+// ProfileRow is a row of merged --profiles output: the profile it came from
+// and the row as rendered and encoded by that profile's process.
+type ProfileRow struct {
+	Profile string
+	Cells   []string
+	Item    json.RawMessage
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r ProfileRow) MarshalJSON() ([]byte, error) {
+	fields, err := r.object()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (r ProfileRow) MarshalYAML() (any, error) {
+	return r.object()
+}
+
+// object returns the row's fields with a Profile field added.
+func (r ProfileRow) object() (map[string]any, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(r.Item, &fields); err != nil || fields == nil {
+		var item any
+		if err := json.Unmarshal(r.Item, &item); err != nil {
+			return nil, fmt.Errorf("decode json: %w", err)
+		}
+		fields = map[string]any{"Item": item}
+	}
+	fields["Profile"] = r.Profile
+
+	return fields, nil
+}
+
+// ProfileColumns returns the table columns for ProfileRow values: PROFILE
+// followed by the given headers.
+func ProfileColumns(headers []string) []Column {
+	columns := make([]Column, 0, 1+len(headers))
+	columns = append(
+		columns,
+		Column{Header: "PROFILE", Extract: func(r any) string {
+			p, _ := r.(ProfileRow)
+
+			return p.Profile
+		}},
+	)
+	for i, h := range headers {
+		columns = append(columns, Column{Header: h, Extract: func(r any) string {
+			p, _ := r.(ProfileRow)
+			if i >= len(p.Cells) {
+				return ""
+			}
+
+			return p.Cells[i]
+		}})
+	}
+
+	return columns
+}
@@ -0,0 +1,164 @@
+package output
+
+import (
+	"cli/internal/i18n"
+	"cli/internal/styles"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fieldsPrinter renders every field of each row as "Field: value" lines,
+// for --all-fields. Nested fields are named by their path, e.g.
+// "Status.CompletedAt".
+type fieldsPrinter struct {
+	w io.Writer
+}
+
+// field is a flattened field name and its rendered value.
+type field struct {
+	name  string
+	value string
+}
+
+func (p *fieldsPrinter) Print(rows any) error {
+	items := toSlice(rows)
+	if items == nil {
+		items = []any{rows}
+	}
+	if len(items) == 0 {
+		_, err := fmt.Fprintln(p.w, styles.MutedStyle.Render(i18n.T("No results.")))
+
+		return err
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			if _, err := fmt.Fprintln(p.w); err != nil {
+				return err
+			}
+		}
+		var fields []field
+		flatten("", reflect.ValueOf(item), &fields)
+		width := 0
+		for _, f := range fields {
+			width = max(width, len(f.name)+1)
+		}
+		for _, f := range fields {
+			if _, err := fmt.Fprintf(
+				p.w,
+				"%s %s\n",
+				styles.TitleStyle.Render(pad(f.name+":", width)),
+				f.value,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// flatten appends the fields of v to fields, naming them below prefix.
+// Fields of embedded structs are listed as if they were v's own.
+func flatten(prefix string, v reflect.Value, fields *[]field) {
+	add := func(value string) {
+		*fields = append(*fields, field{name: prefix, value: value})
+	}
+	kind := v.Kind()
+	if kind == reflect.Invalid ||
+		(kind == reflect.Pointer || kind == reflect.Interface) && v.IsNil() {
+		add("-")
+
+		return
+	}
+	if kind == reflect.Pointer || kind == reflect.Interface {
+		flatten(prefix, v.Elem(), fields)
+
+		return
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		add(localTime(t, time.RFC3339))
+
+		return
+	}
+	if scalar(v.Type()) {
+		add(fmt.Sprint(v.Interface()))
+
+		return
+	}
+	if kind == reflect.Struct {
+		flattenStruct(prefix, v, fields)
+
+		return
+	}
+	if v.Len() == 0 {
+		add("-")
+
+		return
+	}
+	if kind == reflect.Map {
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(
+				fmt.Sprint(a.Interface()),
+				fmt.Sprint(b.Interface()),
+			)
+		})
+		for _, k := range keys {
+			flatten(
+				fmt.Sprintf("%s.%v", prefix, k.Interface()),
+				v.MapIndex(k),
+				fields,
+			)
+		}
+
+		return
+	}
+	// Slices and arrays of scalars share a line.
+	if scalar(v.Type().Elem()) {
+		values := make([]string, v.Len())
+		for i := range v.Len() {
+			values[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		add(strings.Join(values, ", "))
+
+		return
+	}
+	for i := range v.Len() {
+		flatten(fmt.Sprintf("%s[%d]", prefix, i), v.Index(i), fields)
+	}
+}
+
+// flattenStruct appends the exported fields of the struct v to fields.
+func flattenStruct(prefix string, v reflect.Value, fields *[]field) {
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if f.Anonymous {
+			name = prefix
+		}
+		flatten(name, v.Field(i), fields)
+	}
+}
+
+// scalar reports whether values of t render on a single line.
+func scalar(t reflect.Type) bool {
+	return !slices.Contains([]reflect.Kind{
+		reflect.Struct,
+		reflect.Slice,
+		reflect.Array,
+		reflect.Map,
+		reflect.Pointer,
+		reflect.Interface,
+	}, t.Kind())
+}
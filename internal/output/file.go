@@ -0,0 +1,58 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// outputFile is the pending --output-file, if any.
+var outputFile struct {
+	path   string
+	tmp    *os.File
+	stdout *os.File
+}
+
+// RedirectToFile sends everything written to os.Stdout to a temporary file
+// next to path until FinishFile is called. Logs and errors stay on stderr.
+func RedirectToFile(path string) error {
+	tmp, err := os.CreateTemp(
+		filepath.Dir(path),
+		"."+filepath.Base(path)+".*.tmp",
+	)
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
+	}
+	outputFile.path = path
+	outputFile.tmp = tmp
+	outputFile.stdout = os.Stdout
+	os.Stdout = tmp
+
+	return nil
+}
+
+// FinishFile restores os.Stdout and, when keep is set, atomically replaces
+// the --output-file with what the command wrote. Otherwise the partial output
+// is discarded and an existing file is left untouched. It is a no-op when
+// output is not redirected.
+func FinishFile(keep bool) error {
+	tmp := outputFile.tmp
+	if tmp == nil {
+		return nil
+	}
+	os.Stdout = outputFile.stdout
+	outputFile.tmp = nil
+
+	err := tmp.Close()
+	if err == nil && keep {
+		err = os.Rename(tmp.Name(), outputFile.path)
+	}
+	if err != nil || !keep {
+		_ = os.Remove(tmp.Name())
+	}
+	if err != nil {
+		return fmt.Errorf("write output file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// filter is one condition of a --filter expression.
+type filter struct {
+	key string
+	// op is one of "=", "!=", "~", "!~".
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+// filterOps lists the operators, longest first so "!=" wins over "=".
+var filterOps = []string{"!=", "!~", "=", "~"}
+
+// parseFilters parses a --filter expression: comma-separated conditions of
+// the form key=value, key!=value, key~regex, or key!~regex, where key is a
+// column key as used with --columns. All conditions must hold.
+func parseFilters(expr string) ([]filter, error) {
+	var filters []filter
+	for cond := range strings.SplitSeq(expr, ",") {
+		cond = strings.TrimSpace(cond)
+		if cond == "" {
+			continue
+		}
+		f, err := parseFilter(cond)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+func parseFilter(cond string) (filter, error) {
+	at, op := -1, ""
+	for _, o := range filterOps {
+		if i := strings.Index(cond, o); i > 0 && (at < 0 || i < at) {
+			at, op = i, o
+		}
+	}
+	if at < 0 {
+		return filter{}, fmt.Errorf(
+			"invalid filter %q (use key=value or key~regex)",
+			cond,
+		)
+	}
+
+	f := filter{
+		key:   strings.ToLower(strings.TrimSpace(cond[:at])),
+		op:    op,
+		value: cond[at+len(op):],
+	}
+	if op == "~" || op == "!~" {
+		re, err := regexp.Compile(f.value)
+		if err != nil {
+			return filter{}, fmt.Errorf("invalid filter %q: %w", cond, err)
+		}
+		f.re = re
+	}
+
+	return f, nil
+}
+
+// bind resolves the filter's column and returns a predicate over rows.
+func (f *filter) bind(columns []Column) (func(any) bool, error) {
+	col, err := findColumn(columns, f.key)
+	if err != nil {
+		return nil, fmt.Errorf("filter: %w", err)
+	}
+
+	return func(row any) bool {
+		cell := stripAnsi(col.Extract(row))
+		switch f.op {
+		case "=":
+			return matchesValue(cell, f.value)
+		case "!=":
+			return !matchesValue(cell, f.value)
+		case "~":
+			return f.re.MatchString(cell)
+		default:
+			return !f.re.MatchString(cell)
+		}
+	}, nil
+}
+
+// matchesValue reports whether cell is value or, for list cells such as
+// "admin, dev", contains it as an item.
+func matchesValue(cell, value string) bool {
+	return cell == value || slices.Contains(strings.Split(cell, ", "), value)
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/itchyny/gojq"
+)
+
+// jqPrinter filters the JSON form of rows through a jq expression. String
+// results are printed raw, one per line, so they can be used in scripts
+// directly; other results are printed as JSON.
+type jqPrinter struct {
+	query *gojq.Query
+	w     io.Writer
+}
+
+// parseJQ compiles a --jq expression.
+func parseJQ(expr string) (*gojq.Query, error) {
+	q, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jq expression: %w", err)
+	}
+
+	return q, nil
+}
+
+func (p *jqPrinter) Print(rows any) error {
+	input, err := toJSONValue(rows)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	iter := p.query.Run(input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		switch v := v.(type) {
+		case error:
+			return fmt.Errorf("jq: %w", v)
+		case string:
+			if _, err := fmt.Fprintln(p.w, v); err != nil {
+				return err
+			}
+		default:
+			if err := enc.Encode(v); err != nil {
+				return fmt.Errorf("encode json: %w", err)
+			}
+		}
+	}
+}
+
+// toJSONValue converts rows to the plain maps, slices, and scalars its JSON
+// encoding decodes to, the form filters operate on.
+func toJSONValue(rows any) (any, error) {
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("encode json: %w", err)
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("decode json: %w", err)
+	}
+
+	return v, nil
+}
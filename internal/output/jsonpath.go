@@ -0,0 +1,463 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// jsonPathFieldDef makes field lookups fail on missing keys, as kubectl
+// does, instead of yielding null.
+const jsonPathFieldDef = `def field($k): if type == "object" and has($k) ` +
+	`then .[$k] else error("\($k) is not found") end; `
+
+// jsonPathPrinter renders rows through a kubectl-style JSONPath template.
+// Lists are wrapped in an object under "items", as kubectl does, so
+// templates such as '{.items[*].Name}' carry over unchanged.
+type jsonPathPrinter struct {
+	path []jsonPathNode
+	w    io.Writer
+}
+
+// jsonPathNode is a part of a JSONPath template: literal text, an
+// expression whose results are printed, or a range whose body is printed
+// once for each result of its expression.
+type jsonPathNode struct {
+	text  string
+	query *gojq.Code
+	// body is set for {range} nodes.
+	body []jsonPathNode
+	loop bool
+}
+
+// jsonPathParser splits a template into nodes.
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+// parseJSONPath compiles a --jsonpath template. Expressions support field
+// and quoted-key access, [*], indexes, unions, slices, recursive descent
+// (..), and filters such as [?(@.Name=="x")]; {range ...}{end} and quoted
+// literals such as {"\n"} shape the output. Expressions are evaluated with
+// jq.
+func parseJSONPath(template string) ([]jsonPathNode, error) {
+	p := &jsonPathParser{s: template}
+	nodes, err := p.nodes(false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --jsonpath template: %w", err)
+	}
+
+	return nodes, nil
+}
+
+func (p *jsonPathPrinter) Print(rows any) error {
+	input, err := toJSONValue(rows)
+	if err != nil {
+		return err
+	}
+	if reflect.ValueOf(rows).Kind() == reflect.Slice {
+		input = map[string]any{"items": input}
+	}
+	if err := p.exec(p.path, input); err != nil {
+		return fmt.Errorf("jsonpath: %w", err)
+	}
+	_, err = fmt.Fprintln(p.w)
+
+	return err
+}
+
+// exec writes nodes evaluated against input.
+func (p *jsonPathPrinter) exec(nodes []jsonPathNode, input any) error {
+	for _, n := range nodes {
+		if n.query == nil {
+			if _, err := io.WriteString(p.w, n.text); err != nil {
+				return err
+			}
+
+			continue
+		}
+		results, err := run(n.query, input)
+		if err != nil {
+			return err
+		}
+		if n.loop {
+			for _, v := range results {
+				if err := p.exec(n.body, v); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+		// Several results of one expression are separated by spaces.
+		out := make([]string, len(results))
+		for i, v := range results {
+			if out[i], err = formatJSONPathValue(v); err != nil {
+				return err
+			}
+		}
+		if _, err := io.WriteString(p.w, strings.Join(out, " ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nodes parses nodes up to the end of the template, or up to {end} when
+// inRange is set.
+func (p *jsonPathParser) nodes(inRange bool) ([]jsonPathNode, error) {
+	var nodes []jsonPathNode
+	for p.pos < len(p.s) {
+		i := strings.IndexByte(p.s[p.pos:], '{')
+		if i < 0 {
+			nodes = append(nodes, jsonPathNode{text: p.s[p.pos:]})
+			p.pos = len(p.s)
+
+			break
+		}
+		if i > 0 {
+			nodes = append(nodes, jsonPathNode{text: p.s[p.pos : p.pos+i]})
+		}
+		p.pos += i + 1
+		action, err := p.action()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case action == "end":
+			if !inRange {
+				return nil, errors.New("{end} without {range}")
+			}
+
+			return nodes, nil
+		case strings.HasPrefix(action, "range "):
+			q, err := compileJSONPath(strings.TrimPrefix(action, "range "))
+			if err != nil {
+				return nil, err
+			}
+			body, err := p.nodes(true)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, jsonPathNode{query: q, body: body, loop: true})
+		case strings.HasPrefix(action, `"`) || strings.HasPrefix(action, "'"):
+			s, err := unquoteJSONPath(action)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, jsonPathNode{text: s})
+		default:
+			q, err := compileJSONPath(action)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, jsonPathNode{query: q})
+		}
+	}
+	if inRange {
+		return nil, errors.New("{range} without {end}")
+	}
+
+	return nodes, nil
+}
+
+// action returns the content of the action starting at the current
+// position, up to the closing brace outside quotes.
+func (p *jsonPathParser) action() (string, error) {
+	start := p.pos
+	var quote byte
+	for ; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		switch {
+		case quote != 0:
+			switch c {
+			case '\\':
+				p.pos++
+			case quote:
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			action := strings.TrimSpace(p.s[start:p.pos])
+			p.pos++
+
+			return action, nil
+		}
+	}
+
+	return "", fmt.Errorf("unclosed action %q", p.s[start-1:])
+}
+
+// compileJSONPath compiles a JSONPath expression into a jq program.
+func compileJSONPath(expr string) (*gojq.Code, error) {
+	jq, err := translateJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	q, err := gojq.Parse(jsonPathFieldDef + jq)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", expr, err)
+	}
+	code, err := gojq.Compile(q)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", expr, err)
+	}
+
+	return code, nil
+}
+
+// translateJSONPath returns the jq expression for a JSONPath expression
+// relative to the root ($) or the current range element (@).
+func translateJSONPath(expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), "@")
+	var b strings.Builder
+	b.WriteString(".")
+	for i := 0; i < len(expr); {
+		switch {
+		case strings.HasPrefix(expr[i:], ".."):
+			i += 2
+			name, n := fieldName(expr[i:])
+			if n == 0 || name == "*" {
+				return "", fmt.Errorf("%s: want a field name after \"..\"", expr)
+			}
+			i += n
+			k := strconv.Quote(name)
+			fmt.Fprintf(&b, " | (.. | objects | select(has(%s)) | .[%s])", k, k)
+		case expr[i] == '.':
+			i++
+			name, n := fieldName(expr[i:])
+			if n == 0 {
+				continue
+			}
+			i += n
+			if name == "*" {
+				b.WriteString(" | .[]")
+			} else {
+				fmt.Fprintf(&b, " | field(%s)", strconv.Quote(name))
+			}
+		case expr[i] == '[':
+			end := closingBracket(expr, i)
+			if end < 0 {
+				return "", fmt.Errorf("%s: unclosed [", expr)
+			}
+			sub, err := translateSubscript(expr[i+1 : end])
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", expr, err)
+			}
+			b.WriteString(" | " + sub)
+			i = end + 1
+		default:
+			return "", fmt.Errorf("%s: unexpected %q", expr, expr[i:])
+		}
+	}
+
+	return b.String(), nil
+}
+
+// translateSubscript returns the jq expression for the content of a
+// [...] subscript.
+func translateSubscript(sub string) (string, error) {
+	sub = strings.TrimSpace(sub)
+	switch {
+	case sub == "*":
+		return ".[]", nil
+	case strings.HasPrefix(sub, "?(") && strings.HasSuffix(sub, ")"):
+		cond, err := translateFilter(sub[2 : len(sub)-1])
+		if err != nil {
+			return "", err
+		}
+
+		return ".[] | select((" + cond + ")?)", nil
+	case strings.Contains(sub, ":"):
+		bounds := strings.Split(sub, ":")
+		if len(bounds) > 2 {
+			return "", fmt.Errorf("slice steps are not supported: [%s]", sub)
+		}
+		for _, s := range bounds {
+			if _, err := strconv.Atoi(strings.TrimSpace(s)); s != "" && err != nil {
+				return "", fmt.Errorf("invalid slice [%s]", sub)
+			}
+		}
+
+		return ".[" + sub + "] | .[]", nil
+	}
+
+	keys := strings.Split(sub, ",")
+	for i, k := range keys {
+		k = strings.TrimSpace(k)
+		if strings.HasPrefix(k, "'") || strings.HasPrefix(k, `"`) {
+			s, err := unquoteJSONPath(k)
+			if err != nil {
+				return "", err
+			}
+			keys[i] = "field(" + strconv.Quote(s) + ")"
+
+			continue
+		}
+		if _, err := strconv.Atoi(k); err != nil {
+			return "", fmt.Errorf("invalid subscript [%s]", sub)
+		}
+		keys[i] = ".[" + k + "]"
+	}
+
+	return "(" + strings.Join(keys, ", ") + ")", nil
+}
+
+// translateFilter returns the jq condition for a filter expression such as
+// @.Name == 'x' && @.Pulls > 3.
+func translateFilter(f string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(f); {
+		c := f[i]
+		switch {
+		case c == ' ':
+			b.WriteByte(c)
+			i++
+		case c == '@':
+			j := i + 1
+			for j < len(f) && !strings.ContainsRune(" =!<>&|()", rune(f[j])) {
+				j++
+			}
+			path, err := translateJSONPath(f[i:j])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString("(" + path + ")")
+			i = j
+		case c == '"' || c == '\'':
+			j := strings.IndexByte(f[i+1:], c)
+			if j < 0 {
+				return "", fmt.Errorf("unclosed string in filter %q", f)
+			}
+			s, err := unquoteJSONPath(f[i : i+j+2])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(strconv.Quote(s))
+			i += j + 2
+		case strings.HasPrefix(f[i:], "&&"):
+			b.WriteString(" and ")
+			i += 2
+		case strings.HasPrefix(f[i:], "||"):
+			b.WriteString(" or ")
+			i += 2
+		case strings.HasPrefix(f[i:], "=="), strings.HasPrefix(f[i:], "!="),
+			strings.HasPrefix(f[i:], "<="), strings.HasPrefix(f[i:], ">="):
+			b.WriteString(" " + f[i:i+2] + " ")
+			i += 2
+		case strings.ContainsRune("<>()", rune(c)):
+			b.WriteString(" " + string(c) + " ")
+			i++
+		default:
+			j := i
+			for j < len(f) && !strings.ContainsRune(" =!<>&|()", rune(f[j])) {
+				j++
+			}
+			word := f[i:j]
+			_, err := strconv.ParseFloat(word, 64)
+			if err != nil && word != "true" && word != "false" && word != "null" {
+				return "", fmt.Errorf("unexpected %q in filter %q", word, f)
+			}
+			b.WriteString(word)
+			i = j
+		}
+	}
+
+	return b.String(), nil
+}
+
+// fieldName returns the field name at the start of s and its length.
+func fieldName(s string) (name string, n int) {
+	n = strings.IndexAny(s, ".[")
+	if n < 0 {
+		n = len(s)
+	}
+
+	return s[:n], n
+}
+
+// closingBracket returns the index of the bracket closing the one at
+// start, skipping quoted strings, or -1.
+func closingBracket(s string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// unquoteJSONPath unquotes a single- or double-quoted string.
+func unquoteJSONPath(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	u, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+
+	return u, nil
+}
+
+// run returns all results of code for input.
+func run(code *gojq.Code, input any) ([]any, error) {
+	var results []any
+	iter := code.Run(input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return results, nil
+		}
+		if err, ok := v.(error); ok {
+			// Report errors raised by field without jq's "error: " prefix.
+			var ve gojq.ValueError
+			if errors.As(err, &ve) {
+				return nil, fmt.Errorf("%v", ve.Value())
+			}
+
+			return nil, err
+		}
+		results = append(results, v)
+	}
+}
+
+// formatJSONPathValue prints strings raw and other values as JSON.
+func formatJSONPathValue(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encode json: %w", err)
+	}
+
+	return string(b), nil
+}
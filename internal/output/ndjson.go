@@ -0,0 +1,46 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ExportNDJSON writes rows to path as newline-delimited JSON, one object per
+// line. Lines are buffered and the file only replaces path once it is
+// complete, so a failed export leaves no partial file behind.
+func ExportNDJSON[T any](path string, rows []T) error {
+	tmp, err := os.CreateTemp(
+		filepath.Dir(path),
+		"."+filepath.Base(path)+".*.tmp",
+	)
+	if err != nil {
+		return fmt.Errorf("create export file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for i := range rows {
+		if err := enc.Encode(rows[i]); err != nil {
+			_ = tmp.Close()
+
+			return fmt.Errorf("encode json: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write export file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write export file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write export file: %w", err)
+	}
+
+	return nil
+}
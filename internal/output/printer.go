@@ -1,6 +1,14 @@
 package output
 
-import "io"
+import (
+	"cli/internal/config"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
 
 // Format represents the output rendering format.
 type Format int
@@ -9,8 +17,13 @@ const (
 	FormatTable Format = iota
 	FormatJSON
 	FormatYAML
+	// FormatWide is a table including the columns hidden by default.
+	FormatWide
 )
 
+// FormatNames lists the accepted output format names.
+var FormatNames = []string{"table", "wide", "json", "yaml"}
+
 // ParseFormat converts a string to a Format. Defaults to FormatTable.
 func ParseFormat(s string) Format {
 	switch s {
@@ -18,6 +31,8 @@ func ParseFormat(s string) Format {
 		return FormatJSON
 	case "yaml":
 		return FormatYAML
+	case "wide":
+		return FormatWide
 	default:
 		return FormatTable
 	}
@@ -29,6 +44,18 @@ type Column struct {
 	Extract func(row any) string
 	// MinWidth is the minimum column width. 0 means use header length.
 	MinWidth int
+	// Wide columns are only shown with -o wide or when selected explicitly
+	// with --columns.
+	Wide bool
+	// SortKey, if set, orders rows for --sort instead of the rendered cell,
+	// e.g. for timestamps shown relative to now.
+	SortKey func(row any) string
+}
+
+// Key returns the name used to select the column with --columns, e.g.
+// "display-name" for the "DISPLAY NAME" column.
+func (c Column) Key() string {
+	return strings.ToLower(strings.ReplaceAll(c.Header, " ", "-"))
 }
 
 // Printer renders resource slices to an io.Writer.
@@ -43,9 +70,119 @@ func New(format Format, columns []Column, w io.Writer) Printer {
 		return &jsonPrinter{w: w}
 	case FormatYAML:
 		return &yamlPrinter{w: w}
+	case FormatWide:
+		return &tablePrinter{columns: columns, w: w, wide: true}
 	case FormatTable:
 		return &tablePrinter{columns: columns, w: w}
 	default:
 		return &tablePrinter{columns: columns, w: w}
 	}
 }
+
+// FromConfig returns the Printer selected by the output settings in cfg. A
+// --jq expression or --jsonpath template takes precedence over the output
+// format.
+func FromConfig(cfg *config.Config, columns []Column, w io.Writer) Printer {
+	display.utc = cfg.UTC
+	display.relative = cfg.RelativeTime
+	display.raw = cfg.Raw
+	if os.Getenv(FanOutEnv) != "" {
+		table := &tablePrinter{
+			columns:  columns,
+			wide:     ParseFormat(cfg.Output) == FormatWide,
+			selected: cfg.Columns,
+		}
+
+		return arranged(&fanOutPrinter{table: table, w: w}, cfg, columns)
+	}
+	if cfg.JQ != "" {
+		// CheckFilters has rejected invalid expressions during setup.
+		if q, err := parseJQ(cfg.JQ); err == nil {
+			return arranged(&jqPrinter{query: q, w: w}, cfg, columns)
+		}
+	}
+	if cfg.JSONPath != "" {
+		if jp, err := parseJSONPath(cfg.JSONPath); err == nil {
+			return arranged(&jsonPathPrinter{path: jp, w: w}, cfg, columns)
+		}
+	}
+	p := New(ParseFormat(cfg.Output), columns, w)
+	if _, ok := p.(*tablePrinter); ok && cfg.AllFields {
+		return arranged(&fieldsPrinter{w: w}, cfg, columns)
+	}
+	if t, ok := p.(*tablePrinter); ok {
+		t.selected = cfg.Columns
+		t.noHeaders = cfg.NoHeaders
+		t.style = cfg.TableStyle
+		if t.style == TableStylePlain {
+			t.style = TableStyleMinimal
+		}
+	}
+
+	return arranged(p, cfg, columns)
+}
+
+// Uses reports whether the output selected by cfg shows, filters, or sorts
+// by the column with key, so commands can skip looking up data only that
+// column needs. JSON, YAML, --jq, --jsonpath, and --all-fields output only
+// carry the fields of the rows themselves.
+func Uses(cfg *config.Config, columns []Column, key string) bool {
+	if sortKey, _, _ := strings.Cut(
+		strings.ToLower(strings.TrimSpace(cfg.Sort)),
+		":",
+	); sortKey == key {
+		return true
+	}
+	if filters, err := parseFilters(cfg.Filter); err == nil &&
+		slices.ContainsFunc(filters, func(f filter) bool { return f.key == key }) {
+		return true
+	}
+
+	format := ParseFormat(cfg.Output)
+	if os.Getenv(FanOutEnv) == "" {
+		if cfg.JQ != "" || cfg.JSONPath != "" || cfg.AllFields ||
+			format == FormatJSON || format == FormatYAML {
+			return false
+		}
+	}
+	if len(cfg.Columns) > 0 {
+		return slices.ContainsFunc(cfg.Columns, func(c string) bool {
+			return strings.ToLower(strings.TrimSpace(c)) == key
+		})
+	}
+	col, err := findColumn(columns, key)
+
+	return err == nil && (!col.Wide || format == FormatWide)
+}
+
+// CheckFilters validates the output filter expressions and the table style
+// in cfg, so mistakes are reported before any request is made.
+func CheckFilters(cfg *config.Config) error {
+	if cfg.TableStyle != "" && !slices.Contains(TableStyles, cfg.TableStyle) {
+		return fmt.Errorf(
+			"unknown table style %q (available: %s)",
+			cfg.TableStyle,
+			strings.Join(TableStyles, ", "),
+		)
+	}
+	if cfg.JQ != "" {
+		if _, err := parseJQ(cfg.JQ); err != nil {
+			return err
+		}
+	}
+	if cfg.Filter != "" {
+		if _, err := parseFilters(cfg.Filter); err != nil {
+			return err
+		}
+	}
+	if cfg.JSONPath != "" {
+		if cfg.JQ != "" {
+			return errors.New("--jq and --jsonpath are mutually exclusive")
+		}
+		if _, err := parseJSONPath(cfg.JSONPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
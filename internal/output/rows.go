@@ -0,0 +1,82 @@
+package output
+
+import (
+	"cli/internal/config"
+	"slices"
+)
+
+// rowsPrinter applies --filter and --sort to rows before handing them to the
+// underlying printer, so every format sees the same rows in the same order.
+type rowsPrinter struct {
+	Printer
+
+	columns []Column
+	// filter is the --filter value, see parseFilters.
+	filter string
+	// sort is the --sort value, see parseSort.
+	sort string
+}
+
+// arranged wraps p to filter and order rows as requested in cfg.
+func arranged(p Printer, cfg *config.Config, columns []Column) Printer {
+	if cfg.Filter == "" && cfg.Sort == "" {
+		return p
+	}
+
+	return &rowsPrinter{
+		Printer: p,
+		columns: columns,
+		filter:  cfg.Filter,
+		sort:    cfg.Sort,
+	}
+}
+
+func (p *rowsPrinter) Print(rows any) error {
+	items := toSlice(rows)
+	if items == nil {
+		return p.Printer.Print(rows)
+	}
+
+	if p.filter != "" {
+		filters, err := parseFilters(p.filter)
+		if err != nil {
+			return err
+		}
+		matchers := make([]func(any) bool, len(filters))
+		for i, f := range filters {
+			if matchers[i], err = f.bind(p.columns); err != nil {
+				return err
+			}
+		}
+		items = slices.DeleteFunc(items, func(row any) bool {
+			for _, match := range matchers {
+				if !match(row) {
+					return true
+				}
+			}
+
+			return false
+		})
+	}
+
+	if p.sort != "" {
+		col, desc, err := parseSort(p.sort, p.columns)
+		if err != nil {
+			return err
+		}
+		key := col.SortKey
+		if key == nil {
+			key = func(row any) string { return stripAnsi(col.Extract(row)) }
+		}
+		slices.SortStableFunc(items, func(a, b any) int {
+			c := compareCells(key(a), key(b))
+			if desc {
+				return -c
+			}
+
+			return c
+		})
+	}
+
+	return p.Printer.Print(items)
+}
@@ -0,0 +1,25 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// FormatSize renders a byte count in binary units, e.g. "1.5 MiB", or as the
+// exact number of bytes when raw is set.
+func FormatSize(n int64, raw bool) string {
+	if raw {
+		return strconv.FormatInt(n, 10)
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseSort resolves a --sort value against columns.
+func parseSort(spec string, columns []Column) (Column, bool, error) {
+	key, dir, _ := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	var desc bool
+	switch dir {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return Column{}, false, fmt.Errorf(
+			"invalid sort direction %q (use asc or desc)",
+			dir,
+		)
+	}
+
+	col, err := findColumn(columns, key)
+	if err != nil {
+		return Column{}, false, fmt.Errorf("sort: %w", err)
+	}
+
+	return col, desc, nil
+}
+
+// findColumn returns the column selected by key.
+func findColumn(columns []Column, key string) (Column, error) {
+	i := slices.IndexFunc(columns, func(c Column) bool {
+		return c.Key() == key
+	})
+	if i < 0 {
+		keys := make([]string, len(columns))
+		for j, col := range columns {
+			keys[j] = col.Key()
+		}
+
+		return Column{}, fmt.Errorf(
+			"unknown column %q (available: %s)",
+			key,
+			strings.Join(keys, ", "),
+		)
+	}
+
+	return columns[i], nil
+}
+
+// compareCells orders two cell values numerically when both are numbers or
+// durations, and as case-insensitive text otherwise. Empty cells sort first.
+func compareCells(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			return cmp.Compare(x, y)
+		}
+	}
+	if x, err := time.ParseDuration(a); err == nil {
+		if y, err := time.ParseDuration(b); err == nil {
+			return cmp.Compare(x, y)
+		}
+	}
+
+	return cmp.Or(
+		cmp.Compare(strings.ToLower(a), strings.ToLower(b)),
+		cmp.Compare(a, b),
+	)
+}
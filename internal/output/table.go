@@ -1,31 +1,78 @@
 package output
 
 import (
+	"cli/internal/i18n"
 	"cli/internal/styles"
 	"fmt"
 	"io"
+	"os"
 	"reflect"
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
 )
 
 type tablePrinter struct {
 	columns []Column
 	w       io.Writer
+	// wide includes columns marked Wide.
+	wide bool
+	// selected lists column keys to show, in order. Empty means the default
+	// set.
+	selected []string
+	// noHeaders omits the header row.
+	noHeaders bool
+	// style is the table_style setting, see TableStyles.
+	style string
+}
+
+// Table styles selectable with table_style.
+const (
+	// TableStyleDefault aligns columns with spaces under a highlighted
+	// header.
+	TableStyleDefault = "default"
+	// TableStyleMinimal is TableStyleDefault without colors.
+	TableStyleMinimal = "minimal"
+	// TableStylePlain is an alias of TableStyleMinimal.
+	TableStylePlain = "plain"
+	// TableStyleRounded draws borders with rounded corners.
+	TableStyleRounded = "rounded"
+	// TableStyleMarkdown renders a Markdown pipe table.
+	TableStyleMarkdown = "markdown"
+	// TableStyleGitHub is TableStyleMarkdown with numeric columns
+	// right-aligned, as supported by GitHub Flavored Markdown.
+	TableStyleGitHub = "github"
+)
+
+// TableStyles lists the values accepted for table_style.
+var TableStyles = []string{
+	TableStyleDefault,
+	TableStyleMinimal,
+	TableStylePlain,
+	TableStyleRounded,
+	TableStyleMarkdown,
+	TableStyleGitHub,
 }
 
 func (p *tablePrinter) Print(rows any) error {
+	columns, err := p.visibleColumns()
+	if err != nil {
+		return err
+	}
+
 	items := toSlice(rows)
 	if len(items) == 0 {
-		_, err := fmt.Fprintln(p.w, styles.MutedStyle.Render("No results."))
+		_, err := fmt.Fprintln(p.w, styles.MutedStyle.Render(i18n.T("No results.")))
 
 		return err
 	}
 
 	// Compute column widths: max of header length, MinWidth, and all cell values.
-	widths := make([]int, len(p.columns))
+	widths := make([]int, len(columns))
 	cells := make([][]string, len(items))
 
-	for i, col := range p.columns {
+	for i, col := range columns {
 		w := len(col.Header)
 		if col.MinWidth > w {
 			w = col.MinWidth
@@ -34,40 +81,51 @@ func (p *tablePrinter) Print(rows any) error {
 	}
 
 	for r, row := range items {
-		cells[r] = make([]string, len(p.columns))
-		for c, col := range p.columns {
+		cells[r] = make([]string, len(columns))
+		for c, col := range columns {
 			val := col.Extract(row)
-			// Strip ANSI for width calculation.
-			plain := stripAnsi(val)
-			if len(plain) > widths[c] {
-				widths[c] = len(plain)
+			if p.style != TableStyleDefault && p.style != "" &&
+				p.style != TableStyleRounded {
+				val = stripAnsi(val)
 			}
+			// Strip ANSI for width calculation.
+			widths[c] = max(widths[c], displayWidth(val))
 			cells[r][c] = val
 		}
 	}
 
-	// Render header.
-	headerCells := make([]string, len(p.columns))
-	for i, col := range p.columns {
-		padded := pad(col.Header, widths[i])
-		headerCells[i] = styles.HeaderStyle.Render(padded)
+	switch p.style {
+	case TableStyleMarkdown, TableStyleGitHub:
+		return p.printMarkdown(columns, cells)
+	case TableStyleRounded:
+		return p.printRounded(columns, widths, cells)
 	}
-	if _, err := fmt.Fprintln(p.w, strings.Join(headerCells, "")); err != nil {
-		return err
+
+	// Fit the table to the terminal. Wide output is never truncated so the
+	// full values stay available.
+	if maxWidth := terminalWidth(p.w); maxWidth > 0 && !p.wide {
+		fitWidths(widths, columns, maxWidth)
+		for _, row := range cells {
+			for i := range row {
+				row[i] = truncate(row[i], widths[i])
+			}
+		}
+	}
+
+	if !p.noHeaders {
+		if err := p.printHeader(columns, widths); err != nil {
+			return err
+		}
 	}
 
 	// Render rows.
 	for _, row := range cells {
-		rowCells := make([]string, len(p.columns))
+		rowCells := make([]string, len(columns))
 		for i, cell := range row {
-			plain := stripAnsi(cell)
 			// Pad with plain spaces so the column aligns, then wrap with
 			// a single-space margin on each side (no lipgloss padding, which
 			// would mis-count width when cell already contains ANSI codes).
-			padding := widths[i] - len([]rune(plain))
-			if padding < 0 {
-				padding = 0
-			}
+			padding := max(widths[i]-displayWidth(cell), 0)
 			rowCells[i] = " " + cell + strings.Repeat(" ", padding) + " "
 		}
 		if _, err := fmt.Fprintln(p.w, strings.Join(rowCells, "")); err != nil {
@@ -78,6 +136,135 @@ func (p *tablePrinter) Print(rows any) error {
 	return nil
 }
 
+// printHeader renders the header row.
+func (p *tablePrinter) printHeader(columns []Column, widths []int) error {
+	headerCells := make([]string, len(columns))
+	for i, col := range columns {
+		padded := pad(col.Header, widths[i])
+		if p.style == TableStyleMinimal {
+			headerCells[i] = " " + padded + " "
+		} else {
+			headerCells[i] = styles.HeaderStyle.Render(padded)
+		}
+	}
+	_, err := fmt.Fprintln(p.w, strings.Join(headerCells, ""))
+
+	return err
+}
+
+// visibleColumns resolves the columns to render.
+func (p *tablePrinter) visibleColumns() ([]Column, error) {
+	if len(p.selected) == 0 {
+		cols := make([]Column, 0, len(p.columns))
+		for _, col := range p.columns {
+			if !col.Wide || p.wide {
+				cols = append(cols, col)
+			}
+		}
+
+		return cols, nil
+	}
+
+	cols := make([]Column, 0, len(p.selected))
+	for _, key := range p.selected {
+		col, err := findColumn(
+			p.columns,
+			strings.ToLower(strings.TrimSpace(key)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+
+	return cols, nil
+}
+
+// minTruncatedWidth is the narrowest a column is shrunk to when fitting the
+// table to the terminal.
+const minTruncatedWidth = 8
+
+// terminalWidth returns the width of the terminal w writes to, or 0 if w is
+// not a terminal.
+func terminalWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+	fd := int(f.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if !term.IsTerminal(fd) {
+		return 0
+	}
+	width, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// fitWidths narrows the widest columns until the rendered table, including
+// the one-space margin around every cell, fits in maxWidth. Columns are never
+// narrowed below their header, MinWidth, or minTruncatedWidth.
+func fitWidths(widths []int, columns []Column, maxWidth int) {
+	floors := make([]int, len(widths))
+	total := 0
+	for i, col := range columns {
+		floors[i] = min(
+			max(len(col.Header), col.MinWidth, minTruncatedWidth),
+			widths[i],
+		)
+		total += widths[i] + 2
+	}
+
+	for total > maxWidth {
+		widest := -1
+		for i := range widths {
+			if widths[i] > floors[i] &&
+				(widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// truncate shortens cell to width, marking the cut with an ellipsis.
+// Comma-separated lists are cut between items and report how many were
+// dropped, e.g. "latest, v1 +3". Styled cells are left alone.
+func truncate(cell string, width int) string {
+	if displayWidth(cell) <= width || stripAnsi(cell) != cell {
+		return cell
+	}
+
+	if items := strings.Split(cell, ", "); len(items) > 1 {
+		for n := len(items) - 1; n > 0; n-- {
+			s := fmt.Sprintf(
+				"%s +%d",
+				strings.Join(items[:n], ", "),
+				len(items)-n,
+			)
+			if displayWidth(s) <= width {
+				return s
+			}
+		}
+	}
+
+	runes := []rune(cell)
+
+	return string(runes[:max(width-1, 0)]) + "…"
+}
+
+// displayWidth returns the number of terminal cells s occupies, ignoring
+// ANSI escape sequences.
+func displayWidth(s string) int {
+	return utf8.RuneCountInString(stripAnsi(s))
+}
+
 // toSlice converts any slice value to []any using reflection.
 func toSlice(v any) []any {
 	rv := reflect.ValueOf(v)
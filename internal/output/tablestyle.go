@@ -0,0 +1,151 @@
+package output
+
+import (
+	"cli/internal/styles"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// printMarkdown renders cells as a Markdown pipe table. Markdown tables
+// need a header row, so it is printed even with --no-headers, and cells
+// are never truncated.
+func (p *tablePrinter) printMarkdown(columns []Column, cells [][]string) error {
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		widths[i] = max(len(col.Header), 3)
+	}
+	for _, row := range cells {
+		for i := range row {
+			row[i] = strings.ReplaceAll(row[i], "|", `\|`)
+			widths[i] = max(widths[i], displayWidth(row[i]))
+		}
+	}
+
+	header := make([]string, len(columns))
+	rule := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = pad(col.Header, widths[i])
+		rule[i] = strings.Repeat("-", widths[i])
+		if p.style == TableStyleGitHub && numeric(cells, i) {
+			rule[i] = rule[i][1:] + ":"
+		}
+	}
+	if err := p.markdownRow(header); err != nil {
+		return err
+	}
+	if err := p.markdownRow(rule); err != nil {
+		return err
+	}
+	for _, row := range cells {
+		padded := make([]string, len(row))
+		for i, cell := range row {
+			padded[i] = cell + strings.Repeat(
+				" ",
+				max(widths[i]-displayWidth(cell), 0),
+			)
+		}
+		if err := p.markdownRow(padded); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printRounded renders cells inside borders with rounded corners, fitted to
+// the terminal unless the output is wide.
+func (p *tablePrinter) printRounded(
+	columns []Column,
+	widths []int,
+	cells [][]string,
+) error {
+	// Every column takes one more cell for its left border, and the table
+	// one for the closing border.
+	if maxWidth := terminalWidth(p.w); maxWidth > 0 && !p.wide {
+		fitWidths(widths, columns, maxWidth-len(columns)-1)
+		for _, row := range cells {
+			for i := range row {
+				row[i] = truncate(row[i], widths[i])
+			}
+		}
+	}
+
+	// Under --plain the box-drawing characters are ASCII, and so are the
+	// corners.
+	corners := [3][3]string{{"╭", "┬", "╮"}, {"├", "┼", "┤"}, {"╰", "┴", "╯"}}
+	if styles.Plain {
+		corners = [3][3]string{{"+", "+", "+"}, {"+", "+", "+"}, {"+", "+", "+"}}
+	}
+	line := func(c [3]string) error {
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			parts[i] = strings.Repeat(styles.BoxHorizontal, w+2)
+		}
+		_, err := fmt.Fprintln(p.w, c[0]+strings.Join(parts, c[1])+c[2])
+
+		return err
+	}
+	row := func(values []string) error {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = " " + v + strings.Repeat(
+				" ",
+				max(widths[i]-displayWidth(v), 0),
+			) + " "
+		}
+		_, err := fmt.Fprintln(
+			p.w,
+			styles.BoxVertical+strings.Join(parts, styles.BoxVertical)+
+				styles.BoxVertical,
+		)
+
+		return err
+	}
+
+	if err := line(corners[0]); err != nil {
+		return err
+	}
+	if !p.noHeaders {
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = col.Header
+		}
+		if err := row(header); err != nil {
+			return err
+		}
+		if err := line(corners[1]); err != nil {
+			return err
+		}
+	}
+	for _, values := range cells {
+		if err := row(values); err != nil {
+			return err
+		}
+	}
+
+	return line(corners[2])
+}
+
+// markdownRow writes values as a row of a Markdown pipe table.
+func (p *tablePrinter) markdownRow(values []string) error {
+	_, err := fmt.Fprintln(p.w, "| "+strings.Join(values, " | ")+" |")
+
+	return err
+}
+
+// numeric reports whether every non-empty cell of column i is a number.
+func numeric(cells [][]string, i int) bool {
+	found := false
+	for _, row := range cells {
+		if row[i] == "" || row[i] == "-" {
+			continue
+		}
+		if _, err := strconv.ParseFloat(row[i], 64); err != nil {
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
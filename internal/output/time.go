@@ -0,0 +1,88 @@
+package output
+
+import (
+	"fmt"
+	"time"
+)
+
+// display controls how table columns render timestamps and sizes.
+// FromConfig sets it from --utc, --relative-time, and --raw.
+var display struct {
+	utc      bool
+	relative bool
+	raw      bool
+}
+
+// now is the reference point for relative timestamps.
+var now = time.Now
+
+// localTime formats t with layout in the local timezone, or UTC with --utc.
+// Zero times render as "-".
+func localTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if display.utc {
+		return t.UTC().Format(layout)
+	}
+
+	return t.Local().Format(layout)
+}
+
+// timestamp is like localTime, but renders t relative to now, e.g.
+// "3 days ago", when --relative-time is set.
+func timestamp(t time.Time, layout string) string {
+	if t.IsZero() || !display.relative {
+		return localTime(t, layout)
+	}
+
+	return relativeTime(t, now())
+}
+
+// sortTime returns a sort key for t that orders chronologically regardless
+// of how the column renders it.
+func sortTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// relativeTime describes t as a span from ref, rounded down to the largest
+// whole unit.
+func relativeTime(t, ref time.Time) string {
+	d := ref.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+
+	const day = 24 * time.Hour
+	var n int64
+	var unit string
+	switch {
+	case d < time.Hour:
+		n, unit = int64(d/time.Minute), "minute"
+	case d < day:
+		n, unit = int64(d/time.Hour), "hour"
+	case d < 30*day:
+		n, unit = int64(d/day), "day"
+	case d < 365*day:
+		n, unit = int64(d/(30*day)), "month"
+	default:
+		n, unit = int64(d/(365*day)), "year"
+	}
+	span := fmt.Sprintf("%d %s", n, unit)
+	if n != 1 {
+		span += "s"
+	}
+	if future {
+		return "in " + span
+	}
+
+	return span + " ago"
+}
@@ -0,0 +1,69 @@
+// Package parallel runs bulk API operations with bounded concurrency.
+package parallel
+
+import (
+	"cli/internal/client"
+	"cli/internal/progress"
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultJobs is the concurrency used when -j/--parallel is not given.
+const defaultJobs = 4
+
+// AddFlag registers -j/--parallel on cmd.
+func AddFlag(cmd *cobra.Command) {
+	cmd.Flags().IntP(
+		"parallel",
+		"j",
+		defaultJobs,
+		"Number of API calls to run concurrently",
+	)
+}
+
+// Map calls fn for every item, running up to the -j/--parallel count of
+// calls at once, and reports progress as "title done/total". Results are
+// returned in the order of items; only successful calls contribute one.
+// Failures do not stop the remaining items and are returned joined.
+func Map[T, R any](
+	cmd *cobra.Command,
+	title string,
+	items []T,
+	fn func(ctx context.Context, item T) (R, error),
+) ([]R, error) {
+	jobs, _ := cmd.Flags().GetInt("parallel")
+	jobs = max(jobs, 1)
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	counter := progress.NewCounter(title, len(items))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			// Each call keeps the request ID of its own failure.
+			ctx := client.WithRequestID(cmd.Context())
+			results[i], errs[i] = fn(ctx, item)
+			errs[i] = client.WrapRequestID(ctx, errs[i])
+			counter.Inc()
+		}()
+	}
+	wg.Wait()
+	counter.Done()
+
+	ok := make([]R, 0, len(items))
+	for i := range items {
+		if errs[i] == nil {
+			ok = append(ok, results[i])
+		}
+	}
+
+	return ok, errors.Join(errs...)
+}
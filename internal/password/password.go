@@ -0,0 +1,108 @@
+// Package password checks passwords against the configured password policy.
+package password
+
+import (
+	"cli/internal/config"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// classes maps the character classes of password_policy.require to their
+// description and test.
+var classes = map[string]struct {
+	desc string
+	is   func(rune) bool
+}{
+	"lower": {"a lowercase letter", unicode.IsLower},
+	"upper": {"an uppercase letter", unicode.IsUpper},
+	"digit": {"a digit", unicode.IsDigit},
+	"symbol": {"a symbol", func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}},
+}
+
+// validate reports unknown character classes in p.
+func validate(p *config.PasswordPolicy) error {
+	for _, class := range p.Require {
+		if _, ok := classes[class]; !ok {
+			return fmt.Errorf(
+				"password_policy.require: unknown class %q "+
+					"(available: lower, upper, digit, symbol)",
+				class,
+			)
+		}
+	}
+
+	return nil
+}
+
+// Check returns an error listing every rule of p the password breaks, or nil
+// if it follows all of them.
+func Check(p *config.PasswordPolicy, password string) error {
+	if err := validate(p); err != nil {
+		return err
+	}
+
+	var problems []string
+	if n := len([]rune(password)); n < p.MinLength {
+		problems = append(problems, fmt.Sprintf(
+			"has %d characters, needs at least %d",
+			n,
+			p.MinLength,
+		))
+	}
+	for _, class := range p.Require {
+		c := classes[class]
+		if !strings.ContainsFunc(password, c.is) {
+			problems = append(problems, "needs "+c.desc)
+		}
+	}
+	if slices.ContainsFunc(p.Deny, func(d string) bool {
+		return strings.EqualFold(d, password)
+	}) {
+		problems = append(problems, "is on the deny list")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"password does not meet the password policy:\n  - %s",
+		strings.Join(problems, "\n  - "),
+	)
+}
+
+// alphabet holds the characters of generated passwords: every class of
+// password_policy.require, without quotes or spaces that are awkward in
+// shells and config files.
+const alphabet = "abcdefghijklmnopqrstuvwxyz" +
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+	"0123456789" +
+	"-_.!@#%^*+="
+
+// Generate returns a random password of at least length characters that
+// follows p.
+func Generate(p *config.PasswordPolicy, length int) (string, error) {
+	if err := validate(p); err != nil {
+		return "", err
+	}
+	length = max(length, p.MinLength)
+	buf := make([]byte, length)
+	for {
+		for i := range buf {
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+			if err != nil {
+				return "", fmt.Errorf("generate password: %w", err)
+			}
+			buf[i] = alphabet[n.Int64()]
+		}
+		// Retry the rare draws that miss a required class.
+		if Check(p, string(buf)) == nil {
+			return string(buf), nil
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package picker lets the user choose omitted command arguments from a
+// fuzzy-searchable list when a terminal is attached.
+package picker
+
+import (
+	"cli/internal/prompt"
+	"cli/internal/styles"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// ErrCanceled is returned when the user closes the picker without choosing.
+var ErrCanceled = errors.New("selection canceled")
+
+// maxHeight limits how many terminal lines the picker takes up.
+const maxHeight = 16
+
+// Source lists the names to pick an argument from, with a title such as
+// "Select a user".
+type Source struct {
+	Title string
+	List  func(ctx context.Context) ([]string, error)
+}
+
+// Interactive reports whether the user can be asked to pick, i.e. input is
+// interactive and stderr is a terminal. Stdout may be redirected.
+func Interactive() bool {
+	stderr := int(os.Stderr.Fd()) // #nosec G115 -- file descriptors fit in an int
+
+	return prompt.Interactive() && term.IsTerminal(stderr)
+}
+
+// ExactArgs is cobra.ExactArgs(n), except that no arguments at all are
+// accepted when Interactive, so that Fill can ask for them.
+func ExactArgs(n int) cobra.PositionalArgs {
+	return orPick(cobra.ExactArgs(n))
+}
+
+// MinimumNArgs is cobra.MinimumNArgs(n), except that no arguments at all
+// are accepted when Interactive, so that Fill can ask for them.
+func MinimumNArgs(n int) cobra.PositionalArgs {
+	return orPick(cobra.MinimumNArgs(n))
+}
+
+// Fill returns args unchanged unless it is empty, in which case one
+// argument is picked from each source in turn.
+func Fill(
+	ctx context.Context,
+	args []string,
+	sources ...Source,
+) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	picked := make([]string, 0, len(sources))
+	for _, src := range sources {
+		names, err := src.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("%s: nothing to choose from", src.Title)
+		}
+		name, err := Pick(src.Title, names)
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, name)
+	}
+
+	return picked, nil
+}
+
+// Pick shows names in a filterable list on stderr and returns the chosen
+// one. Typing filters the list fuzzily; enter selects and esc cancels.
+func Pick(title string, names []string) (string, error) {
+	items := make([]list.Item, len(names))
+	for i, n := range names {
+		items[i] = item(n)
+	}
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+
+	l := list.New(items, delegate, 0, 0)
+	l.Title = title
+	// The filter input replaces the title while typing, so it keeps it.
+	l.FilterInput.Prompt = title + ": "
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.DisableQuitKeybindings()
+
+	opts := []tea.ProgramOption{tea.WithOutput(os.Stderr)}
+	if styles.Plain {
+		opts = []tea.ProgramOption{
+			tea.WithOutput(&styles.PlainFile{File: os.Stderr}),
+		}
+	}
+	res, err := tea.NewProgram(&model{list: l}, opts...).Run()
+	if err != nil {
+		return "", fmt.Errorf("picker: %w", err)
+	}
+	m, _ := res.(*model)
+	if m == nil || m.chosen == "" {
+		return "", ErrCanceled
+	}
+
+	return m.chosen, nil
+}
+
+func orPick(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && Interactive() {
+			return nil
+		}
+
+		return validate(cmd, args)
+	}
+}
+
+// item is a list entry.
+type item string
+
+func (i item) FilterValue() string { return string(i) }
+func (i item) Title() string       { return string(i) }
+func (i item) Description() string { return "" }
+
+// model wraps list.Model, starting in filter mode so typing searches right
+// away.
+type model struct {
+	list   list.Model
+	chosen string
+	done   bool
+}
+
+func (m *model) Init() tea.Cmd {
+	return func() tea.Msg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}}
+	}
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, min(msg.Height, maxHeight))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			m.done = true
+
+			return m, tea.Quit
+		case "esc":
+			if m.list.FilterState() != list.Filtering || m.list.FilterValue() == "" {
+				m.done = true
+
+				return m, tea.Quit
+			}
+		case "enter":
+			// Enter picks the highlighted match, even while still typing.
+			if sel, ok := m.list.SelectedItem().(item); ok {
+				m.chosen = string(sel)
+				m.done = true
+
+				return m, tea.Quit
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+
+	return m, cmd
+}
+
+func (m *model) View() string {
+	if m.done {
+		return ""
+	}
+
+	return m.list.View()
+}
@@ -0,0 +1,63 @@
+package picker
+
+import (
+	"cli/internal/i18n"
+	"context"
+	"iter"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+)
+
+// Users picks a username.
+func Users(c *enclave.Client) Source {
+	return Source{
+		Title: i18n.T("Select a user"),
+		List: func(ctx context.Context) ([]string, error) {
+			return names(c.ListUsers(ctx), func(u enclave.User) string {
+				return u.Name
+			})
+		},
+	}
+}
+
+// Roles picks a role name.
+func Roles(c *enclave.Client) Source {
+	return Source{
+		Title: i18n.T("Select a role"),
+		List: func(ctx context.Context) ([]string, error) {
+			return names(c.ListRoles(ctx), func(r enclave.Role) string {
+				return r.Name
+			})
+		},
+	}
+}
+
+// ResourceGroups picks a resource group name.
+func ResourceGroups(c *enclave.Client) Source {
+	return Source{
+		Title: i18n.T("Select a resource group"),
+		List: func(ctx context.Context) ([]string, error) {
+			return names(
+				c.ListResourceGroups(ctx),
+				func(rg enclave.ResourceGroup) string { return rg.Name },
+			)
+		},
+	}
+}
+
+// names collects the name of every object in seq.
+func names[T any](
+	seq iter.Seq2[T, error],
+	name func(T) string,
+) ([]string, error) {
+	objs, err := enclave.Collect(seq)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, len(objs))
+	for i, o := range objs {
+		out[i] = name(o)
+	}
+
+	return out, nil
+}
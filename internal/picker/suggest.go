@@ -0,0 +1,70 @@
+package picker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+)
+
+// Suggest adds the closest existing name from src to err when err reports
+// that name was not found, e.g. "did you mean "developer"?" for
+// "developr". Other errors, and names without a close match, are returned
+// unchanged.
+func Suggest(ctx context.Context, src Source, name string, err error) error {
+	if !errors.Is(err, enclave.ErrNotFound) {
+		return err
+	}
+	names, listErr := src.List(ctx)
+	if listErr != nil {
+		return err
+	}
+	if match, ok := closest(name, names); ok {
+		return fmt.Errorf("%w; did you mean %q?", err, match)
+	}
+
+	return err
+}
+
+// closest returns the candidate with the smallest edit distance to name,
+// if it is close enough to be a likely typo.
+func closest(name string, candidates []string) (string, bool) {
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		d := distance(strings.ToLower(name), strings.ToLower(c))
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	limit := max(2, len([]rune(name))/3)
+
+	return best, bestDist >= 0 && bestDist <= limit
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
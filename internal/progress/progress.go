@@ -0,0 +1,177 @@
+// Package progress draws spinners and progress bars on stderr for operations
+// that take noticeably long. Nothing is drawn when stderr is not a terminal.
+package progress
+
+import (
+	"cli/internal/output"
+	"cli/internal/styles"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	bar "github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"golang.org/x/term"
+)
+
+// showDelay keeps quick operations free of flicker: indicators only appear
+// once an operation has been running this long.
+const showDelay = 300 * time.Millisecond
+
+// clearLine returns the cursor to the line start and erases the line.
+const clearLine = "\r\x1b[K"
+
+// Spin runs fn and shows a spinner labelled title while it runs.
+func Spin[T any](title string, fn func() (T, error)) (T, error) {
+	frames := spinner.Dot
+	style := lipgloss.NewStyle().Foreground(styles.ColorPrimaryGreen)
+	ind := start(frames.FPS, func(frame int) string {
+		return style.Render(frames.Frames[frame%len(frames.Frames)]) +
+			" " + title
+	})
+	v, err := fn()
+	ind.stop()
+
+	return v, err
+}
+
+// Reader reports read progress of an underlying reader on stderr. A bar is
+// drawn when the total size is known, a byte counter otherwise.
+type Reader struct {
+	r    io.Reader
+	read atomic.Int64
+	ind  *indicator
+}
+
+// NewReader wraps r. size is the expected total in bytes, or <= 0 if unknown.
+// Call Done once reading is finished.
+func NewReader(r io.Reader, size int64, title string) *Reader {
+	pr := &Reader{r: r}
+	b := bar.New(
+		bar.WithSolidFill(hex(styles.ColorPrimaryGreen)),
+		bar.WithWidth(30),
+	)
+	pr.ind = start(100*time.Millisecond, func(int) string {
+		n := pr.read.Load()
+		if size <= 0 {
+			return fmt.Sprintf("%s %s", title, output.FormatSize(n, false))
+		}
+
+		return fmt.Sprintf(
+			"%s %s %s/%s",
+			title,
+			b.ViewAs(min(float64(n)/float64(size), 1)),
+			output.FormatSize(n, false),
+			output.FormatSize(size, false),
+		)
+	})
+
+	return pr
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read.Add(int64(n))
+
+	return n, err
+}
+
+// Done removes the indicator.
+func (r *Reader) Done() {
+	r.ind.stop()
+}
+
+// Counter reports how many of a known number of items are finished.
+type Counter struct {
+	done atomic.Int64
+	ind  *indicator
+}
+
+// NewCounter starts a counter for total items. Call Done when finished.
+func NewCounter(title string, total int) *Counter {
+	c := &Counter{}
+	frames := spinner.Dot
+	style := lipgloss.NewStyle().Foreground(styles.ColorPrimaryGreen)
+	c.ind = start(frames.FPS, func(frame int) string {
+		return fmt.Sprintf(
+			"%s %s %d/%d",
+			style.Render(frames.Frames[frame%len(frames.Frames)]),
+			title,
+			c.done.Load(),
+			total,
+		)
+	})
+
+	return c
+}
+
+// Inc marks one item as finished.
+func (c *Counter) Inc() {
+	c.done.Add(1)
+}
+
+// Done removes the indicator.
+func (c *Counter) Done() {
+	c.ind.stop()
+}
+
+// indicator redraws a single status line on stderr until stopped.
+type indicator struct {
+	quit chan struct{}
+	done chan struct{}
+}
+
+// start begins drawing view every interval after showDelay. It returns nil
+// when stderr is not a terminal; stopping a nil indicator is a no-op.
+func start(interval time.Duration, view func(frame int) string) *indicator {
+	// Plain mode never redraws lines, which screen readers read repeatedly.
+	fd := int(os.Stderr.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if styles.Plain || !term.IsTerminal(fd) {
+		return nil
+	}
+
+	ind := &indicator{quit: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(ind.done)
+
+		select {
+		case <-ind.quit:
+			return
+		case <-time.After(showDelay):
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			_, _ = fmt.Fprint(os.Stderr, clearLine+view(frame))
+			select {
+			case <-ind.quit:
+				_, _ = fmt.Fprint(os.Stderr, clearLine)
+
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return ind
+}
+
+// stop removes the indicator and waits until the line is cleared.
+func (i *indicator) stop() {
+	if i == nil {
+		return
+	}
+	close(i.quit)
+	<-i.done
+}
+
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
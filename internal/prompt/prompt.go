@@ -0,0 +1,62 @@
+// Package prompt asks the user for confirmation on the terminal.
+package prompt
+
+import (
+	"bufio"
+	"cli/internal/i18n"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNotInteractive is returned when a confirmation is needed but stdin is
+// not a terminal or --non-interactive is set.
+var ErrNotInteractive = errors.New(
+	"confirmation required but input is not interactive (use --yes)",
+)
+
+// nonInteractive is set by --non-interactive.
+var nonInteractive bool
+
+// SetNonInteractive turns off prompts, pickers, editors, and interactive
+// views even when a terminal is attached, for CI.
+func SetNonInteractive() {
+	nonInteractive = true
+}
+
+// Interactive reports whether the user can be asked for input: stdin is a
+// terminal and --non-interactive is not set.
+func Interactive() bool {
+	stdin := int(os.Stdin.Fd()) // #nosec G115 -- file descriptors fit in an int
+
+	return !nonInteractive && term.IsTerminal(stdin)
+}
+
+// Confirm asks question on stderr and reports whether the user answered
+// yes. Anything but "y" or "yes" declines.
+func Confirm(question string) (bool, error) {
+	if !Interactive() {
+		return false, ErrNotInteractive
+	}
+	if _, err := fmt.Fprintf(
+		os.Stderr,
+		i18n.T("%s [y/N]: "),
+		question,
+	); err != nil {
+		return false, err
+	}
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("read answer: %w", err)
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	// German answers are accepted regardless of the active language.
+	case "y", "yes", "j", "ja":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
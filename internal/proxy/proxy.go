@@ -0,0 +1,257 @@
+// Package proxy implements a pull-through cache for artifact downloads in
+// front of an Enclave API.
+package proxy
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/rs/zerolog/log"
+)
+
+// Proxy serves artifact downloads from a directory, fetching misses from
+// the upstream API with its own credentials. Every download is first looked
+// up upstream with the caller's credentials, so the cache serves nothing
+// the caller could not download directly. All other requests are forwarded
+// upstream unchanged.
+type Proxy struct {
+	c      *enclave.Client
+	apiURL string
+	dir    string
+	// upstream forwards requests the proxy does not cache.
+	upstream *httputil.ReverseProxy
+}
+
+// New returns a proxy that caches in dir and forwards to the API at apiURL
+// through transport.
+func New(
+	c *enclave.Client,
+	apiURL string,
+	transport http.RoundTripper,
+	dir string,
+) (*Proxy, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse api url: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	upstream := httputil.NewSingleHostReverseProxy(u)
+	upstream.Transport = transport
+
+	return &Proxy{c: c, apiURL: apiURL, dir: dir, upstream: upstream}, nil
+}
+
+// Handler returns the HTTP handler of the proxy.
+func (p *Proxy) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(
+		"GET /v1/artifact/raw/{ns}/{name}/hash/{hash}",
+		p.downloadByHash,
+	)
+	mux.HandleFunc(
+		"GET /v1/artifact/raw/{ns}/{name}/tag/{tag}",
+		p.downloadByTag,
+	)
+	mux.Handle("/", p.upstream)
+
+	return mux
+}
+
+func (p *Proxy) downloadByHash(w http.ResponseWriter, r *http.Request) {
+	ns, name, hash := r.PathValue("ns"), r.PathValue("name"), r.PathValue("hash")
+	if !validSegments(ns, name, hash) {
+		writeError(w, http.StatusBadRequest, "invalid artifact reference")
+
+		return
+	}
+	c, ok := p.caller(w, r)
+	if !ok {
+		return
+	}
+	if _, err := c.GetArtifactByHash(r.Context(), ns, name, hash); err != nil {
+		writeUpstreamError(w, err)
+
+		return
+	}
+	p.serve(w, r, ns, name, hash)
+}
+
+// downloadByTag resolves the tag upstream, since tags move, and serves the
+// version it points to.
+func (p *Proxy) downloadByTag(w http.ResponseWriter, r *http.Request) {
+	ns, name, tag := r.PathValue("ns"), r.PathValue("name"), r.PathValue("tag")
+	if !validSegments(ns, name, tag) {
+		writeError(w, http.StatusBadRequest, "invalid artifact reference")
+
+		return
+	}
+	c, ok := p.caller(w, r)
+	if !ok {
+		return
+	}
+	a, err := c.GetArtifactByTag(r.Context(), ns, name, tag)
+	if err != nil {
+		writeUpstreamError(w, err)
+
+		return
+	}
+	p.serve(w, r, ns, name, a.VersionHash)
+}
+
+// caller returns a client with the credentials of r. Without credentials it
+// answers 401 and returns false.
+func (p *Proxy) caller(
+	w http.ResponseWriter,
+	r *http.Request,
+) (*enclave.Client, bool) {
+	user, password, ok := r.BasicAuth()
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="enclave"`)
+		writeError(w, http.StatusUnauthorized, "credentials required")
+
+		return nil, false
+	}
+	c, err := enclave.New(p.apiURL, user, password)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+
+		return nil, false
+	}
+
+	return c, true
+}
+
+// serve writes the cached content of the version hash, fetching it first on
+// a miss.
+func (p *Proxy) serve(
+	w http.ResponseWriter,
+	r *http.Request,
+	ns, name, hash string,
+) {
+	if !validSegments(hash) {
+		writeError(w, http.StatusBadGateway, "invalid version hash")
+
+		return
+	}
+	path := filepath.Join(p.dir, ns, name, "blobs", hash)
+	cache := "HIT"
+	// #nosec G703 -- path segments are checked by validSegments
+	if _, err := os.Stat(path); err != nil {
+		cache = "MISS"
+		if err := p.fetch(r.Context(), ns, name, hash, path); err != nil {
+			writeUpstreamError(w, err)
+
+			return
+		}
+	}
+	log.Info().
+		Str("artifact", ns+"/"+name+"@"+hash).
+		Str("cache", cache).
+		Msg("download")
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("X-Cache", cache)
+	http.ServeFile(w, r, path)
+}
+
+// fetch downloads the version hash from upstream into path.
+func (p *Proxy) fetch(
+	ctx context.Context,
+	ns, name, hash, path string,
+) error {
+	body, err := p.c.DownloadArtifactByHash(ctx, ns, name, hash)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	return writeFile(path, body, hash)
+}
+
+// writeFile stores r at path. The file only appears once it is complete
+// and its SHA-256 digest matches hash, so readers never see a partial or
+// corrupted download.
+func writeFile(path string, r io.Reader, hash string) error {
+	// #nosec G703 -- path segments are checked by validSegments
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create cache file: %w", err)
+	}
+	// #nosec G703 -- path segments are checked by validSegments
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), r); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("download artifact: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write cache file: %w", err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != hash {
+		return fmt.Errorf("downloaded artifact has sha256 %s, want %s", got, hash)
+	}
+	// #nosec G703 -- path segments are checked by validSegments
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write cache file: %w", err)
+	}
+
+	return nil
+}
+
+// validSegments reports whether all path segments are safe to use as file
+// names in the cache.
+func validSegments(segments ...string) bool {
+	for _, s := range segments {
+		if s == "" || s == "." || s == ".." || strings.ContainsAny(s, `/\`) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeUpstreamError relays errors of the upstream API with their status,
+// and reports other failures as 502.
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	var apiErr *enclave.APIError
+	if errors.As(err, &apiErr) {
+		writeError(
+			w,
+			apiErr.StatusCode,
+			cmp.Or(apiErr.Message, http.StatusText(apiErr.StatusCode)),
+		)
+
+		return
+	}
+	writeError(w, http.StatusBadGateway, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{msg}); err != nil {
+		log.Debug().Err(err).Msg("proxy: write response")
+	}
+}
@@ -0,0 +1,105 @@
+package rbac
+
+import (
+	"cli/internal/ci"
+	"cli/internal/client"
+	"cli/internal/i18n"
+	"cli/internal/prompt"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// AddYesFlag registers -y/--yes, which skips the confirmation in Apply.
+func AddYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+}
+
+// Apply lists steps on the command's output, asks for confirmation unless
+// --yes is set, and applies them in order, stopping at the first failure.
+// Commands with a --dry-run flag stop after the listing when it is set, and
+// commands with --atomic revert the applied steps if a later one fails.
+func Apply(cmd *cobra.Command, steps []Step) error {
+	out := cmd.OutOrStdout()
+
+	if len(steps) == 0 {
+		_, err := fmt.Fprintln(out, i18n.T("No changes."))
+
+		return err
+	}
+	endGroup := ci.Group(out, i18n.Sprintf("%d planned changes", len(steps)))
+	for _, s := range steps {
+		_, _ = fmt.Fprintln(out, "  "+s.Desc)
+	}
+	endGroup()
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return nil
+	}
+
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+		ok, err := prompt.Confirm(i18n.T("Apply these changes?"))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New(i18n.T("canceled"))
+		}
+	}
+
+	if err := applySteps(cmd, steps); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(out, i18n.T("Done."))
+
+	return err
+}
+
+// applySteps applies steps in order. With --atomic, the steps applied before
+// a failure are reverted, newest first.
+func applySteps(cmd *cobra.Command, steps []Step) error {
+	ctx := cmd.Context()
+	c := client.FromContext(ctx)
+	atomic := Atomic(cmd)
+
+	var undo []Step
+	for _, s := range steps {
+		since := Recorded(ctx)
+		err := s.Apply(ctx, c)
+		if atomic {
+			switch {
+			case s.Revert != nil && err == nil:
+				undo = append(undo, Step{
+					Desc:  i18n.Sprintf("undo %s", s.Desc),
+					Apply: s.Revert,
+				})
+			case s.Revert == nil:
+				inv, invErr := inverses(ctx, since)
+				if invErr != nil {
+					// What the step changed cannot be reverted; revert
+					// the steps before it.
+					cause := fmt.Errorf("%s cannot be reverted: %w", s.Desc, invErr)
+					if err != nil {
+						cause = errors.Join(fmt.Errorf("%s: %w", s.Desc, err), cause)
+					}
+					slices.Reverse(undo)
+
+					return rollback(ctx, c, cmd.OutOrStdout(), undo, cause)
+				}
+				undo = append(undo, inv...)
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("%s: %w", s.Desc, err)
+			if !atomic {
+				return err
+			}
+			slices.Reverse(undo)
+
+			return rollback(ctx, c, cmd.OutOrStdout(), undo, err)
+		}
+	}
+
+	return nil
+}
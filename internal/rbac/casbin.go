@@ -0,0 +1,189 @@
+package rbac
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// casbinActions maps the Casbin actions commonly used in place of HTTP
+// methods to the methods they cover.
+var casbinActions = map[string][]string{
+	"read":  {"GET", "HEAD"},
+	"write": {"POST", "PUT", "PATCH", "DELETE"},
+}
+
+// ParseCasbin converts a Casbin policy file to a model. It understands
+// RBAC policies with resource roles:
+//
+//	p, <role>, <endpoint or resource group>, <method or read/write>
+//	g, <user>, <role>
+//	g2, <endpoint>, <resource group>
+//
+// Objects of p lines that are no g2 group become resource groups of their
+// own, named after the endpoint. Deny rules and role hierarchies have no
+// equivalent and are rejected.
+func ParseCasbin(r io.Reader) (Model, error) {
+	cr := csv.NewReader(r)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var m Model
+	type rule struct{ sub, obj, act string }
+	var rules []rule
+	groups := map[string][]string{}
+	var groupOrder []string
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return m, fmt.Errorf("parse casbin policy: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		for i := range rec {
+			rec[i] = strings.TrimSpace(rec[i])
+		}
+		switch {
+		case rec[0] == "p" && len(rec) >= 4:
+			if len(rec) > 4 && strings.EqualFold(rec[4], "deny") {
+				return m, fmt.Errorf("line %d: deny rules are not supported", line)
+			}
+			rules = append(rules, rule{sub: rec[1], obj: rec[2], act: rec[3]})
+		case rec[0] == "g" && len(rec) >= 3:
+			addRoleUser(&m, rec[2], rec[1])
+		case rec[0] == "g2" && len(rec) >= 3:
+			if _, ok := groups[rec[2]]; !ok {
+				groupOrder = append(groupOrder, rec[2])
+			}
+			groups[rec[2]] = append(groups[rec[2]], rec[1])
+		default:
+			return m, fmt.Errorf(
+				"line %d: unsupported rule %q",
+				line,
+				strings.Join(rec, ", "),
+			)
+		}
+	}
+
+	for _, name := range groupOrder {
+		m.ResourceGroups = append(m.ResourceGroups, ResourceGroup{
+			Name:      name,
+			Endpoints: groups[name],
+		})
+	}
+	for _, r := range rules {
+		group := r.obj
+		if _, ok := groups[r.obj]; !ok {
+			group = groupName(r.obj)
+			if !slices.ContainsFunc(m.ResourceGroups, func(rg ResourceGroup) bool {
+				return rg.Name == group
+			}) {
+				m.ResourceGroups = append(m.ResourceGroups, ResourceGroup{
+					Name:      group,
+					Endpoints: []string{r.obj},
+				})
+			}
+		}
+		addRoleUser(&m, r.sub, "")
+
+		methods, ok := casbinActions[strings.ToLower(r.act)]
+		if !ok {
+			methods = []string{strings.ToUpper(r.act)}
+		}
+		for _, method := range methods {
+			p := Policy{Role: r.sub, ResourceGroup: group, Method: method}
+			if !slices.Contains(m.Policies, p) {
+				m.Policies = append(m.Policies, p)
+			}
+		}
+	}
+
+	for _, r := range m.Roles {
+		for _, u := range r.Users {
+			if slices.ContainsFunc(m.Roles, func(x Role) bool {
+				return x.Name == u
+			}) {
+				return m, fmt.Errorf(
+					"role %s inherits from role %s; role hierarchies are not supported",
+					u,
+					r.Name,
+				)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// addRoleUser adds role to m if needed and assigns user to it unless user
+// is empty.
+func addRoleUser(m *Model, role, user string) {
+	i := slices.IndexFunc(m.Roles, func(r Role) bool { return r.Name == role })
+	if i < 0 {
+		m.Roles = append(m.Roles, Role{Name: role})
+		i = len(m.Roles) - 1
+	}
+	if user != "" && !slices.Contains(m.Roles[i].Users, user) {
+		m.Roles[i].Users = append(m.Roles[i].Users, user)
+	}
+}
+
+// groupName derives a resource group name from an endpoint pattern, e.g.
+// "v1-artifact-all" from "/v1/artifact/*".
+func groupName(endpoint string) string {
+	var b strings.Builder
+	for _, r := range endpoint {
+		switch {
+		case r == '*':
+			b.WriteString("-all-")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune('-')
+		}
+	}
+	name := strings.Join(strings.FieldsFunc(b.String(), func(r rune) bool {
+		return r == '-'
+	}), "-")
+	if name == "" {
+		return "all"
+	}
+
+	return name
+}
+
+// WriteCasbin writes m as a Casbin policy file in the layout ParseCasbin
+// reads.
+func WriteCasbin(w io.Writer, m *Model) error {
+	cw := csv.NewWriter(w)
+	for _, rg := range m.ResourceGroups {
+		for _, e := range rg.Endpoints {
+			if err := cw.Write([]string{"g2", e, rg.Name}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range m.Roles {
+		for _, u := range r.Users {
+			if err := cw.Write([]string{"g", u, r.Name}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, p := range m.Policies {
+		err := cw.Write([]string{"p", p.Role, p.ResourceGroup, p.Method})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,196 @@
+package rbac
+
+import (
+	"cli/internal/diff"
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Change is one difference between two models.
+type Change struct {
+	// Op is "+" for objects only in the second model, "-" for objects only
+	// in the first, and "~" for objects in both that differ.
+	Op   string `json:"op"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Detail lists the differing members of changed objects, e.g.
+	// "users -bob +alice".
+	Detail string `json:"detail,omitempty"`
+}
+
+// Diff returns the changes that turn a into b: roles and their users,
+// resource groups and their endpoints, and policies.
+func Diff(a, b *Model) []Change {
+	var changes []Change
+
+	rolesA := roleUsers(a)
+	rolesB := roleUsers(b)
+	changes = append(changes, diffSets("role", "users", rolesA, rolesB)...)
+
+	groupsA := groupEndpoints(a)
+	groupsB := groupEndpoints(b)
+	changes = append(
+		changes,
+		diffSets("resource group", "endpoints", groupsA, groupsB)...,
+	)
+
+	for _, p := range a.Policies {
+		if !slices.Contains(b.Policies, p) {
+			changes = append(changes, Change{
+				Op:   "-",
+				Kind: "policy",
+				Name: policyName(p),
+			})
+		}
+	}
+	for _, p := range b.Policies {
+		if !slices.Contains(a.Policies, p) {
+			changes = append(changes, Change{
+				Op:   "+",
+				Kind: "policy",
+				Name: policyName(p),
+			})
+		}
+	}
+
+	return changes
+}
+
+func roleUsers(m *Model) map[string][]string {
+	out := make(map[string][]string, len(m.Roles))
+	for _, r := range m.Roles {
+		out[r.Name] = r.Users
+	}
+
+	return out
+}
+
+func groupEndpoints(m *Model) map[string][]string {
+	out := make(map[string][]string, len(m.ResourceGroups))
+	for _, rg := range m.ResourceGroups {
+		out[rg.Name] = rg.Endpoints
+	}
+
+	return out
+}
+
+// diffSets compares named objects that each hold a set of members.
+func diffSets(kind, members string, a, b map[string][]string) []Change {
+	var changes []Change
+	for _, name := range sortedKeys(a, b) {
+		am, inA := a[name]
+		bm, inB := b[name]
+		switch {
+		case !inB:
+			changes = append(changes, Change{Op: "-", Kind: kind, Name: name})
+		case !inA:
+			changes = append(changes, Change{Op: "+", Kind: kind, Name: name})
+		default:
+			var detail []string
+			for _, m := range am {
+				if !slices.Contains(bm, m) {
+					detail = append(detail, "-"+m)
+				}
+			}
+			for _, m := range bm {
+				if !slices.Contains(am, m) {
+					detail = append(detail, "+"+m)
+				}
+			}
+			if len(detail) > 0 {
+				changes = append(changes, Change{
+					Op:     "~",
+					Kind:   kind,
+					Name:   name,
+					Detail: members + " " + strings.Join(detail, " "),
+				})
+			}
+		}
+	}
+
+	return changes
+}
+
+// sortedKeys returns the keys of a and b, sorted and without duplicates.
+func sortedKeys(a, b map[string][]string) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return slices.Compact(keys)
+}
+
+// policyName renders p the way plan steps do.
+func policyName(p Policy) string {
+	return p.Role + " → " + p.ResourceGroup + " (" + p.Method + ")"
+}
+
+// UnifiedDiff returns the unified diff between the YAML forms of a and b,
+// labelled from and to. Objects and their members are sorted first, so only
+// real differences show up. It returns "" when the models are equal.
+func UnifiedDiff(a, b *Model, from, to string) (string, error) {
+	docA, err := a.sorted().document()
+	if err != nil {
+		return "", err
+	}
+	docB, err := b.sorted().document()
+	if err != nil {
+		return "", err
+	}
+
+	return diff.Unified(from, to, docA, docB), nil
+}
+
+// sorted returns a copy of m with objects sorted by name and their users and
+// endpoints sorted.
+func (m *Model) sorted() Model {
+	out := Model{
+		Roles:          slices.Clone(m.Roles),
+		ResourceGroups: slices.Clone(m.ResourceGroups),
+		Policies:       slices.Clone(m.Policies),
+	}
+	for i, r := range out.Roles {
+		out.Roles[i].Users = slices.Sorted(slices.Values(r.Users))
+	}
+	slices.SortFunc(out.Roles, func(a, b Role) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for i, rg := range out.ResourceGroups {
+		out.ResourceGroups[i].Endpoints = slices.Sorted(
+			slices.Values(rg.Endpoints),
+		)
+	}
+	slices.SortFunc(out.ResourceGroups, func(a, b ResourceGroup) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	slices.SortFunc(out.Policies, func(a, b Policy) int {
+		return cmp.Or(
+			strings.Compare(a.Role, b.Role),
+			strings.Compare(a.ResourceGroup, b.ResourceGroup),
+			strings.Compare(a.Method, b.Method),
+		)
+	})
+
+	return out
+}
+
+// document renders m as a YAML document.
+func (m Model) document() (string, error) {
+	var sb strings.Builder
+	enc := yaml.NewEncoder(&sb)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return "", fmt.Errorf("encode yaml: %w", err)
+	}
+
+	return sb.String(), nil
+}
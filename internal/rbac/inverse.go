@@ -0,0 +1,242 @@
+package rbac
+
+import (
+	"cli/internal/history"
+	"cli/internal/i18n"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+)
+
+// Inverse returns the step that reverses ch. Steps record the changes they
+// make in the history entry of the undo command itself.
+func Inverse(ch *history.Change) (Step, error) {
+	switch ch.Kind {
+	case history.KindPolicy:
+		var p enclave.Policy
+		if err := json.Unmarshal(ch.Object, &p); err != nil {
+			return Step{}, fmt.Errorf("decode recorded policy: %w", err)
+		}
+
+		return inversePolicy(ch.Action, p), nil
+	case history.KindRole:
+		var r, prev enclave.Role
+		if err := json.Unmarshal(ch.Object, &r); err != nil {
+			return Step{}, fmt.Errorf("decode recorded role: %w", err)
+		}
+		if ch.Action == history.ActionUpdate {
+			if err := json.Unmarshal(ch.Previous, &prev); err != nil {
+				return Step{}, fmt.Errorf("decode recorded role: %w", err)
+			}
+
+			return restoreRole(r, prev), nil
+		}
+
+		return inverseRole(ch.Action, r), nil
+	case history.KindResourceGroup:
+		var rg, prev enclave.ResourceGroup
+		if err := json.Unmarshal(ch.Object, &rg); err != nil {
+			return Step{}, fmt.Errorf(
+				"decode recorded resource group: %w",
+				err,
+			)
+		}
+		if ch.Action == history.ActionUpdate {
+			if err := json.Unmarshal(ch.Previous, &prev); err != nil {
+				return Step{}, fmt.Errorf(
+					"decode recorded resource group: %w",
+					err,
+				)
+			}
+
+			return restoreResourceGroup(rg, prev), nil
+		}
+
+		return inverseResourceGroup(ch.Action, rg), nil
+	default:
+		return Step{}, fmt.Errorf("cannot undo %s %s", ch.Action, ch.Kind)
+	}
+}
+
+func inversePolicy(action string, p enclave.Policy) Step {
+	name := fmt.Sprintf("%s → %s (%s)", p.Role, p.ResourceGroup, p.Method)
+	if action == history.ActionCreate {
+		return Step{
+			Desc: i18n.Sprintf("delete policy %s", name),
+			Apply: func(ctx context.Context, c *enclave.Client) error {
+				if err := c.DeletePolicy(ctx, p); err != nil {
+					return err
+				}
+				history.RecordChange(
+					ctx,
+					history.KindPolicy,
+					history.ActionDelete,
+					p,
+				)
+
+				return nil
+			},
+		}
+	}
+
+	return Step{
+		Desc: i18n.Sprintf("recreate policy %s", name),
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			if err := c.CreatePolicy(ctx, p); err != nil {
+				return err
+			}
+			history.RecordChange(ctx, history.KindPolicy, history.ActionCreate, p)
+
+			return nil
+		},
+	}
+}
+
+func inverseRole(action string, r enclave.Role) Step {
+	if action == history.ActionCreate {
+		desc := i18n.Sprintf("delete role %s", r.Name)
+		if len(r.Users) > 0 {
+			desc += i18n.Sprintf(", unassigning %s", strings.Join(r.Users, ", "))
+		}
+
+		return Step{
+			Desc: desc,
+			Apply: func(ctx context.Context, c *enclave.Client) error {
+				deleted, err := c.DeleteRole(ctx, r.Name)
+				if err != nil {
+					return err
+				}
+				history.RecordChange(
+					ctx,
+					history.KindRole,
+					history.ActionDelete,
+					deleted,
+				)
+
+				return nil
+			},
+		}
+	}
+
+	desc := i18n.Sprintf("recreate role %s", r.Name)
+	if len(r.Users) > 0 {
+		desc += i18n.Sprintf(" for %s", strings.Join(r.Users, ", "))
+	}
+
+	return Step{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			created, err := c.CreateRole(ctx, r.Name, r.Users)
+			if err != nil {
+				return err
+			}
+			history.RecordChange(
+				ctx,
+				history.KindRole,
+				history.ActionCreate,
+				created,
+			)
+
+			return nil
+		},
+	}
+}
+
+func inverseResourceGroup(action string, rg enclave.ResourceGroup) Step {
+	if action == history.ActionCreate {
+		return Step{
+			Desc: i18n.Sprintf("delete resource group %s", rg.Name),
+			Apply: func(ctx context.Context, c *enclave.Client) error {
+				deleted, err := c.DeleteResourceGroup(ctx, rg.Name)
+				if err != nil {
+					return err
+				}
+				history.RecordChange(
+					ctx,
+					history.KindResourceGroup,
+					history.ActionDelete,
+					deleted,
+				)
+
+				return nil
+			},
+		}
+	}
+
+	desc := i18n.Sprintf("recreate resource group %s", rg.Name)
+	if len(rg.Endpoints) > 0 {
+		desc += i18n.Sprintf(" with %s", strings.Join(rg.Endpoints, ", "))
+	}
+
+	return Step{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			created, err := c.CreateResourceGroup(ctx, rg.Name, rg.Endpoints)
+			if err != nil {
+				return err
+			}
+			history.RecordChange(
+				ctx,
+				history.KindResourceGroup,
+				history.ActionCreate,
+				created,
+			)
+
+			return nil
+		},
+	}
+}
+
+// restoreRole puts the users of prev back on the role r, replacing them in
+// place so the policies of the role are kept.
+func restoreRole(r, prev enclave.Role) Step {
+	desc := i18n.Sprintf("update role %s", prev.Name)
+	if len(prev.Users) > 0 {
+		desc += i18n.Sprintf(" for %s", strings.Join(prev.Users, ", "))
+	}
+
+	return Step{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			restored, err := c.CreateRole(ctx, prev.Name, prev.Users)
+			if err != nil {
+				return err
+			}
+			history.RecordUpdate(ctx, history.KindRole, r, restored)
+
+			return nil
+		},
+	}
+}
+
+// restoreResourceGroup puts the endpoints of prev back on the resource
+// group rg, replacing them in place so the policies on it are kept.
+func restoreResourceGroup(rg, prev enclave.ResourceGroup) Step {
+	desc := i18n.Sprintf("update resource group %s", prev.Name)
+	desc += i18n.Sprintf(" with %s", strings.Join(prev.Endpoints, ", "))
+
+	return Step{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			restored, err := c.CreateResourceGroup(
+				ctx,
+				prev.Name,
+				prev.Endpoints,
+			)
+			if err != nil {
+				return err
+			}
+			history.RecordUpdate(
+				ctx,
+				history.KindResourceGroup,
+				rg,
+				restored,
+			)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EndpointRule assigns the OpenAPI paths it matches to a resource group.
+type EndpointRule struct {
+	// Kind is "tag" to match operations by tag, "path" to match paths by
+	// prefix, or "*" to match every path.
+	Kind          string
+	Value         string
+	ResourceGroup string
+}
+
+// ParseEndpointRule parses tag:<tag>=<group>, path:<prefix>=<group>, or
+// *=<group>.
+func ParseEndpointRule(s string) (EndpointRule, error) {
+	match, group, ok := strings.Cut(s, "=")
+	if !ok || group == "" {
+		return EndpointRule{}, fmt.Errorf(
+			"invalid mapping %q, want tag:<tag>=<group>, "+
+				"path:<prefix>=<group>, or *=<group>",
+			s,
+		)
+	}
+	if match == "*" {
+		return EndpointRule{Kind: "*", ResourceGroup: group}, nil
+	}
+	kind, value, ok := strings.Cut(match, ":")
+	if !ok || value == "" || kind != "tag" && kind != "path" {
+		return EndpointRule{}, fmt.Errorf(
+			"invalid mapping %q, want tag:<tag>=<group>, "+
+				"path:<prefix>=<group>, or *=<group>",
+			s,
+		)
+	}
+
+	return EndpointRule{Kind: kind, Value: value, ResourceGroup: group}, nil
+}
+
+// matches reports whether the rule applies to path, whose operations carry
+// tags.
+func (r EndpointRule) matches(path string, tags []string) bool {
+	switch r.Kind {
+	case "tag":
+		return slices.Contains(tags, r.Value)
+	case "path":
+		return strings.HasPrefix(path, r.Value)
+	default:
+		return true
+	}
+}
+
+// openAPIMethods are the operation keys of an OpenAPI path item.
+var openAPIMethods = []string{
+	"get", "put", "post", "delete", "options", "head", "patch", "trace",
+}
+
+// openAPIParam matches path templates such as {namespace}.
+var openAPIParam = regexp.MustCompile(`\{[^/}]+\}`)
+
+// OpenAPIPaths reads an OpenAPI document in YAML or JSON and returns the
+// tags of the operations of every path. Path templates are turned into
+// endpoint wildcards, e.g. /v1/artifact/{ns} becomes /v1/artifact/*.
+func OpenAPIPaths(r io.Reader) (map[string][]string, error) {
+	var doc struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse openapi document: %w", err)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("openapi document has no paths")
+	}
+
+	paths := make(map[string][]string, len(doc.Paths))
+	for path, item := range doc.Paths {
+		endpoint := openAPIParam.ReplaceAllString(path, "*")
+		tags := paths[endpoint]
+		for _, method := range openAPIMethods {
+			node, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op struct {
+				Tags []string `yaml:"tags"`
+			}
+			if err := node.Decode(&op); err != nil {
+				return nil, fmt.Errorf("parse %s %s: %w", method, path, err)
+			}
+			for _, t := range op.Tags {
+				if !slices.Contains(tags, t) {
+					tags = append(tags, t)
+				}
+			}
+		}
+		paths[endpoint] = tags
+	}
+
+	return paths, nil
+}
+
+// MapEndpoints assigns every path to the resource group of the first rule
+// that matches it. It returns the resulting resource groups and the paths
+// no rule matched, both sorted.
+func MapEndpoints(
+	paths map[string][]string,
+	rules []EndpointRule,
+) (m Model, unmapped []string) {
+	endpoints := make([]string, 0, len(paths))
+	for p := range paths {
+		endpoints = append(endpoints, p)
+	}
+	sort.Strings(endpoints)
+
+	for _, p := range endpoints {
+		i := slices.IndexFunc(rules, func(r EndpointRule) bool {
+			return r.matches(p, paths[p])
+		})
+		if i < 0 {
+			unmapped = append(unmapped, p)
+
+			continue
+		}
+		group := rules[i].ResourceGroup
+		j := slices.IndexFunc(m.ResourceGroups, func(rg ResourceGroup) bool {
+			return rg.Name == group
+		})
+		if j < 0 {
+			m.ResourceGroups = append(m.ResourceGroups, ResourceGroup{Name: group})
+			j = len(m.ResourceGroups) - 1
+		}
+		m.ResourceGroups[j].Endpoints = append(m.ResourceGroups[j].Endpoints, p)
+	}
+
+	return m, unmapped
+}
@@ -0,0 +1,124 @@
+package rbac
+
+import (
+	"slices"
+	"strings"
+)
+
+// Preset is a built-in RBAC template.
+type Preset struct {
+	Name        string
+	Description string
+	model       Model
+}
+
+// presets are the built-in templates, in the order they are listed.
+var presets = []Preset{
+	{
+		Name:        "admin",
+		Description: "Full access to every endpoint",
+		model: Model{
+			Roles:          []Role{{Name: "admin"}},
+			ResourceGroups: []ResourceGroup{{Name: "all", Endpoints: []string{"*"}}},
+			Policies: []Policy{
+				{Role: "admin", ResourceGroup: "all", Method: "*"},
+			},
+		},
+	},
+	{
+		Name:        "read-only",
+		Description: "Read access to every endpoint",
+		model: Model{
+			Roles:          []Role{{Name: "read-only"}},
+			ResourceGroups: []ResourceGroup{{Name: "all", Endpoints: []string{"*"}}},
+			Policies: []Policy{
+				{Role: "read-only", ResourceGroup: "all", Method: "GET"},
+				{Role: "read-only", ResourceGroup: "all", Method: "HEAD"},
+			},
+		},
+	},
+	{
+		Name:        "artifact-publisher",
+		Description: "Read, upload, and tag artifacts",
+		model: Model{
+			Roles: []Role{{Name: "artifact-publisher"}},
+			ResourceGroups: []ResourceGroup{{
+				Name:      "artifacts",
+				Endpoints: []string{"/v1/artifact", "/v1/artifact/*"},
+			}},
+			Policies: []Policy{
+				{
+					Role:          "artifact-publisher",
+					ResourceGroup: "artifacts",
+					Method:        "GET",
+				},
+				{
+					Role:          "artifact-publisher",
+					ResourceGroup: "artifacts",
+					Method:        "HEAD",
+				},
+				{
+					Role:          "artifact-publisher",
+					ResourceGroup: "artifacts",
+					Method:        "POST",
+				},
+				{
+					Role:          "artifact-publisher",
+					ResourceGroup: "artifacts",
+					Method:        "PATCH",
+				},
+			},
+		},
+	},
+}
+
+// Presets returns the built-in templates.
+func Presets() []Preset {
+	return slices.Clone(presets)
+}
+
+// LookupPreset returns the built-in template called name.
+func LookupPreset(name string) (Preset, bool) {
+	i := slices.IndexFunc(presets, func(p Preset) bool {
+		return p.Name == name
+	})
+	if i < 0 {
+		return Preset{}, false
+	}
+
+	return presets[i], true
+}
+
+// PresetNames lists the names of the built-in templates.
+func PresetNames() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+
+	return names
+}
+
+// Model returns the objects of the preset, with prefix prepended to the
+// names of its roles and resource groups.
+func (p *Preset) Model(prefix string) Model {
+	var m Model
+	for _, r := range p.model.Roles {
+		m.Roles = append(m.Roles, Role{Name: prefix + r.Name})
+	}
+	for _, rg := range p.model.ResourceGroups {
+		m.ResourceGroups = append(m.ResourceGroups, ResourceGroup{
+			Name:      prefix + rg.Name,
+			Endpoints: slices.Clone(rg.Endpoints),
+		})
+	}
+	for _, pol := range p.model.Policies {
+		m.Policies = append(m.Policies, Policy{
+			Role:          prefix + pol.Role,
+			ResourceGroup: prefix + pol.ResourceGroup,
+			Method:        strings.ToUpper(pol.Method),
+		})
+	}
+
+	return m
+}
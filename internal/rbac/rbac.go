@@ -0,0 +1,286 @@
+// Package rbac describes roles, resource groups, and policies as a whole and
+// brings a server in line with such a description.
+package rbac
+
+import (
+	"cli/internal/history"
+	"cli/internal/i18n"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+)
+
+// Model is a set of RBAC objects.
+type Model struct {
+	Roles          []Role          `json:"roles,omitempty"           yaml:"roles,omitempty"`
+	ResourceGroups []ResourceGroup `json:"resource_groups,omitempty" yaml:"resource_groups,omitempty"`
+	Policies       []Policy        `json:"policies,omitempty"        yaml:"policies,omitempty"`
+}
+
+// Role is a role and the users assigned to it.
+type Role struct {
+	Name  string   `json:"name"            yaml:"name"`
+	Users []string `json:"users,omitempty" yaml:"users,omitempty"`
+}
+
+// ResourceGroup is a named set of API endpoints.
+type ResourceGroup struct {
+	Name      string   `json:"name"      yaml:"name"`
+	Endpoints []string `json:"endpoints" yaml:"endpoints"`
+}
+
+// Policy grants a role an HTTP method on a resource group.
+type Policy struct {
+	Role          string `json:"role"           yaml:"role"`
+	ResourceGroup string `json:"resource_group" yaml:"resource_group"`
+	Method        string `json:"method"         yaml:"method"`
+}
+
+// SDK returns p as an enclave.Policy.
+func (p Policy) SDK() enclave.Policy {
+	return enclave.Policy{
+		Role:          p.Role,
+		ResourceGroup: p.ResourceGroup,
+		Method:        enclave.PolicyMethod(strings.ToUpper(p.Method)),
+	}
+}
+
+// Fetch reads the current RBAC objects from the server.
+func Fetch(ctx context.Context, c *enclave.Client) (Model, error) {
+	var m Model
+	roles, err := enclave.Collect(c.ListRoles(ctx))
+	if err != nil {
+		return m, fmt.Errorf("list roles: %w", err)
+	}
+	groups, err := enclave.Collect(c.ListResourceGroups(ctx))
+	if err != nil {
+		return m, fmt.Errorf("list resource groups: %w", err)
+	}
+	policies, err := enclave.Collect(c.ListPolicies(ctx))
+	if err != nil {
+		return m, fmt.Errorf("list policies: %w", err)
+	}
+
+	for _, r := range roles {
+		m.Roles = append(m.Roles, Role{Name: r.Name, Users: r.Users})
+	}
+	for _, rg := range groups {
+		m.ResourceGroups = append(m.ResourceGroups, ResourceGroup{
+			Name:      rg.Name,
+			Endpoints: rg.Endpoints,
+		})
+	}
+	for _, p := range policies {
+		m.Policies = append(m.Policies, Policy{
+			Role:          p.Role,
+			ResourceGroup: p.ResourceGroup,
+			Method:        string(p.Method),
+		})
+	}
+
+	return m, nil
+}
+
+// Step is a single change that brings the server closer to a model.
+type Step struct {
+	Desc  string
+	Apply func(ctx context.Context, c *enclave.Client) error
+	// Revert undoes Apply for --atomic. Steps without it are reverted
+	// through the changes they record in the history.
+	Revert func(ctx context.Context, c *enclave.Client) error
+}
+
+// Plan returns the steps that add what m declares and the server lacks, in
+// dependency order: resource groups, roles, then policies. Nothing is
+// removed; users and endpoints of existing objects are merged with the
+// declared ones. Applying the steps records them for encl rbac undo.
+func Plan(ctx context.Context, c *enclave.Client, m *Model) ([]Step, error) {
+	current, err := Fetch(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []Step
+	for _, rg := range m.ResourceGroups {
+		i := slices.IndexFunc(current.ResourceGroups, func(x ResourceGroup) bool {
+			return x.Name == rg.Name
+		})
+		if i < 0 {
+			steps = append(steps, createResourceGroup(rg, nil))
+
+			continue
+		}
+		old := current.ResourceGroups[i]
+		if merged, changed := union(old.Endpoints, rg.Endpoints); changed {
+			steps = append(steps, createResourceGroup(
+				ResourceGroup{Name: rg.Name, Endpoints: merged},
+				&old,
+			))
+		}
+	}
+
+	for _, r := range m.Roles {
+		i := slices.IndexFunc(current.Roles, func(x Role) bool {
+			return x.Name == r.Name
+		})
+		if i < 0 {
+			steps = append(steps, createRole(r, nil))
+
+			continue
+		}
+		old := current.Roles[i]
+		if merged, changed := union(old.Users, r.Users); changed {
+			steps = append(steps, createRole(
+				Role{Name: r.Name, Users: merged},
+				&old,
+			))
+		}
+	}
+
+	for _, p := range m.Policies {
+		want := p.SDK()
+		if !slices.ContainsFunc(current.Policies, func(x Policy) bool {
+			return x.SDK() == want
+		}) {
+			steps = append(steps, createPolicy(want))
+		}
+	}
+
+	return steps, nil
+}
+
+// union returns a followed by the items of b it lacks, and whether there
+// were any.
+func union(a, b []string) ([]string, bool) {
+	merged := slices.Clone(a)
+	for _, s := range b {
+		if !slices.Contains(merged, s) {
+			merged = append(merged, s)
+		}
+	}
+
+	return merged, len(merged) > len(a)
+}
+
+// createResourceGroup creates rg, replacing old if it is set. Only groups
+// that did not exist before are recorded as created.
+func createResourceGroup(rg ResourceGroup, old *ResourceGroup) Step {
+	desc := i18n.Sprintf("create resource group %s", rg.Name)
+	if old != nil {
+		desc = i18n.Sprintf("update resource group %s", rg.Name)
+	}
+	desc += i18n.Sprintf(" with %s", strings.Join(rg.Endpoints, ", "))
+
+	return Step{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			prev := old
+			if prev == nil {
+				// A group created since the plan is recorded as updated,
+				// so a rollback does not delete it.
+				cur, err := c.GetResourceGroup(ctx, rg.Name)
+				switch {
+				case err == nil:
+					prev = &ResourceGroup{Name: cur.Name, Endpoints: cur.Endpoints}
+				case !errors.Is(err, enclave.ErrNotFound):
+					return err
+				}
+			}
+			created, err := c.CreateResourceGroup(ctx, rg.Name, rg.Endpoints)
+			if err != nil {
+				return err
+			}
+			if prev != nil {
+				history.RecordUpdate(
+					ctx,
+					history.KindResourceGroup,
+					enclave.ResourceGroup{Name: prev.Name, Endpoints: prev.Endpoints},
+					created,
+				)
+
+				return nil
+			}
+			history.RecordChange(
+				ctx,
+				history.KindResourceGroup,
+				history.ActionCreate,
+				created,
+			)
+
+			return nil
+		},
+	}
+}
+
+// createRole creates r, replacing old if it is set. Only roles that did not
+// exist before are recorded as created.
+func createRole(r Role, old *Role) Step {
+	desc := i18n.Sprintf("create role %s", r.Name)
+	if old != nil {
+		desc = i18n.Sprintf("update role %s", r.Name)
+	}
+	if len(r.Users) > 0 {
+		desc += i18n.Sprintf(" for %s", strings.Join(r.Users, ", "))
+	}
+
+	return Step{
+		Desc: desc,
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			prev := old
+			if prev == nil {
+				// A role created since the plan is recorded as updated, so
+				// a rollback does not delete it and its policies.
+				cur, err := c.GetRole(ctx, r.Name)
+				switch {
+				case err == nil:
+					prev = &Role{Name: cur.Name, Users: cur.Users}
+				case !errors.Is(err, enclave.ErrNotFound):
+					return err
+				}
+			}
+			created, err := c.CreateRole(ctx, r.Name, r.Users)
+			if err != nil {
+				return err
+			}
+			if prev != nil {
+				history.RecordUpdate(
+					ctx,
+					history.KindRole,
+					enclave.Role{Name: prev.Name, Users: prev.Users},
+					created,
+				)
+
+				return nil
+			}
+			history.RecordChange(
+				ctx,
+				history.KindRole,
+				history.ActionCreate,
+				created,
+			)
+
+			return nil
+		},
+	}
+}
+
+func createPolicy(p enclave.Policy) Step {
+	return Step{
+		Desc: i18n.Sprintf(
+			"create policy %s",
+			fmt.Sprintf("%s → %s (%s)", p.Role, p.ResourceGroup, p.Method),
+		),
+		Apply: func(ctx context.Context, c *enclave.Client) error {
+			if err := c.CreatePolicy(ctx, p); err != nil {
+				return err
+			}
+			history.RecordChange(ctx, history.KindPolicy, history.ActionCreate, p)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package rbac
+
+import (
+	"cli/internal/client"
+	"cli/internal/history"
+	"cli/internal/i18n"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// AddForceFlag registers --force, which deletes objects that are still
+// referenced.
+func AddForceFlag(cmd *cobra.Command) {
+	cmd.Flags().
+		Bool("force", false, "Delete even if other objects still reference it")
+}
+
+// CheckReferences lists what still references the named roles or resource
+// groups, depending on kind, on the command's error output. It fails when
+// there are references and --force is not set.
+func CheckReferences(cmd *cobra.Command, kind string, names []string) error {
+	m, err := Fetch(cmd.Context(), client.FromContext(cmd.Context()))
+	if err != nil {
+		return err
+	}
+
+	w := cmd.ErrOrStderr()
+	referenced := 0
+	for _, name := range names {
+		refs := m.references(kind, name)
+		if len(refs) == 0 {
+			continue
+		}
+		referenced++
+		_, _ = fmt.Fprintf(w, i18n.T("%s is still referenced by:\n"), name)
+		for _, ref := range refs {
+			_, _ = fmt.Fprintln(w, "  "+ref)
+		}
+	}
+	if force, _ := cmd.Flags().GetBool("force"); referenced == 0 || force {
+		return nil
+	}
+
+	return errors.New(i18n.Sprintf(
+		"%d of the given objects are still in use (use --force to delete anyway)",
+		referenced,
+	))
+}
+
+// references describes the users and policies in m that refer to the role
+// or resource group name.
+func (m *Model) references(kind, name string) []string {
+	var refs []string
+	if kind == history.KindRole {
+		for _, r := range m.Roles {
+			if r.Name != name {
+				continue
+			}
+			for _, u := range r.Users {
+				refs = append(refs, i18n.Sprintf("user %s", u))
+			}
+		}
+	}
+	for _, p := range m.Policies {
+		if kind == history.KindRole && p.Role == name ||
+			kind == history.KindResourceGroup && p.ResourceGroup == name {
+			refs = append(refs, i18n.Sprintf(
+				"policy %s",
+				fmt.Sprintf("%s → %s (%s)", p.Role, p.ResourceGroup, p.Method),
+			))
+		}
+	}
+
+	return refs
+}
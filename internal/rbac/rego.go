@@ -0,0 +1,64 @@
+package rbac
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// regoRules decides requests against the data written by WriteRego. Input
+// is {"user": ..., "method": ..., "path": ...}.
+const regoRules = `default allow := false
+
+allow if {
+	some p in policies
+	input.user in roles[p.role]
+	p.method in ["*", input.method]
+	some endpoint in resource_groups[p.resource_group]
+	glob.match(endpoint, [], input.path)
+}
+`
+
+// WriteRego writes m as a Rego module in package pkg with the RBAC objects as
+// data and an allow rule that mirrors the server's decision.
+func WriteRego(w io.Writer, m *Model, pkg string) error {
+	roles := map[string][]string{}
+	for _, r := range m.Roles {
+		roles[r.Name] = append([]string{}, r.Users...)
+	}
+	groups := map[string][]string{}
+	for _, rg := range m.ResourceGroups {
+		groups[rg.Name] = append([]string{}, rg.Endpoints...)
+	}
+	policies := m.Policies
+	if policies == nil {
+		policies = []Policy{}
+	}
+
+	if _, err := fmt.Fprintf(
+		w,
+		"package %s\n\nimport rego.v1\n\n",
+		pkg,
+	); err != nil {
+		return err
+	}
+	for _, def := range []struct {
+		name  string
+		value any
+	}{
+		{"roles", roles},
+		{"resource_groups", groups},
+		{"policies", policies},
+	} {
+		data, err := json.MarshalIndent(def.value, "", "\t")
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", def.name, err)
+		}
+		if _, err := fmt.Fprintf(w, "%s := %s\n\n", def.name, data); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, regoRules)
+
+	return err
+}
@@ -0,0 +1,103 @@
+package rbac
+
+import (
+	"cli/internal/history"
+	"cli/internal/i18n"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/spf13/cobra"
+)
+
+// AddAtomicFlag registers --atomic, which makes a failed run revert the
+// changes it already made.
+func AddAtomicFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(
+		"atomic",
+		false,
+		"Revert the changes already made if a later one fails",
+	)
+}
+
+// Atomic reports whether --atomic is set on cmd.
+func Atomic(cmd *cobra.Command) bool {
+	atomic, _ := cmd.Flags().GetBool("atomic")
+
+	return atomic
+}
+
+// Recorded returns the number of changes recorded under ctx so far, to be
+// passed to Rollback.
+func Recorded(ctx context.Context) int {
+	changes, _ := history.Changes(ctx)
+
+	return len(changes)
+}
+
+// Rollback reverses the changes recorded under ctx after the first since,
+// newest first, listing each on out. It returns cause joined with any
+// errors the rollback ran into.
+func Rollback(
+	ctx context.Context,
+	c *enclave.Client,
+	out io.Writer,
+	since int,
+	cause error,
+) error {
+	steps, err := inverses(ctx, since)
+	if err != nil {
+		return errors.Join(cause, fmt.Errorf("rollback: %w", err))
+	}
+	slices.Reverse(steps)
+
+	return rollback(ctx, c, out, steps, cause)
+}
+
+// inverses returns the steps reversing the changes recorded under ctx after
+// the first since, in recording order.
+func inverses(ctx context.Context, since int) ([]Step, error) {
+	changes, _ := history.Changes(ctx)
+	steps := make([]Step, 0, len(changes)-since)
+	for i := range changes[since:] {
+		step, err := Inverse(&changes[since+i])
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// rollback applies steps and reports the outcome on out.
+func rollback(
+	ctx context.Context,
+	c *enclave.Client,
+	out io.Writer,
+	steps []Step,
+	cause error,
+) error {
+	if len(steps) == 0 {
+		return cause
+	}
+	// The failure may stem from a canceled context; the rollback must still
+	// run.
+	ctx = context.WithoutCancel(ctx)
+	_, _ = fmt.Fprintln(out, i18n.T("Rolling back:"))
+	errs := []error{cause}
+	for _, s := range steps {
+		_, _ = fmt.Fprintln(out, "  "+s.Desc)
+		if err := s.Apply(ctx, c); err != nil {
+			errs = append(errs, fmt.Errorf("rollback: %s: %w", s.Desc, err))
+		}
+	}
+	if len(errs) == 1 {
+		return fmt.Errorf("%w (changes rolled back)", cause)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,100 @@
+// Package recent tracks the artifacts used most recently from this machine
+// in ~/.enclave/recent.json, for encl artifact recent and shell completion.
+package recent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// maxEntries bounds the number of remembered artifacts.
+const maxEntries = 50
+
+// Entry is a recently used artifact.
+type Entry struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Ref is the tag or version hash last used.
+	Ref string `json:"ref"`
+	// Action is the command that used it, e.g. "download".
+	Action string    `json:"action"`
+	Time   time.Time `json:"time"`
+}
+
+// Path returns the state file, ~/.enclave/recent.json.
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locate home dir: %w", err)
+	}
+
+	return filepath.Join(home, ".enclave", "recent.json"), nil
+}
+
+// Touch records that an artifact was used, moving it to the front.
+func Touch(namespace, name, ref, action string) error {
+	entries, err := Read()
+	if err != nil {
+		return err
+	}
+	entries = slices.DeleteFunc(entries, func(e Entry) bool {
+		return e.Namespace == namespace && e.Name == name
+	})
+	entries = slices.Insert(entries, 0, Entry{
+		Namespace: namespace,
+		Name:      name,
+		Ref:       ref,
+		Action:    action,
+		Time:      time.Now(),
+	})
+	if len(entries) > maxEntries {
+		entries = entries[:maxEntries]
+	}
+
+	return write(entries)
+}
+
+// Read returns the recently used artifacts, most recent first.
+func Read() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path) // #nosec G304 -- path is under the home dir
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read recent artifacts: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+func write(entries []Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode recent artifacts: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create state dir: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("write recent artifacts: %w", err)
+	}
+
+	return nil
+}
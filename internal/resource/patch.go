@@ -0,0 +1,162 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Patch types accepted by Patch.
+const (
+	// PatchMerge is a JSON merge patch (RFC 7386): objects are merged,
+	// null removes a field, and lists are replaced.
+	PatchMerge = "merge"
+	// PatchStrategic merges lists instead of replacing them. Entries are
+	// removed from a list with "$deleteFromPrimitiveList/<field>".
+	PatchStrategic = "strategic"
+)
+
+// PatchTypes lists the patch types accepted by Patch.
+var PatchTypes = []string{PatchMerge, PatchStrategic}
+
+// deletePrefix marks the keys of strategic patches that remove list
+// entries.
+const deletePrefix = "$deleteFromPrimitiveList/"
+
+// Patch applies the JSON patch of type patchType to doc and returns the
+// patched document, decoded and validated like an edited one.
+func (k *Kind) Patch(doc any, patch []byte, patchType string) (any, error) {
+	var p map[string]any
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("parse patch: %w", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", k.Name, err)
+	}
+	var target map[string]any
+	if err := json.Unmarshal(data, &target); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", k.Name, err)
+	}
+
+	var patched any
+	switch patchType {
+	case PatchMerge:
+		patched = mergePatch(target, p)
+	case PatchStrategic:
+		if patched, err = strategicPatch(target, p); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(
+			"unknown patch type %q (available: %s)",
+			patchType,
+			strings.Join(PatchTypes, ", "),
+		)
+	}
+
+	if data, err = json.Marshal(patched); err != nil {
+		return nil, fmt.Errorf("encode %s: %w", k.Name, err)
+	}
+
+	return k.Decode(data)
+}
+
+// mergePatch applies a JSON merge patch to target.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for key, v := range p {
+		if v == nil {
+			delete(t, key)
+
+			continue
+		}
+		t[key] = mergePatch(t[key], v)
+	}
+
+	return t
+}
+
+// strategicPatch applies patch to target like mergePatch, but adds the
+// entries of patched lists to the existing ones and removes the entries
+// listed under deletePrefix keys.
+func strategicPatch(target, patch any) (any, error) {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch, nil
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+
+	var deletes []string
+	for key, v := range p {
+		if strings.HasPrefix(key, deletePrefix) {
+			deletes = append(deletes, key)
+
+			continue
+		}
+		if v == nil {
+			delete(t, key)
+
+			continue
+		}
+		list, isList := v.([]any)
+		existing, hasList := t[key].([]any)
+		if isList && hasList {
+			for _, e := range list {
+				if !contains(existing, e) {
+					existing = append(existing, e)
+				}
+			}
+			t[key] = existing
+
+			continue
+		}
+		merged, err := strategicPatch(t[key], v)
+		if err != nil {
+			return nil, err
+		}
+		t[key] = merged
+	}
+
+	// Removals apply after additions, so a patch may replace entries.
+	for _, key := range deletes {
+		field := strings.TrimPrefix(key, deletePrefix)
+		remove, ok := p[key].([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s: want a list of entries to remove", key)
+		}
+		existing, ok := t[field].([]any)
+		if !ok {
+			if _, found := t[field]; found {
+				return nil, fmt.Errorf("%s is not a list", field)
+			}
+
+			continue
+		}
+		t[field] = slices.DeleteFunc(existing, func(e any) bool {
+			return contains(remove, e)
+		})
+	}
+
+	return t, nil
+}
+
+// contains reports whether list holds a value equal to v. Unlike
+// slices.Contains it accepts the objects and lists JSON values may hold.
+func contains(list []any, v any) bool {
+	return slices.ContainsFunc(list, func(e any) bool {
+		return reflect.DeepEqual(e, v)
+	})
+}
@@ -0,0 +1,315 @@
+// Package resource describes server objects as documents that are changed
+// as a whole, for encl edit and encl patch.
+package resource
+
+import (
+	"bytes"
+	"cli/internal/history"
+	"cli/internal/output"
+	"cli/internal/picker"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is a type of server object that can be changed as a document.
+type Kind struct {
+	Name    string
+	Aliases []string
+	// Columns render the objects Update returns.
+	Columns []output.Column
+	// Source lists the objects of the kind, for suggestions when a name is
+	// not found.
+	Source func(c *enclave.Client) picker.Source
+
+	get    func(ctx context.Context, c *enclave.Client, name string) (any, error)
+	decode func(data []byte) (any, error)
+	update func(ctx context.Context, c *enclave.Client, old, doc any) (any, error)
+}
+
+// User is the document form of a user.
+type User struct {
+	Name        string   `json:"name"        yaml:"name"`
+	DisplayName string   `json:"displayName" yaml:"displayName"`
+	Roles       []string `json:"roles"       yaml:"roles"`
+}
+
+// Role is the document form of a role and the users bound to it.
+type Role struct {
+	Name  string   `json:"name"  yaml:"name"`
+	Users []string `json:"users" yaml:"users"`
+}
+
+// ResourceGroup is the document form of a resource group.
+type ResourceGroup struct {
+	Name      string   `json:"name"      yaml:"name"`
+	Endpoints []string `json:"endpoints" yaml:"endpoints"`
+}
+
+// Kinds lists the kinds of objects that can be changed as documents.
+var Kinds = []*Kind{
+	define(
+		"user",
+		[]string{"users"},
+		output.UserColumns,
+		picker.Users,
+		getUser,
+		updateUser,
+	),
+	define(
+		"role",
+		[]string{"roles"},
+		output.RoleColumns,
+		picker.Roles,
+		getRole,
+		updateRole,
+	),
+	define(
+		"resource-group",
+		[]string{"resource-groups", "rg"},
+		output.ResourceGroupColumns,
+		picker.ResourceGroups,
+		getResourceGroup,
+		updateResourceGroup,
+	),
+}
+
+// Lookup returns the kind called name or one of its aliases.
+func Lookup(name string) (*Kind, error) {
+	for _, k := range Kinds {
+		if k.Name == name || slices.Contains(k.Aliases, name) {
+			return k, nil
+		}
+	}
+	names := make([]string, len(Kinds))
+	for i, k := range Kinds {
+		names[i] = k.Name
+	}
+
+	return nil, fmt.Errorf(
+		"unknown resource %q (available: %s)",
+		name,
+		strings.Join(names, ", "),
+	)
+}
+
+// Get fetches the named object as a document.
+func (k *Kind) Get(
+	ctx context.Context,
+	c *enclave.Client,
+	name string,
+) (any, error) {
+	doc, err := k.get(ctx, c, name)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", k.Name, err)
+	}
+
+	return doc, nil
+}
+
+// Decode parses a YAML or JSON document of the kind. Unknown fields are
+// rejected, so typos do not silently drop changes.
+func (k *Kind) Decode(data []byte) (any, error) {
+	doc, err := k.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", k.Name, err)
+	}
+
+	return doc, nil
+}
+
+// Update changes the object old describes to match doc and returns the
+// updated object. Names cannot be changed.
+func (k *Kind) Update(
+	ctx context.Context,
+	c *enclave.Client,
+	old, doc any,
+) (any, error) {
+	obj, err := k.update(ctx, c, old, doc)
+	if err != nil {
+		return nil, fmt.Errorf("update %s: %w", k.Name, err)
+	}
+
+	return obj, nil
+}
+
+// define builds a kind whose documents are of type T.
+func define[T any](
+	name string,
+	aliases []string,
+	columns []output.Column,
+	source func(c *enclave.Client) picker.Source,
+	get func(ctx context.Context, c *enclave.Client, name string) (*T, error),
+	update func(ctx context.Context, c *enclave.Client, old, doc *T) (any, error),
+) *Kind {
+	return &Kind{
+		Name:    name,
+		Aliases: aliases,
+		Columns: columns,
+		Source:  source,
+		get: func(ctx context.Context, c *enclave.Client, name string) (any, error) {
+			return get(ctx, c, name)
+		},
+		decode: func(data []byte) (any, error) {
+			doc := new(T)
+			dec := yaml.NewDecoder(bytes.NewReader(data))
+			dec.KnownFields(true)
+			if err := dec.Decode(doc); err != nil {
+				return nil, err
+			}
+
+			return doc, nil
+		},
+		update: func(
+			ctx context.Context,
+			c *enclave.Client,
+			old, doc any,
+		) (any, error) {
+			o, ok := old.(*T)
+			d, ok2 := doc.(*T)
+			if !ok || !ok2 {
+				return nil, errors.New("document of the wrong kind")
+			}
+
+			return update(ctx, c, o, d)
+		},
+	}
+}
+
+// checkName returns an error unless the document kept its name.
+func checkName(old, name string) error {
+	if name != old {
+		return fmt.Errorf("name cannot be changed (%q to %q)", old, name)
+	}
+
+	return nil
+}
+
+func getUser(
+	ctx context.Context,
+	c *enclave.Client,
+	name string,
+) (*User, error) {
+	u, err := c.GetUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &User{
+		Name:        u.Name,
+		DisplayName: u.DisplayName,
+		Roles:       nonNil(u.Roles),
+	}, nil
+}
+
+func updateUser(
+	ctx context.Context,
+	c *enclave.Client,
+	old, doc *User,
+) (any, error) {
+	if err := checkName(old.Name, doc.Name); err != nil {
+		return nil, err
+	}
+	var opts []enclave.UpdateUserOption
+	if doc.DisplayName != old.DisplayName {
+		opts = append(opts, enclave.WithDisplayName(doc.DisplayName))
+	}
+	if !sameSet(doc.Roles, old.Roles) {
+		opts = append(opts, enclave.WithUserRoles(nonNil(doc.Roles)...))
+	}
+
+	return c.UpdateUser(ctx, old.Name, opts...)
+}
+
+func getRole(
+	ctx context.Context,
+	c *enclave.Client,
+	name string,
+) (*Role, error) {
+	r, err := c.GetRole(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Role{Name: r.Name, Users: nonNil(r.Users)}, nil
+}
+
+func updateRole(
+	ctx context.Context,
+	c *enclave.Client,
+	old, doc *Role,
+) (any, error) {
+	if err := checkName(old.Name, doc.Name); err != nil {
+		return nil, err
+	}
+	r, err := c.CreateRole(ctx, old.Name, nonNil(doc.Users))
+	if err != nil {
+		return nil, err
+	}
+	history.RecordUpdate(
+		ctx,
+		history.KindRole,
+		enclave.Role{Name: old.Name, Users: old.Users},
+		r,
+	)
+
+	return r, nil
+}
+
+func getResourceGroup(
+	ctx context.Context,
+	c *enclave.Client,
+	name string,
+) (*ResourceGroup, error) {
+	rg, err := c.GetResourceGroup(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourceGroup{Name: rg.Name, Endpoints: nonNil(rg.Endpoints)}, nil
+}
+
+func updateResourceGroup(
+	ctx context.Context,
+	c *enclave.Client,
+	old, doc *ResourceGroup,
+) (any, error) {
+	if err := checkName(old.Name, doc.Name); err != nil {
+		return nil, err
+	}
+	rg, err := c.CreateResourceGroup(ctx, old.Name, nonNil(doc.Endpoints))
+	if err != nil {
+		return nil, err
+	}
+	history.RecordUpdate(
+		ctx,
+		history.KindResourceGroup,
+		enclave.ResourceGroup{Name: old.Name, Endpoints: old.Endpoints},
+		rg,
+	)
+
+	return rg, nil
+}
+
+// nonNil returns s, or an empty slice if s is nil, so documents show empty
+// lists as [] rather than null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+
+	return s
+}
+
+// sameSet reports whether a and b hold the same strings, in any order.
+func sameSet(a, b []string) bool {
+	return slices.Equal(
+		slices.Sorted(slices.Values(a)),
+		slices.Sorted(slices.Values(b)),
+	)
+}
@@ -4,6 +4,7 @@ import "charm.land/lipgloss/v2"
 
 // Enclave brand colors derived from the website CSS and logo SVG.
 // In lipgloss v2, colors are interface values so they must be vars.
+// They hold the default dark theme until Apply selects another one.
 var (
 	ColorPrimaryGreen   = lipgloss.Color("#b5d055")
 	ColorSecondaryGreen = lipgloss.Color("#98b04a")
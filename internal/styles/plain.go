@@ -0,0 +1,102 @@
+package styles
+
+import (
+	"os"
+
+	"charm.land/lipgloss/v2"
+)
+
+// Plain reports whether plain output is active: no colors, no box drawing,
+// and no animated progress. Set it with SetPlain.
+var Plain bool
+
+// Box-drawing characters used by the TUI. SetPlain replaces them with ASCII.
+var (
+	BoxVertical   = "│"
+	BoxHorizontal = "─"
+)
+
+// SetPlain switches all styles to plain text, for screen readers and dumb
+// terminals. It stays in effect when a theme is applied afterwards.
+func SetPlain() {
+	Plain = true
+	BoxVertical = "|"
+	BoxHorizontal = "-"
+	buildStyles()
+}
+
+// PanelBorder returns the border drawn around TUI panels and dialogs.
+func PanelBorder() lipgloss.Border {
+	if Plain {
+		return lipgloss.ASCIIBorder()
+	}
+
+	return lipgloss.RoundedBorder()
+}
+
+// buildPlainStyles resets every style to unstyled text. Paddings are kept so
+// layouts still line up.
+func buildPlainStyles() {
+	HeaderStyle = lipgloss.NewStyle().Padding(0, 1)
+	SelectedRowStyle = lipgloss.NewStyle().Reverse(true)
+	MutedStyle = lipgloss.NewStyle()
+	TitleStyle = lipgloss.NewStyle()
+	StatusBarStyle = lipgloss.NewStyle().Padding(0, 1)
+	StatusBarHighlight = lipgloss.NewStyle().Padding(0, 1)
+	HelpBarStyle = lipgloss.NewStyle().Padding(0, 1)
+	HelpKeyStyle = lipgloss.NewStyle()
+	ErrorStyle = lipgloss.NewStyle()
+	BorderStyle = lipgloss.NewStyle().Border(PanelBorder())
+	MatchStyle = lipgloss.NewStyle().Reverse(true)
+	DiffAddedStyle = lipgloss.NewStyle()
+	DiffRemovedStyle = lipgloss.NewStyle()
+	DiffHunkStyle = lipgloss.NewStyle()
+}
+
+// PlainFile wraps a terminal so that color and text attribute sequences are
+// dropped from everything written to it, while cursor movement still works.
+// It is used to render the TUI in plain mode.
+type PlainFile struct {
+	*os.File
+
+	// pending holds an escape sequence split across writes.
+	pending []byte
+}
+
+// Write strips SGR sequences (ESC [ ... m) from p before writing it.
+func (f *PlainFile) Write(p []byte) (int, error) {
+	buf := p
+	if len(f.pending) > 0 {
+		f.pending = append(f.pending, p...)
+		buf, f.pending = f.pending, nil
+	}
+
+	out := make([]byte, 0, len(buf))
+	for i := 0; i < len(buf); i++ {
+		if buf[i] != '\x1b' || i+1 < len(buf) && buf[i+1] != '[' {
+			out = append(out, buf[i])
+
+			continue
+		}
+		// Find the final byte of the control sequence.
+		j := i + 2
+		for j < len(buf) && (buf[j] < 0x40 || buf[j] > 0x7e) {
+			j++
+		}
+		if j >= len(buf) {
+			f.pending = append([]byte(nil), buf[i:]...)
+
+			break
+		}
+		if buf[j] != 'm' {
+			out = append(out, buf[i:j+1]...)
+		}
+		i = j
+	}
+
+	if _, err := f.File.Write(out); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
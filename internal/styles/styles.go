@@ -4,61 +4,104 @@ import "charm.land/lipgloss/v2"
 
 var (
 	// HeaderStyle is used for table column headers.
-	HeaderStyle = lipgloss.NewStyle().
-			Foreground(ColorNearBlack).
-			Background(ColorPrimaryGreen).
-			Bold(true).
-			Padding(0, 1)
+	HeaderStyle lipgloss.Style
 
 	// SelectedRowStyle highlights the cursor row in TUI tables.
-	SelectedRowStyle = lipgloss.NewStyle().
-				Foreground(ColorNearBlack).
-				Background(ColorSecondaryGreen)
+	SelectedRowStyle lipgloss.Style
 
 	// MutedStyle renders secondary/contextual text.
-	MutedStyle = lipgloss.NewStyle().
-			Foreground(ColorSlateDark)
+	MutedStyle lipgloss.Style
 
 	// TitleStyle is used for view titles in the TUI.
-	TitleStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimaryGreen).
-			Bold(true)
+	TitleStyle lipgloss.Style
 
 	// StatusBarStyle is the top status bar background.
-	StatusBarStyle = lipgloss.NewStyle().
-			Foreground(ColorNearBlack).
-			Background(ColorDarkestGreen).
-			Padding(0, 1)
+	StatusBarStyle lipgloss.Style
 
 	// StatusBarHighlight is used for active view name in the status bar.
-	StatusBarHighlight = lipgloss.NewStyle().
-				Foreground(ColorNearBlack).
-				Background(ColorPrimaryGreen).
-				Bold(true).
-				Padding(0, 1)
+	StatusBarHighlight lipgloss.Style
 
 	// HelpBarStyle is the bottom help bar.
-	HelpBarStyle = lipgloss.NewStyle().
-			Foreground(ColorSlateDark).
-			Background(ColorNearBlack).
-			Padding(0, 1)
+	HelpBarStyle lipgloss.Style
 
 	// HelpKeyStyle highlights keybinding keys.
-	HelpKeyStyle = lipgloss.NewStyle().
-			Foreground(ColorPrimaryGreen)
+	HelpKeyStyle lipgloss.Style
 
 	// ErrorStyle renders error messages.
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorWarmHighlight)
+	ErrorStyle lipgloss.Style
 
 	// BorderStyle is used for panel borders.
-	BorderStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorDarkGreen)
+	BorderStyle lipgloss.Style
+
+	// MatchStyle marks search matches in log messages.
+	MatchStyle lipgloss.Style
+
+	// DiffAddedStyle and DiffRemovedStyle render the added and removed
+	// lines of unified diffs, DiffHunkStyle their hunk headers.
+	DiffAddedStyle   lipgloss.Style
+	DiffRemovedStyle lipgloss.Style
+	DiffHunkStyle    lipgloss.Style
 )
 
+func init() {
+	buildStyles()
+}
+
+// buildStyles derives all styles from the current palette.
+func buildStyles() {
+	if Plain {
+		buildPlainStyles()
+
+		return
+	}
+	HeaderStyle = lipgloss.NewStyle().
+		Foreground(ColorNearBlack).
+		Background(ColorPrimaryGreen).
+		Bold(true).
+		Padding(0, 1)
+	SelectedRowStyle = lipgloss.NewStyle().
+		Foreground(ColorNearBlack).
+		Background(ColorSecondaryGreen)
+	MutedStyle = lipgloss.NewStyle().
+		Foreground(ColorSlateDark)
+	TitleStyle = lipgloss.NewStyle().
+		Foreground(ColorPrimaryGreen).
+		Bold(true)
+	StatusBarStyle = lipgloss.NewStyle().
+		Foreground(ColorNearBlack).
+		Background(ColorDarkestGreen).
+		Padding(0, 1)
+	StatusBarHighlight = lipgloss.NewStyle().
+		Foreground(ColorNearBlack).
+		Background(ColorPrimaryGreen).
+		Bold(true).
+		Padding(0, 1)
+	HelpBarStyle = lipgloss.NewStyle().
+		Foreground(ColorSlateDark).
+		Background(ColorNearBlack).
+		Padding(0, 1)
+	HelpKeyStyle = lipgloss.NewStyle().
+		Foreground(ColorPrimaryGreen)
+	ErrorStyle = lipgloss.NewStyle().
+		Foreground(ColorWarmHighlight)
+	BorderStyle = lipgloss.NewStyle().
+		Border(PanelBorder()).
+		BorderForeground(ColorDarkGreen)
+	MatchStyle = lipgloss.NewStyle().
+		Foreground(ColorNearBlack).
+		Background(ColorWarmHighlight)
+	// Diffs use the basic ANSI colors, like git, so additions and removals
+	// read the same in every theme.
+	DiffAddedStyle = lipgloss.NewStyle().Foreground(lipgloss.Green)
+	DiffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Red)
+	DiffHunkStyle = lipgloss.NewStyle().Foreground(ColorLogoTeal)
+}
+
 // TaskStateBadge returns a coloured badge string for the given task state.
 func TaskStateBadge(state string) string {
+	if Plain {
+		return state
+	}
 	switch state {
 	case "running", "processing":
 		return lipgloss.NewStyle().
@@ -0,0 +1,146 @@
+package styles
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+)
+
+// Theme assigns a color to each palette role. The roles keep their brand
+// names in the Color* vars, so in non-default themes ColorPrimaryGreen is
+// simply "the primary color".
+type Theme struct {
+	Primary   color.Color // ColorPrimaryGreen: headers, highlights
+	Secondary color.Color // ColorSecondaryGreen: selected rows
+	Dark      color.Color // ColorDarkGreen: borders, separators
+	Darkest   color.Color // ColorDarkestGreen: status bar background
+	Subtle    color.Color // ColorSlateLight: secondary text
+	Muted     color.Color // ColorSlateDark: hints, empty states
+	Contrast  color.Color // ColorNearBlack: text on colored backgrounds
+	Warning   color.Color // ColorWarmHighlight: errors, failures
+	Accent    color.Color // ColorLogoTeal: completed states
+	Text      color.Color // ColorWhite: primary text
+}
+
+// ThemeNames lists the built-in themes accepted by Apply, besides "auto".
+var ThemeNames = []string{"dark", "light", "high-contrast"}
+
+var themes = map[string]Theme{
+	"dark": {
+		Primary:   lipgloss.Color("#b5d055"),
+		Secondary: lipgloss.Color("#98b04a"),
+		Dark:      lipgloss.Color("#7d8f3f"),
+		Darkest:   lipgloss.Color("#5a6b28"),
+		Subtle:    lipgloss.Color("#94a3b8"),
+		Muted:     lipgloss.Color("#64748b"),
+		Contrast:  lipgloss.Color("#202020"),
+		Warning:   lipgloss.Color("#E9B57B"),
+		Accent:    lipgloss.Color("#AFCDD1"),
+		Text:      lipgloss.Color("#f8fafc"),
+	},
+	// The light theme darkens every foreground color so text stays readable
+	// on white backgrounds.
+	"light": {
+		Primary:   lipgloss.Color("#98b04a"),
+		Secondary: lipgloss.Color("#b5d055"),
+		Dark:      lipgloss.Color("#5a6b28"),
+		Darkest:   lipgloss.Color("#b5d055"),
+		Subtle:    lipgloss.Color("#475569"),
+		Muted:     lipgloss.Color("#64748b"),
+		Contrast:  lipgloss.Color("#202020"),
+		Warning:   lipgloss.Color("#b45309"),
+		Accent:    lipgloss.Color("#0f766e"),
+		Text:      lipgloss.Color("#0f172a"),
+	},
+	// The high-contrast theme only uses the basic ANSI colors so it renders
+	// predictably on any terminal and color scheme.
+	"high-contrast": {
+		Primary:   lipgloss.Yellow,
+		Secondary: lipgloss.BrightCyan,
+		Dark:      lipgloss.BrightWhite,
+		Darkest:   lipgloss.BrightWhite,
+		Subtle:    lipgloss.BrightWhite,
+		Muted:     lipgloss.White,
+		Contrast:  lipgloss.Black,
+		Warning:   lipgloss.BrightRed,
+		Accent:    lipgloss.BrightCyan,
+		Text:      lipgloss.BrightWhite,
+	},
+}
+
+// Apply activates the named theme and rebuilds all styles. "auto" (or an
+// empty name) picks the dark or light theme based on darkBackground.
+// overrides maps role names (e.g. "primary", "warning") to colors.
+func Apply(
+	name string,
+	darkBackground bool,
+	overrides map[string]string,
+) error {
+	switch name {
+	case "", "auto":
+		name = "light"
+		if darkBackground {
+			name = "dark"
+		}
+	}
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf(
+			"unknown theme %q (available: auto, %s)",
+			name,
+			strings.Join(ThemeNames, ", "),
+		)
+	}
+
+	roles := t.roles()
+	for role, value := range overrides {
+		c, ok := roles[strings.ToLower(role)]
+		if !ok {
+			keys := make([]string, 0, len(roles))
+			for k := range roles {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+
+			return fmt.Errorf(
+				"unknown theme color %q (available: %s)",
+				role,
+				strings.Join(keys, ", "),
+			)
+		}
+		*c = lipgloss.Color(value)
+	}
+
+	ColorPrimaryGreen = t.Primary
+	ColorSecondaryGreen = t.Secondary
+	ColorDarkGreen = t.Dark
+	ColorDarkestGreen = t.Darkest
+	ColorSlateLight = t.Subtle
+	ColorSlateDark = t.Muted
+	ColorNearBlack = t.Contrast
+	ColorWarmHighlight = t.Warning
+	ColorLogoTeal = t.Accent
+	ColorWhite = t.Text
+	buildStyles()
+
+	return nil
+}
+
+// roles maps the override keys to the theme fields.
+func (t *Theme) roles() map[string]*color.Color {
+	return map[string]*color.Color{
+		"primary":   &t.Primary,
+		"secondary": &t.Secondary,
+		"dark":      &t.Dark,
+		"darkest":   &t.Darkest,
+		"subtle":    &t.Subtle,
+		"muted":     &t.Muted,
+		"contrast":  &t.Contrast,
+		"warning":   &t.Warning,
+		"accent":    &t.Accent,
+		"text":      &t.Text,
+	}
+}
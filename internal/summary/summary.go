@@ -0,0 +1,130 @@
+// Package summary writes a machine-readable record of what a command did,
+// for --summary-file.
+package summary
+
+import (
+	"cli/internal/client"
+	"cli/internal/history"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Summary is the record written to the summary file.
+type Summary struct {
+	// Transfer counts the API requests and the bytes sent and received.
+	client.Transfer
+
+	Command  string        `json:"command"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	ExitCode int           `json:"exitCode"`
+	// Error is the error message; empty when the command succeeded.
+	Error string `json:"error,omitempty"`
+	// Created, Updated, and Deleted list the objects the command changed,
+	// as recorded in the history.
+	Created []history.Change `json:"created"`
+	Updated []history.Change `json:"updated"`
+	Deleted []history.Change `json:"deleted"`
+	// Warnings holds the messages logged at warn level.
+	Warnings []string `json:"warnings"`
+}
+
+// New returns the summary of a command that ran from started until now,
+// made the given changes, and ended with exitCode and err.
+func New(
+	command string,
+	started time.Time,
+	changes []history.Change,
+	exitCode int,
+	err error,
+) *Summary {
+	s := &Summary{
+		Command:  command,
+		Started:  started,
+		Duration: time.Since(started),
+		ExitCode: exitCode,
+		Created:  []history.Change{},
+		Updated:  []history.Change{},
+		Deleted:  []history.Change{},
+		Transfer: client.Stats(),
+		Warnings: Warnings(),
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	for _, ch := range changes {
+		switch ch.Action {
+		case history.ActionCreate:
+			s.Created = append(s.Created, ch)
+		case history.ActionUpdate:
+			s.Updated = append(s.Updated, ch)
+		case history.ActionDelete:
+			s.Deleted = append(s.Deleted, ch)
+		}
+	}
+
+	return s
+}
+
+// Write stores s as JSON at path. The file only replaces path once it is
+// complete.
+func Write(path string, s *Summary) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode summary: %w", err)
+	}
+	tmp, err := os.CreateTemp(
+		filepath.Dir(path),
+		"."+filepath.Base(path)+".*.tmp",
+	)
+	if err != nil {
+		return fmt.Errorf("create summary file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write summary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write summary file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write summary file: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	mu       sync.Mutex
+	warnings = []string{}
+)
+
+// Hook collects warnings logged through zerolog for the summary.
+type Hook struct{}
+
+// Run implements zerolog.Hook.
+func (Hook) Run(_ *zerolog.Event, level zerolog.Level, msg string) {
+	if level != zerolog.WarnLevel {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	warnings = append(warnings, msg)
+}
+
+// Warnings returns the warnings logged so far.
+func Warnings() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	return append([]string{}, warnings...)
+}
@@ -0,0 +1,217 @@
+// Package telemetry records opt-in, anonymous usage metrics: which commands
+// are run and how they fail. Arguments, names, and error messages are never
+// recorded.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"cli/internal/client"
+	"cli/internal/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/EnclaveRunner/sdk-go/enclave"
+)
+
+const (
+	// flushBatch is how many events are collected before they are sent.
+	flushBatch = 20
+	// maxSpooled caps the local spool when no endpoint is configured or it
+	// stays unreachable.
+	maxSpooled = 1000
+	// sendTimeout bounds sending so an offline machine is not held up.
+	sendTimeout = 2 * time.Second
+)
+
+// Event is a single recorded command invocation.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Version string    `json:"version"`
+	OS      string    `json:"os"`
+	Arch    string    `json:"arch"`
+	// Error is the class of error the command failed with, e.g. "api_404"
+	// or "network". Empty on success.
+	Error string `json:"error,omitempty"`
+}
+
+// Record appends an event for command to the local spool and sends the
+// spool to cfg.TelemetryEndpoint once enough events are collected. It does
+// nothing unless telemetry is enabled. All errors are ignored.
+func Record(
+	ctx context.Context,
+	cfg *config.Config,
+	command, version string,
+	err error,
+) {
+	if cfg == nil || !cfg.TelemetryEnabled() {
+		return
+	}
+	path, pathErr := SpoolPath()
+	if pathErr != nil {
+		return
+	}
+
+	n, _ := Pending()
+	if n < maxSpooled {
+		if appendEvent(path, &Event{
+			Time:    time.Now().UTC().Truncate(time.Hour),
+			Command: command,
+			Version: version,
+			OS:      runtime.GOOS,
+			Arch:    runtime.GOARCH,
+			Error:   ErrorClass(err),
+		}) == nil {
+			n++
+		}
+	}
+
+	if cfg.TelemetryEndpoint != "" && n >= flushBatch {
+		if send(ctx, cfg.TelemetryEndpoint) == nil {
+			_ = Clear()
+		}
+	}
+}
+
+// SpoolPath returns the file events are collected in before they are sent,
+// e.g. ~/.cache/enclave/telemetry.jsonl.
+func SpoolPath() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate cache dir: %w", err)
+	}
+
+	return filepath.Join(base, "enclave", "telemetry.jsonl"), nil
+}
+
+// Pending returns how many events are waiting to be sent.
+func Pending() (int, error) {
+	events, err := readSpool()
+
+	return len(events), err
+}
+
+// Clear discards all events that were not sent yet.
+func Clear() error {
+	path, err := SpoolPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove telemetry spool: %w", err)
+	}
+
+	return nil
+}
+
+// ErrorClass reduces err to a coarse class that carries no user data.
+func ErrorClass(err error) string {
+	var apiErr *enclave.APIError
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("api_%d", apiErr.StatusCode)
+	case errors.As(err, &netErr):
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+func appendEvent(path string, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile( // #nosec G304 -- path is under the cache dir
+		path,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
+		0o600,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+
+	return errors.Join(err, f.Close())
+}
+
+func readSpool() ([]Event, error) {
+	path, err := SpoolPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path) // #nosec G304 -- path is under the cache dir
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open telemetry spool: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+
+	return events, scanner.Err()
+}
+
+// send posts the spooled events to endpoint as a JSON array.
+func send(ctx context.Context, endpoint string) error {
+	events, err := readSpool()
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		endpoint,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.StockClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	return nil
+}
@@ -16,6 +16,8 @@ import (
 const (
 	minWidth  = 80
 	minHeight = 20
+
+	keyCtrlC = "ctrl+c"
 )
 
 // AppModel is the root Bubbletea model for the TUI.
@@ -213,7 +215,7 @@ func (m AppModel) Update(
 
 	case tea.KeyMsg:
 		// Always allow quit.
-		if msg.String() == "ctrl+c" {
+		if msg.String() == keyCtrlC {
 			return m, tea.Quit
 		}
 
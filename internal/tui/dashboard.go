@@ -0,0 +1,351 @@
+package tui
+
+import (
+	"cli/internal/styles"
+	"context"
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recentLimit is how many recent uploads and failed tasks are listed.
+const recentLimit = 5
+
+// snapshot is one refresh of the dashboard data. Each section carries its
+// own error so an unreachable endpoint does not blank the whole screen.
+type snapshot struct {
+	at time.Time
+
+	latency   time.Duration
+	healthErr error
+
+	users          int
+	roles          int
+	resourceGroups int
+	policies       int
+	rbacErr        error
+
+	namespaces  int
+	artifacts   int
+	recent      []enclave.Artifact
+	artifactErr error
+
+	taskStates map[string]int
+	failed     []enclave.Task
+	taskErr    error
+}
+
+// snapshotMsg carries a completed refresh.
+type snapshotMsg struct{ snap snapshot }
+
+// refreshTickMsg triggers the next refresh.
+type refreshTickMsg struct{}
+
+// dashboardModel is the live platform overview.
+type dashboardModel struct {
+	ctx      context.Context //nolint:containedctx // cancels in-flight loads
+	client   *enclave.Client
+	apiURL   string
+	interval time.Duration
+	snap     *snapshot
+	loading  bool
+	width    int
+}
+
+// RunDashboard opens a full-screen overview of the server that refreshes
+// every interval.
+func RunDashboard(
+	ctx context.Context,
+	c *enclave.Client,
+	apiURL string,
+	interval time.Duration,
+) error {
+	m := dashboardModel{
+		ctx:      ctx,
+		client:   c,
+		apiURL:   apiURL,
+		interval: interval,
+		loading:  true,
+	}
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithContext(ctx)}
+	if styles.Plain {
+		opts = append(opts, tea.WithOutput(&styles.PlainFile{File: os.Stdout}))
+	}
+	if _, err := tea.NewProgram(m, opts...).Run(); err != nil {
+		return fmt.Errorf("dashboard: %w", err)
+	}
+
+	return nil
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return m.load()
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", keyCtrlC, "esc":
+			return m, tea.Quit
+		case "r":
+			if !m.loading {
+				m.loading = true
+
+				return m, m.load()
+			}
+		}
+	case snapshotMsg:
+		m.snap = &msg.snap
+		m.loading = false
+
+		return m, tea.Tick(
+			m.interval,
+			func(time.Time) tea.Msg { return refreshTickMsg{} },
+		)
+	case refreshTickMsg:
+		if !m.loading {
+			m.loading = true
+
+			return m, m.load()
+		}
+	}
+
+	return m, nil
+}
+
+func (m dashboardModel) View() string {
+	var b strings.Builder
+	status := "loading…"
+	if m.snap != nil {
+		status = "updated " + m.snap.at.Format(time.TimeOnly)
+		if m.loading {
+			status += ", refreshing…"
+		}
+	}
+	b.WriteString(
+		styles.TitleStyle.Render("Enclave "+m.apiURL) + "  " +
+			styles.MutedStyle.Render(status) + "\n\n",
+	)
+	if m.snap == nil {
+		return b.String()
+	}
+
+	colWidth := max(36, m.width/2-2)
+	panel := func(title, body string) string {
+		return styles.BorderStyle.Width(colWidth).Render(
+			styles.TitleStyle.Render(title) + "\n" + body,
+		)
+	}
+	s := m.snap
+	left := lipgloss.JoinVertical(
+		lipgloss.Left,
+		panel("Server", healthBody(s)),
+		panel("Access", rbacBody(s)),
+	)
+	right := lipgloss.JoinVertical(
+		lipgloss.Left,
+		panel("Artifacts", artifactBody(s)),
+		panel("Tasks", taskBody(s)),
+	)
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, left, right) + "\n")
+
+	desc := lipgloss.NewStyle().Foreground(styles.ColorSlateDark)
+	b.WriteString(
+		styles.HelpKeyStyle.Render("r") + desc.Render(" refresh   ") +
+			styles.HelpKeyStyle.Render("q") + desc.Render(" quit"),
+	)
+
+	return b.String()
+}
+
+func (m dashboardModel) load() tea.Cmd {
+	ctx, c := m.ctx, m.client
+
+	return func() tea.Msg {
+		return snapshotMsg{snap: fetchSnapshot(ctx, c)}
+	}
+}
+
+// fetchSnapshot loads all dashboard sections.
+func fetchSnapshot(ctx context.Context, c *enclave.Client) snapshot {
+	s := snapshot{at: time.Now()}
+
+	start := time.Now()
+	_, s.healthErr = c.GetMe(ctx)
+	s.latency = time.Since(start)
+
+	var err error
+	if s.users, err = count(c.ListUsers(ctx)); err != nil {
+		s.rbacErr = err
+	} else if s.roles, err = count(c.ListRoles(ctx)); err != nil {
+		s.rbacErr = err
+	} else if s.resourceGroups, err = count(c.ListResourceGroups(ctx)); err != nil {
+		s.rbacErr = err
+	} else if s.policies, err = count(c.ListPolicies(ctx)); err != nil {
+		s.rbacErr = err
+	}
+
+	s.namespaces, s.artifacts, s.recent, s.artifactErr = artifactStats(ctx, c)
+	s.taskStates, s.failed, s.taskErr = taskStats(ctx, c)
+
+	return s
+}
+
+// artifactStats counts namespaces and artifacts and returns the most recently
+// uploaded artifacts.
+func artifactStats(
+	ctx context.Context,
+	c *enclave.Client,
+) (namespaces, artifacts int, recent []enclave.Artifact, err error) {
+	nss, err := enclave.Collect(c.ListArtifactNamespaces(ctx))
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	for _, ns := range nss {
+		items, err := enclave.Collect(c.ListArtifacts(ctx, ns.Namespace))
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		recent = append(recent, items...)
+	}
+	artifacts = len(recent)
+	slices.SortFunc(recent, func(a, b enclave.Artifact) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return len(nss), artifacts, recent[:min(len(recent), recentLimit)], nil
+}
+
+// taskStats counts tasks by state and returns the latest failed tasks.
+func taskStats(
+	ctx context.Context,
+	c *enclave.Client,
+) (map[string]int, []enclave.Task, error) {
+	states := map[string]int{}
+	var failed []enclave.Task
+	for t, err := range c.ListTasks(ctx) {
+		if err != nil {
+			return nil, nil, err
+		}
+		states[t.Status.State]++
+		if t.Status.State == "failed" || t.Status.State == "error" {
+			failed = append(failed, t)
+		}
+	}
+	slices.SortFunc(failed, func(a, b enclave.Task) int {
+		return b.Status.LastFailedAt.Compare(a.Status.LastFailedAt)
+	})
+
+	return states, failed[:min(len(failed), recentLimit)], nil
+}
+
+func healthBody(s *snapshot) string {
+	if s.healthErr != nil {
+		return styles.ErrorStyle.Render("unreachable: " + s.healthErr.Error())
+	}
+
+	return fmt.Sprintf(
+		"reachable, %s round trip",
+		s.latency.Round(time.Millisecond),
+	)
+}
+
+func rbacBody(s *snapshot) string {
+	if s.rbacErr != nil {
+		return styles.ErrorStyle.Render(s.rbacErr.Error())
+	}
+
+	return fmt.Sprintf(
+		"%d users\n%d roles\n%d resource groups\n%d policies",
+		s.users,
+		s.roles,
+		s.resourceGroups,
+		s.policies,
+	)
+}
+
+func artifactBody(s *snapshot) string {
+	if s.artifactErr != nil {
+		return styles.ErrorStyle.Render(s.artifactErr.Error())
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d artifacts in %d namespaces", s.artifacts, s.namespaces)
+	if len(s.recent) > 0 {
+		b.WriteString("\n" + styles.MutedStyle.Render("Recent uploads:"))
+	}
+	for _, a := range s.recent {
+		fmt.Fprintf(
+			&b,
+			"\n  %s/%s  %s",
+			a.Namespace,
+			a.Name,
+			styles.MutedStyle.Render(a.CreatedAt.Local().Format(time.DateTime)),
+		)
+	}
+
+	return b.String()
+}
+
+func taskBody(s *snapshot) string {
+	if s.taskErr != nil {
+		return styles.ErrorStyle.Render(s.taskErr.Error())
+	}
+	if len(s.taskStates) == 0 {
+		return styles.MutedStyle.Render("No tasks.")
+	}
+	states := make([]string, 0, len(s.taskStates))
+	for state := range s.taskStates {
+		states = append(states, state)
+	}
+	slices.Sort(states)
+
+	var b strings.Builder
+	for i, state := range states {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(
+			&b,
+			"%4d  %s",
+			s.taskStates[state],
+			styles.TaskStateBadge(state),
+		)
+	}
+	if len(s.failed) > 0 {
+		b.WriteString("\n" + styles.MutedStyle.Render("Recent failures:"))
+	}
+	for i := range s.failed {
+		t := &s.failed[i]
+		fmt.Fprintf(
+			&b,
+			"\n  %s  %s",
+			truncate(t.ID, 12),
+			styles.ErrorStyle.Render(truncate(t.Status.LastError, 40)),
+		)
+	}
+
+	return b.String()
+}
+
+// count drains seq and returns the number of items.
+func count[T any](seq iter.Seq2[T, error]) (int, error) {
+	n := 0
+	for _, err := range seq {
+		if err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
@@ -10,16 +10,33 @@ import (
 
 // logoSeg is a colored text segment.
 type logoSeg struct {
-	text  string
-	style lipgloss.Style
+	text string
+	tone logoTone
 }
 
-var (
-	styleLogoHi  = lipgloss.NewStyle().Foreground(styles.ColorPrimaryGreen)
-	styleLogoLo  = lipgloss.NewStyle().Foreground(styles.ColorDarkGreen)
-	styleLogoDim = lipgloss.NewStyle().Foreground(styles.ColorSlateDark)
+// logoTone selects a logo color. It is resolved at render time so the logo
+// follows the active theme.
+type logoTone int
+
+const (
+	styleLogoDim logoTone = iota
+	styleLogoHi
+	styleLogoLo
 )
 
+func (t logoTone) style() lipgloss.Style {
+	switch t {
+	case styleLogoHi:
+		return lipgloss.NewStyle().Foreground(styles.ColorPrimaryGreen)
+	case styleLogoLo:
+		return lipgloss.NewStyle().Foreground(styles.ColorDarkGreen)
+	case styleLogoDim:
+		return lipgloss.NewStyle().Foreground(styles.ColorSlateDark)
+	default:
+		return lipgloss.NewStyle().Foreground(styles.ColorSlateDark)
+	}
+}
+
 // logoArt defines each line as a slice of colored segments.
 // Spells "ENCL" in a compact ASCII font.
 // Every line renders to exactly 23 visible characters.
@@ -72,7 +89,7 @@ const logoWidth = 23
 func renderLogoLine(segs []logoSeg) string {
 	var sb strings.Builder
 	for i := range segs {
-		sb.WriteString(segs[i].style.Render(segs[i].text))
+		sb.WriteString(segs[i].tone.style().Render(segs[i].text))
 	}
 
 	return sb.String()
@@ -156,10 +173,12 @@ func (h headerPanel) View() string {
 		"",
 	}
 
-	sep := lipgloss.NewStyle().Foreground(styles.ColorDarkGreen).Render("│")
+	sep := lipgloss.NewStyle().
+		Foreground(styles.ColorDarkGreen).
+		Render(styles.BoxVertical)
 	borderLine := lipgloss.NewStyle().
 		Foreground(styles.ColorDarkGreen).
-		Render(strings.Repeat("─", h.width))
+		Render(strings.Repeat(styles.BoxHorizontal, h.width))
 
 	var b strings.Builder
 	b.WriteString(borderLine + "\n")
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"cli/internal/styles"
+	"cli/internal/tui/views"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// logsModel hosts a log viewer as a standalone program.
+type logsModel struct {
+	viewer views.LogViewerModel
+}
+
+// RunLogs opens a full-screen log viewer that loads entries with fetch.
+func RunLogs(title string, fetch views.LogFetchFunc, follow bool) error {
+	m := logsModel{viewer: views.NewLogViewer(title, fetch, follow)}
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if styles.Plain {
+		opts = append(opts, tea.WithOutput(&styles.PlainFile{File: os.Stdout}))
+	}
+	if _, err := tea.NewProgram(m, opts...).Run(); err != nil {
+		return fmt.Errorf("log viewer: %w", err)
+	}
+
+	return nil
+}
+
+func (m logsModel) Init() tea.Cmd {
+	return m.viewer.Init()
+}
+
+func (m logsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.viewer.SetSize(msg.Width, msg.Height)
+
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case keyCtrlC:
+			return m, tea.Quit
+		case "q":
+			if !m.viewer.Filtering() {
+				return m, tea.Quit
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewer, cmd = m.viewer.Update(msg)
+
+	return m, cmd
+}
+
+func (m logsModel) View() string {
+	return m.viewer.View()
+}
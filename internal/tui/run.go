@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"cli/internal/styles"
 	"fmt"
+	"os"
 
 	"github.com/EnclaveRunner/sdk-go/enclave"
 	tea "github.com/charmbracelet/bubbletea"
@@ -15,7 +17,11 @@ func Run(c *enclave.Client) error {
 // RunWithConfig launches the TUI with config info for the header panel.
 func RunWithConfig(c *enclave.Client, apiURL, username, version string) error {
 	m := New(c, apiURL, username, version)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if styles.Plain {
+		opts = append(opts, tea.WithOutput(&styles.PlainFile{File: os.Stdout}))
+	}
+	p := tea.NewProgram(m, opts...)
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("tui: %w", err)
 	}
@@ -1,6 +1,7 @@
 package views
 
 import (
+	"cli/internal/output"
 	"cli/internal/styles"
 	"context"
 	"strings"
@@ -215,7 +216,7 @@ func (m ArtifactsModel) View() string {
 			if len(h) > 16 {
 				h = h[:16]
 			}
-			tags := strings.Join(a.Tags, ", ")
+			tags := strings.Join(output.UserTags(a.Tags), ", ")
 			created := a.CreatedAt.Format("2006-01-02")
 			style := lipgloss.NewStyle().Padding(0, 1)
 			if i == m.Cursor {
@@ -14,6 +14,8 @@ type FormField struct {
 	Label       string
 	Placeholder string
 	Secret      bool
+	// Value pre-fills the input.
+	Value string
 }
 
 // FormModel is a full-screen multi-field input form.
@@ -33,6 +35,7 @@ func NewForm(title string, fields []FormField) FormModel {
 	for i, f := range fields {
 		ti := textinput.New()
 		ti.Placeholder = f.Placeholder
+		ti.SetValue(f.Value)
 		if f.Secret {
 			ti.EchoMode = textinput.EchoPassword
 		}
@@ -0,0 +1,317 @@
+package views
+
+import (
+	"cli/internal/styles"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"charm.land/lipgloss/v2"
+	"github.com/EnclaveRunner/sdk-go/enclave"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// followInterval is how often the log viewer re-fetches while following.
+const followInterval = 2 * time.Second
+
+// LogFetchFunc loads the current log entries.
+type LogFetchFunc func() ([]enclave.TaskLog, error)
+
+// logsFetchedMsg carries the result of a LogFetchFunc.
+type logsFetchedMsg struct {
+	logs []enclave.TaskLog
+	err  error
+}
+
+// followTickMsg triggers a re-fetch while following.
+type followTickMsg struct{}
+
+// LogViewerModel is a full-screen, scrollable log viewer with a regex filter
+// and live follow.
+type LogViewerModel struct {
+	title     string
+	fetch     LogFetchFunc
+	logs      []enclave.TaskLog
+	loaded    bool
+	err       error
+	filter    *regexp.Regexp
+	input     textinput.Model
+	filtering bool
+	inputErr  string
+	follow    bool
+	vp        viewport.Model
+	width     int
+	height    int
+}
+
+// NewLogViewer builds a log viewer that loads entries with fetch. When follow
+// is set, entries are re-fetched periodically and the view sticks to the end.
+func NewLogViewer(
+	title string,
+	fetch LogFetchFunc,
+	follow bool,
+) LogViewerModel {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "regex"
+
+	return LogViewerModel{
+		title:  title,
+		fetch:  fetch,
+		input:  ti,
+		follow: follow,
+		vp:     viewport.New(0, 0),
+	}
+}
+
+// Init starts loading the logs.
+func (m LogViewerModel) Init() tea.Cmd {
+	return m.load()
+}
+
+// SetSize updates the viewport size.
+func (m *LogViewerModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	m.vp.Width = w
+	m.vp.Height = maxInt(1, h-4)
+	m.refresh()
+}
+
+// Filtering reports whether the filter input has focus.
+func (m LogViewerModel) Filtering() bool {
+	return m.filtering
+}
+
+// Update handles loading, follow ticks, filter input, and scrolling.
+func (m LogViewerModel) Update(msg tea.Msg) (LogViewerModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logsFetchedMsg:
+		m.loaded = true
+		m.err = msg.err
+		if msg.err == nil {
+			m.logs = msg.logs
+		}
+		m.refresh()
+		if m.follow {
+			return m, tea.Tick(
+				followInterval,
+				func(time.Time) tea.Msg { return followTickMsg{} },
+			)
+		}
+
+		return m, nil
+	case followTickMsg:
+		if !m.follow {
+			return m, nil
+		}
+
+		return m, m.load()
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		switch msg.String() {
+		case "/":
+			m.filtering = true
+			m.inputErr = ""
+			m.input.Focus()
+
+			return m, textinput.Blink
+		case keyEsc:
+			m.filter = nil
+			m.input.SetValue("")
+			m.refresh()
+
+			return m, nil
+		case "f":
+			m.follow = !m.follow
+			if m.follow {
+				m.vp.GotoBottom()
+
+				return m, m.load()
+			}
+
+			return m, nil
+		case "g", "home":
+			m.vp.GotoTop()
+
+			return m, nil
+		case "G", "end":
+			m.vp.GotoBottom()
+
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.vp, cmd = m.vp.Update(msg)
+
+	return m, cmd
+}
+
+// View renders the title, the visible log lines, and the help bar.
+func (m LogViewerModel) View() string {
+	var b strings.Builder
+	status := fmt.Sprintf("%d/%d", m.visible(), len(m.logs))
+	if m.follow {
+		status += "  following"
+	}
+	b.WriteString(
+		styles.TitleStyle.Render(m.title) + "  " +
+			styles.MutedStyle.Render(status) + "\n\n",
+	)
+
+	if m.loaded {
+		b.WriteString(m.vp.View())
+	} else {
+		b.WriteString(styles.MutedStyle.Render("  Loading logs…"))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case m.filtering:
+		b.WriteString(m.input.View())
+		if m.inputErr != "" {
+			b.WriteString("  " + styles.ErrorStyle.Render(m.inputErr))
+		}
+	case m.err != nil:
+		b.WriteString(
+			styles.ErrorStyle.Render("Error loading logs: " + m.err.Error()),
+		)
+	default:
+		b.WriteString(m.helpBar())
+	}
+
+	return b.String()
+}
+
+func (m LogViewerModel) updateFilter(
+	msg tea.KeyMsg,
+) (LogViewerModel, tea.Cmd) {
+	switch msg.String() {
+	case keyEsc:
+		m.filtering = false
+		m.input.Blur()
+		if m.filter != nil {
+			m.input.SetValue(m.filter.String())
+		} else {
+			m.input.SetValue("")
+		}
+
+		return m, nil
+	case keyEnter:
+		expr := m.input.Value()
+		if expr == "" {
+			m.filter = nil
+		} else {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				m.inputErr = err.Error()
+
+				return m, nil
+			}
+			m.filter = re
+		}
+		m.filtering = false
+		m.input.Blur()
+		m.refresh()
+
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+
+	return m, cmd
+}
+
+func (m LogViewerModel) load() tea.Cmd {
+	fetch := m.fetch
+
+	return func() tea.Msg {
+		logs, err := fetch()
+
+		return logsFetchedMsg{logs: logs, err: err}
+	}
+}
+
+// refresh re-renders the viewport content, keeping the end in view while
+// following.
+func (m *LogViewerModel) refresh() {
+	m.vp.SetContent(m.renderLines())
+	if m.follow {
+		m.vp.GotoBottom()
+	}
+}
+
+func (m LogViewerModel) renderLines() string {
+	if len(m.logs) == 0 {
+		return styles.MutedStyle.Render("No logs.")
+	}
+	var b strings.Builder
+	for _, l := range m.logs {
+		line := logLine(l)
+		if m.filter != nil && !m.filter.MatchString(line) {
+			continue
+		}
+		ts := l.Timestamp.Format("15:04:05.000")
+		b.WriteString(
+			styles.MutedStyle.Render(ts+" ") +
+				logLevelStyle(l.Level).Render(padRight(l.Level, 5)+" ") +
+				styles.MutedStyle.Render("["+l.Issuer+"] ") +
+				m.highlight(l.Message) + "\n",
+		)
+	}
+
+	return b.String()
+}
+
+// visible returns the number of entries matching the filter.
+func (m LogViewerModel) visible() int {
+	if m.filter == nil {
+		return len(m.logs)
+	}
+	n := 0
+	for _, l := range m.logs {
+		if m.filter.MatchString(logLine(l)) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// highlight marks the filter matches in s.
+func (m LogViewerModel) highlight(s string) string {
+	if m.filter == nil {
+		return s
+	}
+
+	return m.filter.ReplaceAllStringFunc(s, func(x string) string {
+		return styles.MatchStyle.Render(x)
+	})
+}
+
+func (m LogViewerModel) helpBar() string {
+	desc := lipgloss.NewStyle().Foreground(styles.ColorSlateDark)
+	follow := " follow   "
+	if m.follow {
+		follow = " stop following   "
+	}
+
+	return styles.HelpKeyStyle.Render("/") + desc.Render(" filter   ") +
+		styles.HelpKeyStyle.Render("esc") + desc.Render(" clear filter   ") +
+		styles.HelpKeyStyle.Render("f") + desc.Render(follow) +
+		styles.HelpKeyStyle.Render("g/G") + desc.Render(" top/bottom   ") +
+		styles.HelpKeyStyle.Render("q") + desc.Render(" quit")
+}
+
+// logLine is the plain text of l that filters are matched against.
+func logLine(l enclave.TaskLog) string {
+	return l.Timestamp.Format(time.RFC3339) + " " + l.Level + " [" +
+		l.Issuer + "] " + l.Message
+}
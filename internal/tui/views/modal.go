@@ -41,7 +41,7 @@ func (m ModalModel) Update(msg tea.Msg) (ModalModel, tea.Cmd) {
 // View renders the modal as a standalone string (to be overlaid by the parent).
 func (m ModalModel) View() string {
 	boxStyle := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(styles.PanelBorder()).
 		BorderForeground(styles.ColorPrimaryGreen).
 		Padding(1, 3)
 
@@ -0,0 +1,93 @@
+package version
+
+import (
+	"cli/internal/i18n"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// checkInterval is how long a remote version lookup is reused.
+	checkInterval = 24 * time.Hour
+	// checkTimeout bounds the lookup so an offline machine is not held up.
+	checkTimeout = 2 * time.Second
+)
+
+// checkState is the cached result of the last remote version lookup.
+type checkState struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Latest    string    `json:"latest"`
+}
+
+// Notify prints a one-line hint to w when a newer release than local is
+// published. The remote version is looked up at most once per
+// checkInterval; failed lookups are cached too, so being offline costs one
+// short timeout a day. All errors are ignored.
+func Notify(ctx context.Context, w io.Writer, local string) {
+	if local == "" {
+		return
+	}
+	path, err := statePath()
+	if err != nil {
+		return
+	}
+
+	state := loadState(path)
+	if time.Since(state.CheckedAt) >= checkInterval {
+		ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		if latest, err := fetchRemote(ctx); err == nil {
+			state.Latest = latest
+		}
+		cancel()
+		state.CheckedAt = time.Now()
+		saveState(path, state)
+	}
+
+	if state.Latest != "" && Compare(local, state.Latest) == -1 {
+		_, _ = fmt.Fprintf(
+			w,
+			i18n.T(
+				"A new version of encl is available: %s (current %s)\n",
+			),
+			state.Latest,
+			local,
+		)
+	}
+}
+
+// statePath returns the file the last lookup is cached in, e.g.
+// ~/.cache/enclave/version-check.json.
+func statePath() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(base, "enclave", "version-check.json"), nil
+}
+
+func loadState(path string) checkState {
+	var state checkState
+	b, err := os.ReadFile(path) // #nosec G304 -- path is under the cache dir
+	if err == nil {
+		_ = json.Unmarshal(b, &state)
+	}
+
+	return state
+}
+
+func saveState(path string, state checkState) {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, b, 0o600)
+}
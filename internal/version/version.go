@@ -1,6 +1,7 @@
 package version
 
 import (
+	"cli/internal/client"
 	"context"
 	"fmt"
 	"io"
@@ -13,9 +14,9 @@ import (
 const RemoteVersionURL = "https://raw.githubusercontent.com/EnclaveRunner/cli/main/Version"
 
 // fetchRemote retrieves the remote Version file contents.
-func fetchRemote() (string, error) {
+func fetchRemote(ctx context.Context) (string, error) {
 	req, err := http.NewRequestWithContext(
-		context.Background(),
+		ctx,
 		http.MethodGet,
 		RemoteVersionURL,
 		http.NoBody,
@@ -23,7 +24,7 @@ func fetchRemote() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := client.StockClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -70,7 +71,7 @@ func normalize(s string) [3]int {
 
 // CheckRemote compares local to remote and returns remote + whether it's newer.
 func CheckRemote(local string) (remote string, newer bool, err error) {
-	r, err := fetchRemote()
+	r, err := fetchRemote(context.Background())
 	if err != nil {
 		return "", false, err
 	}
@@ -0,0 +1,109 @@
+// Package watch re-runs list commands on an interval, redrawing their output
+// in place.
+package watch
+
+import (
+	"bytes"
+	"cli/internal/client"
+	"cli/internal/history"
+	"cli/internal/i18n"
+	"cli/internal/styles"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultInterval = 2 * time.Second
+	minInterval     = 500 * time.Millisecond
+
+	// clearScreen moves the cursor home and clears the terminal.
+	clearScreen = "\x1b[H\x1b[2J"
+)
+
+// AddFlag registers --watch on cmd. A bare --watch refreshes every two
+// seconds; a custom interval must be attached with "=", e.g. --watch=10s.
+func AddFlag(cmd *cobra.Command) {
+	cmd.Flags().Duration(
+		"watch",
+		0,
+		"Re-fetch and redraw every interval until interrupted (--watch=5s)",
+	)
+	cmd.Flags().Lookup("watch").NoOptDefVal = defaultInterval.String()
+}
+
+// Run calls render once, or repeatedly when --watch is set. In watch mode
+// each refresh clears the screen and replaces the previous output; errors are
+// shown in place of the output and do not stop the loop. The context passed
+// to render is cancelled on interrupt.
+func Run(
+	cmd *cobra.Command,
+	render func(ctx context.Context, w io.Writer) error,
+) error {
+	interval, _ := cmd.Flags().GetDuration("watch")
+	if interval <= 0 {
+		return render(cmd.Context(), os.Stdout)
+	}
+	interval = max(interval, minInterval)
+
+	// Every refresh must reach the server.
+	ctx, stop := signal.NotifyContext(
+		client.WithoutCache(cmd.Context()),
+		os.Interrupt,
+		syscall.SIGTERM,
+	)
+	defer stop()
+
+	// The title leaves out flags and redacts arguments, so secrets such as
+	// passwords are not shown on every refresh.
+	title := fmt.Sprintf(
+		i18n.T("Every %s: %s"),
+		interval,
+		strings.Join(append(
+			[]string{cmd.CommandPath()},
+			history.RedactArgs(cmd, cmd.Flags().Args())...,
+		), " "),
+	)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var buf bytes.Buffer
+		if err := render(ctx, &buf); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			buf.Reset()
+			_, _ = fmt.Fprintln(&buf, i18n.T("Error:"), err)
+		}
+		// Plain mode appends each refresh instead of redrawing the screen.
+		reset := clearScreen
+		if styles.Plain {
+			reset = "\n"
+		}
+		_, err := fmt.Fprintf(
+			os.Stdout,
+			"%s%s  %s\n\n%s",
+			reset,
+			title,
+			time.Now().Format(time.TimeOnly),
+			buf.String(),
+		)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}